@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// neo4jAuth returns the driver credentials and how to describe them in the
+// "Connecting" line. Without a token it is basic auth with user and pass.
+// With --auth-token, scheme "bearer" (the default) sends the token as a
+// bearer token, e.g. an SSO access token; any other scheme is sent as a
+// custom auth scheme with user as the principal and the token as credentials.
+func neo4jAuth(user, pass, token, scheme string) (neo4j.AuthToken, string, error) {
+	if token == "" {
+		if scheme != "" {
+			return neo4j.AuthToken{}, "", fmt.Errorf("--auth-scheme needs --auth-token")
+		}
+		if pass == "" {
+			return neo4j.AuthToken{}, "", fmt.Errorf("missing password: provide -p/--password, --password-file, --password-stdin or NEO4J_PASS")
+		}
+		return neo4j.BasicAuth(user, pass, ""), user, nil
+	}
+	switch scheme = strings.ToLower(strings.TrimSpace(scheme)); scheme {
+	case "", "bearer":
+		return neo4j.BearerAuth(token), "bearer token", nil
+	case "basic":
+		return neo4j.AuthToken{}, "", fmt.Errorf("--auth-scheme basic: use -u/--username and -p/--password instead")
+	default:
+		return neo4j.CustomAuth(scheme, user, token, "", nil), fmt.Sprintf("%s (%s auth)", user, scheme), nil
+	}
+}
+
+// readPasswordFile reads a password from path, trimming surrounding
+// whitespace. Like ssh with private keys, it refuses a file that group or
+// other users can read (not checked on Windows, which has no such bits).
+func readPasswordFile(path string) (string, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if !fi.Mode().IsRegular() {
+		return "", fmt.Errorf("%s is not a regular file", path)
+	}
+	if runtime.GOOS != "windows" && fi.Mode().Perm()&0o077 != 0 {
+		return "", fmt.Errorf("%s is accessible by other users (mode %04o); chmod 600 it", path, fi.Mode().Perm())
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	pass := strings.TrimSpace(string(b))
+	if pass == "" {
+		return "", fmt.Errorf("%s is empty", path)
+	}
+	return pass, nil
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/bakw00ds/goBloodyEll/internal/queries"
+	"github.com/bakw00ds/goBloodyEll/internal/schema"
+)
+
+// checkLabels are the BloodHound node types whose counts the preflight
+// check reports.
+var checkLabels = []string{"User", "Computer", "Group", "Domain", "AZUser", "AZTenant"}
+
+// staleDataAfter is how old the newest data may be before the preflight
+// check and the run warn that it is stale.
+const staleDataAfter = 30 * 24 * time.Hour
+
+type checkRow struct {
+	name, status, detail string
+}
+
+// runCheck is --check: it verifies connectivity, authentication, the
+// database, read access, graph size and data freshness, prints a table to
+// stdout and reports whether nothing failed. Once a step fails the ones
+// depending on it are skipped.
+func runCheck(ctx context.Context, driver neo4j.DriverWithContext, db string, dialect queries.Dialect) bool {
+	var rows []checkRow
+	add := func(name, status, format string, args ...any) {
+		rows = append(rows, checkRow{name, status, fmt.Sprintf(format, args...)})
+	}
+	defer func() {
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "CHECK\tSTATUS\tDETAIL")
+		for _, r := range rows {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", r.name, r.status, r.detail)
+		}
+		tw.Flush()
+	}()
+	skip := func(names ...string) bool {
+		for _, n := range names {
+			add(n, "SKIP", "")
+		}
+		return false
+	}
+
+	if err := driver.VerifyConnectivity(ctx); err != nil {
+		var ne *neo4j.Neo4jError
+		if !errors.As(err, &ne) || !strings.HasPrefix(ne.Code, "Neo.ClientError.Security.") {
+			add("connectivity", "FAIL", "%v", err)
+			return skip("authentication", "database", "read access", "graph size", "data freshness")
+		}
+		add("connectivity", "PASS", "")
+		add("authentication", "FAIL", "%s", ne.Msg)
+		return skip("database", "read access", "graph size", "data freshness")
+	}
+	add("connectivity", "PASS", "")
+	add("authentication", "PASS", "")
+
+	sess := driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: db, AccessMode: neo4j.AccessModeRead})
+	defer sess.Close(ctx)
+	if _, err := neo4j.ExecuteRead(ctx, sess, func(tx neo4j.ManagedTransaction) (any, error) {
+		res, err := tx.Run(ctx, "RETURN 1", nil)
+		if err != nil {
+			return nil, err
+		}
+		return res.Consume(ctx)
+	}); err != nil {
+		add("database", "FAIL", "%s: %v", db, err)
+		return skip("read access", "graph size", "data freshness")
+	}
+	add("database", "PASS", "%s", db)
+
+	srv, err := schema.DescribeServer(ctx, sess, dialect)
+	switch {
+	case err != nil:
+		add("read access", "FAIL", "%v", err)
+		return skip("graph size", "data freshness")
+	case srv.Nodes == 0:
+		add("read access", "WARN", "no nodes visible: empty database or no read privilege on it")
+	default:
+		add("read access", "PASS", "Neo4j %s %s", srv.Version, srv.Edition)
+	}
+
+	var counts []string
+	for _, l := range checkLabels {
+		n, err := schema.CountLabel(ctx, sess, l)
+		if err != nil {
+			add("graph size", "FAIL", "%v", err)
+			return false
+		}
+		if n > 0 {
+			counts = append(counts, fmt.Sprintf("%s=%d", l, n))
+		}
+	}
+	detail := fmt.Sprintf("%d nodes, %d relationships", srv.Nodes, srv.Relationships)
+	if len(counts) > 0 {
+		add("graph size", "PASS", "%s (%s)", detail, strings.Join(counts, " "))
+	} else {
+		add("graph size", "WARN", "%s; no BloodHound nodes", detail)
+	}
+
+	newest, err := schema.NewestData(ctx, sess)
+	switch {
+	case err != nil:
+		add("data freshness", "FAIL", "%v", err)
+		return false
+	case newest.IsZero():
+		add("data freshness", "WARN", "no lastseen, whencreated or lastlogontimestamp to date the collection")
+	case time.Since(newest) > staleDataAfter:
+		add("data freshness", "WARN", "newest data %s (%d days ago)", newest.Format("2006-01-02"), dataAgeDays(newest))
+	default:
+		add("data freshness", "PASS", "newest data %s", newest.Format("2006-01-02"))
+	}
+	return true
+}
+
+// dataAgeDays is how many whole days ago t was.
+func dataAgeDays(t time.Time) int { return int(time.Since(t).Hours() / 24) }
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configFile is the user's connection profiles, by default
+// ~/.config/gobloodyell/config.yaml (the OS config dir elsewhere):
+//
+//	default: lab
+//	profiles:
+//	  lab:
+//	    uri: bolt://10.0.0.9:7687
+//	    user: neo4j
+//	  acme:
+//	    uri: neo4j+s://bh.acme.example:7687
+//	    db: bloodhound
+//	    tls-ca: ~/engagements/acme/ca.pem
+type configFile struct {
+	// Default names the profile used when --conn-profile is not given.
+	Default  string                 `yaml:"default"`
+	Profiles map[string]connProfile `yaml:"profiles"`
+}
+
+// connProfile holds connection settings; each one applies unless its flag
+// is given on the command line.
+type connProfile struct {
+	URI           string `yaml:"uri"`
+	User          string `yaml:"user"`
+	DB            string `yaml:"db"`
+	TLSCA         string `yaml:"tls-ca"`
+	TLSSkipVerify bool   `yaml:"tls-skip-verify"`
+	TLSCert       string `yaml:"tls-cert"`
+	TLSKey        string `yaml:"tls-key"`
+}
+
+// defaultConfigPath is config.yaml in the gobloodyell user config directory.
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "gobloodyell", "config.yaml")
+}
+
+// applyProfile loads profile name (or the file's default profile when name
+// is empty) from the config file at path and sets the flags it covers that
+// were not given explicitly. A missing file is only an error when a profile
+// was asked for.
+func applyProfile(path, name string) error {
+	explicitPath := path != ""
+	if path == "" {
+		path = defaultConfigPath()
+	}
+	if path == "" {
+		return nil
+	}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && name == "" && !explicitPath {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var cf configFile
+	if err := yaml.Unmarshal(b, &cf); err != nil {
+		return fmt.Errorf("parse %s: %w", path, err)
+	}
+	if name == "" {
+		if name = cf.Default; name == "" {
+			return nil
+		}
+	}
+	p, ok := cf.Profiles[name]
+	if !ok {
+		names := make([]string, 0, len(cf.Profiles))
+		for n := range cf.Profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("%s: no profile %q (have: %s)", path, name, strings.Join(names, ", "))
+	}
+
+	set := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	apply := func(v string, names ...string) {
+		if v == "" {
+			return
+		}
+		for _, n := range names {
+			if set[n] {
+				return
+			}
+		}
+		_ = flag.Set(names[0], v)
+	}
+	apply(p.URI, "neo4j-uri", "neo4j-ip")
+	apply(p.User, "username", "u")
+	apply(p.DB, "db")
+	apply(expandHome(p.TLSCA), "tls-ca")
+	if p.TLSSkipVerify {
+		apply(strconv.FormatBool(p.TLSSkipVerify), "tls-skip-verify")
+	}
+	apply(expandHome(p.TLSCert), "tls-cert")
+	apply(expandHome(p.TLSKey), "tls-key")
+	fmt.Fprintf(os.Stderr, "[+] Using connection profile %q from %s\n", name, path)
+	return nil
+}
+
+// expandHome replaces a leading "~/" with the user's home directory.
+func expandHome(p string) string {
+	if rest, ok := strings.CutPrefix(p, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return p
+}
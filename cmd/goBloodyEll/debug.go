@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	_ "net/http/pprof" // registers /debug/pprof on the default mux
+	"os"
+	"runtime"
+	"time"
+)
+
+// startPprof serves net/http/pprof on addr for profiling long exports.
+func startPprof(addr string) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		fatalf("invalid --pprof: %v", err)
+	}
+	if host, _, _ := net.SplitHostPort(ln.Addr().String()); !net.ParseIP(host).IsLoopback() {
+		fmt.Fprintf(os.Stderr, "[!] pprof is listening on %s, reachable from the network; prefer 127.0.0.1\n", ln.Addr())
+	}
+	fmt.Fprintf(os.Stderr, "[+] pprof on http://%s/debug/pprof/\n", ln.Addr())
+	go func() { _ = http.Serve(ln, nil) }()
+}
+
+// startMemStats logs heap and GC figures every interval; the returned func
+// stops it after a final line.
+func startMemStats(interval time.Duration) func() {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-stop:
+				logMemStats()
+				return
+			case <-t.C:
+				logMemStats()
+			}
+		}
+	}()
+	return func() {
+		close(stop)
+		<-done
+	}
+}
+
+func logMemStats() {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	fmt.Fprintf(os.Stderr, "[+] mem: heap %d MiB (objects %d), sys %d MiB, total alloc %d MiB, gc %d, goroutines %d\n",
+		m.HeapAlloc>>20, m.HeapObjects, m.Sys>>20, m.TotalAlloc>>20, m.NumGC, runtime.NumGoroutine())
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// loadEnvFile sets the variables in a dotenv file: KEY=VALUE lines, an
+// optional "export " prefix, '#' comments and single- or double-quoted
+// values. Variables already in the environment win, so the file only
+// supplies defaults. With path empty it reads ./.env if there is one.
+func loadEnvFile(path string) error {
+	explicit := path != ""
+	if !explicit {
+		path = ".env"
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	n := 0
+	sc := bufio.NewScanner(f)
+	for line := 1; sc.Scan(); line++ {
+		s := strings.TrimSpace(sc.Text())
+		if s == "" || strings.HasPrefix(s, "#") {
+			continue
+		}
+		s = strings.TrimPrefix(s, "export ")
+		key, val, ok := strings.Cut(s, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return fmt.Errorf("%s:%d: expected KEY=VALUE", path, line)
+		}
+		val = strings.TrimSpace(val)
+		if len(val) >= 2 && (val[0] == '"' || val[0] == '\'') && val[len(val)-1] == val[0] {
+			val = val[1 : len(val)-1]
+		} else if i := strings.Index(val, " #"); i >= 0 {
+			val = strings.TrimSpace(val[:i])
+		}
+		if _, set := os.LookupEnv(key); set {
+			continue
+		}
+		if err := os.Setenv(key, val); err != nil {
+			return fmt.Errorf("%s:%d: %v", path, line, err)
+		}
+		n++
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "[+] Loaded %d variable(s) from %s\n", n, path)
+	return nil
+}
+
+// envPrefix prefixes the environment variable for each flag:
+// --neo4j-uri is GOBLOODYELL_NEO4J_URI, --parallel is GOBLOODYELL_PARALLEL.
+const envPrefix = "GOBLOODYELL_"
+
+// flagEnvName is the environment variable for flag name.
+func flagEnvName(name string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// flagsFromEnv sets every flag not given on the command line from its
+// GOBLOODYELL_* variable. An alias (-u for --username) given on the command
+// line counts for the long name too. Single-letter aliases have no variable
+// of their own, and --jobs has none because its child runs inherit the
+// environment.
+func flagsFromEnv() error {
+	given := map[flag.Value]bool{}
+	flag.Visit(func(f *flag.Flag) { given[f.Value] = true })
+	var err error
+	flag.VisitAll(func(f *flag.Flag) {
+		if err != nil || len(f.Name) == 1 || f.Name == "jobs" || given[f.Value] {
+			return
+		}
+		v, ok := os.LookupEnv(flagEnvName(f.Name))
+		if !ok {
+			return
+		}
+		if e := flag.Set(f.Name, v); e != nil {
+			err = fmt.Errorf("%s: %v", flagEnvName(f.Name), e)
+		}
+	})
+	return err
+}
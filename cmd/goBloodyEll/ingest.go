@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/bakw00ds/goBloodyEll/internal/ingest"
+	"github.com/bakw00ds/goBloodyEll/internal/queries"
+)
+
+// runIngest is the ingest subcommand: it loads SharpHound zips or
+// collection .json files into db.
+func runIngest(ctx context.Context, driver neo4j.DriverWithContext, db string, dialect queries.Dialect, files []string) error {
+	if len(files) == 0 {
+		return fmt.Errorf("ingest needs SharpHound .zip or .json files")
+	}
+	sess := driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: db, AccessMode: neo4j.AccessModeWrite})
+	defer sess.Close(ctx)
+	w := ingest.NewWriter(ctx, sess, ingest.DefaultBatch)
+	if err := w.Prepare(dialect); err != nil {
+		return fmt.Errorf("create objectid index: %v", err)
+	}
+	for _, path := range files {
+		fmt.Fprintf(os.Stderr, "[+] Ingesting %s\n", path)
+		results, err := ingest.LoadFile(path, w)
+		for _, r := range results {
+			if r.Skipped {
+				fmt.Fprintf(os.Stderr, "[!]   %s: %s data is not supported; skipped\n", r.Name, r.Type)
+				continue
+			}
+			fmt.Fprintf(os.Stderr, "[+]   %s: %d %s\n", r.Name, r.Objects, r.Type)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "[+] Merged %d nodes and %d relationships into %s\n", w.Nodes, w.Edges, db)
+	return nil
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// jobsFile is the --jobs document: a list of runs, each described either as a
+// map of flag name -> value or as a raw argument list.
+//
+//	parallel: 2
+//	jobs:
+//	  - name: corp
+//	    flags: {neo4j-uri: "bolt://10.0.0.5:7687", category: AD, xlsx: corp.xlsx}
+//	  - name: lab
+//	    args: ["--neo4j-ip", "10.0.0.9", "-x", "lab.xlsx"]
+type jobsFile struct {
+	Parallel int       `yaml:"parallel"`
+	Jobs     []jobSpec `yaml:"jobs"`
+}
+
+type jobSpec struct {
+	Name  string         `yaml:"name"`
+	Flags map[string]any `yaml:"flags"`
+	Args  []string       `yaml:"args"`
+}
+
+func loadJobs(path string) (jobsFile, error) {
+	var jf jobsFile
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return jf, err
+	}
+	if err := yaml.Unmarshal(b, &jf); err != nil {
+		return jf, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if len(jf.Jobs) == 0 {
+		return jf, fmt.Errorf("%s: no jobs defined", path)
+	}
+	for i := range jf.Jobs {
+		if strings.TrimSpace(jf.Jobs[i].Name) == "" {
+			jf.Jobs[i].Name = fmt.Sprintf("job%d", i+1)
+		}
+	}
+	return jf, nil
+}
+
+// args renders the job as command-line arguments; flags are sorted for a stable order.
+func (j jobSpec) args() []string {
+	keys := make([]string, 0, len(j.Flags))
+	for k := range j.Flags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out := make([]string, 0, len(keys)+len(j.Args))
+	for _, k := range keys {
+		name := "--" + strings.TrimLeft(k, "-")
+		switch v := j.Flags[k].(type) {
+		case bool:
+			out = append(out, fmt.Sprintf("%s=%t", name, v))
+		case nil:
+			out = append(out, name)
+		default:
+			out = append(out, fmt.Sprintf("%s=%v", name, v))
+		}
+	}
+	return append(out, j.Args...)
+}
+
+// runJobs executes every job as a child process of this binary and returns the
+// combined exit status: 0 if all jobs succeeded, otherwise the highest child code.
+func runJobs(path string, parallelOverride int) int {
+	jf, err := loadJobs(path)
+	if err != nil {
+		fatalf("invalid --jobs: %v", err)
+	}
+	parallel := jf.Parallel
+	if parallelOverride > 0 {
+		parallel = parallelOverride
+	}
+	return runJobSpecs(jf.Jobs, parallel, nil)
+}
+
+// runJobSpecs runs jobs as child processes, at most parallel at a time, with
+// env as their environment (nil inherits this one's), and returns the
+// combined exit status.
+func runJobSpecs(jobs []jobSpec, parallel int, env []string) int {
+	self, err := os.Executable()
+	if err != nil {
+		fatalf("cannot locate own executable: %v", err)
+	}
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	codes := make([]int, len(jobs))
+	sem := make(chan struct{}, parallel)
+	var mu sync.Mutex // serializes prefixed log lines and job stdout
+	var wg sync.WaitGroup
+	for i, job := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			fmt.Fprintf(os.Stderr, "[jobs] starting %s\n", job.Name)
+			cmd := exec.Command(self, job.args()...)
+			cmd.Env = env
+			stdout, _ := cmd.StdoutPipe()
+			stderr, _ := cmd.StderrPipe()
+			if err := cmd.Start(); err != nil {
+				fmt.Fprintf(os.Stderr, "[jobs] %s: %v\n", job.Name, err)
+				codes[i] = 1
+				return
+			}
+			// Only log lines are prefixed; a job's stdout (a report written
+			// without an output file) is held back and written whole once the
+			// job ends, so it stays parseable and does not interleave.
+			var out bytes.Buffer
+			var pw sync.WaitGroup
+			pw.Add(2)
+			go func() { defer pw.Done(); io.Copy(&out, stdout) }()
+			go func() { defer pw.Done(); prefixLines(&mu, os.Stderr, stderr, job.Name) }()
+			pw.Wait()
+
+			err := cmd.Wait()
+			var exitErr *exec.ExitError
+			switch {
+			case err == nil:
+			case errors.As(err, &exitErr):
+				// ExitCode is -1 for a child killed by a signal.
+				codes[i] = max(exitErr.ExitCode(), 1)
+			default:
+				codes[i] = 1
+			}
+			mu.Lock()
+			os.Stdout.Write(out.Bytes())
+			mu.Unlock()
+			fmt.Fprintf(os.Stderr, "[jobs] finished %s (exit %d)\n", job.Name, codes[i])
+		}()
+	}
+	wg.Wait()
+
+	worst := 0
+	failed := 0
+	for _, c := range codes {
+		if c != 0 {
+			failed++
+		}
+		if c > worst {
+			worst = c
+		}
+	}
+	fmt.Fprintf(os.Stderr, "[jobs] %d/%d job(s) succeeded\n", len(codes)-failed, len(codes))
+	return worst
+}
+
+func prefixLines(mu *sync.Mutex, w io.Writer, r io.Reader, name string) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 16<<20)
+	for sc.Scan() {
+		mu.Lock()
+		fmt.Fprintf(w, "[%s] %s\n", name, sc.Text())
+		mu.Unlock()
+	}
+}
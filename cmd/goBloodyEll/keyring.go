@@ -0,0 +1,21 @@
+package main
+
+import (
+	"errors"
+	"net/url"
+)
+
+// keyringService names goBloodyEll's entries in the OS keychain.
+const keyringService = "gobloodyell"
+
+// errNoKeyringEntry is returned by keyringGet when nothing is stored.
+var errNoKeyringEntry = errors.New("no password stored in the OS keyring")
+
+// keyringAccount keys a stored password by user and server, so one user's
+// passwords for different customer databases don't collide.
+func keyringAccount(user, uri string) string {
+	if u, err := url.Parse(uri); err == nil && u.Host != "" {
+		uri = u.Host
+	}
+	return user + "@" + uri
+}
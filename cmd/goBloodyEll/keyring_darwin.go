@@ -0,0 +1,36 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// keyringGet reads the password from the login keychain.
+func keyringGet(account string) (string, error) {
+	out, err := exec.Command("security", "find-generic-password", "-s", keyringService, "-a", account, "-w").Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok && ee.ExitCode() == 44 {
+			return "", errNoKeyringEntry
+		}
+		return "", fmt.Errorf("security find-generic-password: %v", err)
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
+
+// keyringSet stores the password in the login keychain, replacing any
+// earlier one. The command goes through "security -i" on stdin so the
+// password never appears in the process list.
+func keyringSet(account, secret string) error {
+	q := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	cmd := fmt.Sprintf("add-generic-password -U -s \"%s\" -a \"%s\" -w \"%s\"\n", keyringService, q.Replace(account), q.Replace(secret))
+	c := exec.Command("security", "-i")
+	c.Stdin = strings.NewReader(cmd)
+	var stderr bytes.Buffer
+	c.Stderr = &stderr
+	if err := c.Run(); err != nil || stderr.Len() > 0 {
+		return fmt.Errorf("security add-generic-password: %v %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
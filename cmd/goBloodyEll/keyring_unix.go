@@ -0,0 +1,39 @@
+//go:build !darwin && !windows
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// keyringGet looks the password up through the Secret Service (GNOME
+// Keyring, KWallet) with secret-tool from libsecret.
+func keyringGet(account string) (string, error) {
+	var stderr bytes.Buffer
+	c := exec.Command("secret-tool", "lookup", "service", keyringService, "account", account)
+	c.Stderr = &stderr
+	out, err := c.Output()
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); ok && stderr.Len() == 0 {
+			return "", errNoKeyringEntry
+		}
+		return "", fmt.Errorf("secret-tool lookup: %v %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
+
+// keyringSet stores the password in the Secret Service; secret-tool reads
+// it from stdin, keeping it out of the process list.
+func keyringSet(account, secret string) error {
+	var stderr bytes.Buffer
+	c := exec.Command("secret-tool", "store", "--label", "goBloodyEll Neo4j "+account, "service", keyringService, "account", account)
+	c.Stdin = strings.NewReader(secret)
+	c.Stderr = &stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("secret-tool store: %v %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
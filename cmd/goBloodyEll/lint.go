@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/bakw00ds/goBloodyEll/internal/queries"
+	"github.com/bakw00ds/goBloodyEll/internal/schema"
+)
+
+// lintQueries returns the query definitions lint checks: every built-in
+// query, then those of the given pack files.
+func lintQueries(packs []string) ([]queries.Query, error) {
+	all := append([]queries.Query{}, queries.FindingQueries...)
+	all = append(all, queries.InfoQueries...)
+	all = append(all, queries.MaintenanceQueries...)
+	pack, err := loadPacks(packs)
+	return append(all, pack...), err
+}
+
+// loadPacks reads the queries of the given pack files, in order.
+func loadPacks(paths []string) ([]queries.Query, error) {
+	var out []queries.Query
+	for _, p := range paths {
+		qs, err := queries.LoadPack(p)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, qs...)
+	}
+	return out, nil
+}
+
+// packProblems lints the queries of --pack files before a run: the checks
+// of queries.Lint, plus IDs that clash with a built-in query.
+func packProblems(pack []queries.Query) []queries.Problem {
+	problems := queries.Lint(pack)
+	builtin, _ := lintQueries(nil)
+	for _, q := range pack {
+		if _, dup := findQueryByID(builtin, q.ID); dup {
+			problems = append(problems, queries.Problem{ID: q.ID, Msg: "duplicates a built-in query ID"})
+		}
+	}
+	return problems
+}
+
+// runLint is the lint subcommand: it prints the problems queries.Lint finds
+// in qs and, given a schema snapshot, the queries the schema would skip. It
+// returns the exit code: 1 when a query definition has a problem. Schema
+// skips are reported only, as they depend on the data.
+func runLint(qs []queries.Query, snap *schema.Snapshot) int {
+	problems := append(queries.Lint(qs), undeclaredProps(qs)...)
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "QUERY\tCHECK\tDETAIL")
+	for _, p := range problems {
+		fmt.Fprintf(tw, "%s\tpack\t%s\n", p.ID, p.Msg)
+	}
+	skips := 0
+	if snap != nil {
+		presence := schema.PresenceFromSummary(snap.Summary)
+		presence.Props = snap.Props
+		tz := snap.TierZero()
+		adapted := make([]queries.Query, len(qs))
+		for i, q := range qs {
+			adapted[i] = tz.Apply(q)
+		}
+		predicted := predictSkips(adapted, presence)
+		for _, q := range qs {
+			if why, skipped := predicted[q.ID]; skipped {
+				fmt.Fprintf(tw, "%s\tschema\t%s\n", q.ID, why)
+			}
+		}
+		skips = len(predicted)
+	}
+	tw.Flush()
+	fmt.Fprintf(os.Stderr, "[+] Linted %d queries: %d problem(s)", len(qs), len(problems))
+	if snap != nil {
+		fmt.Fprintf(os.Stderr, ", %d skipped by the schema", skips)
+	}
+	fmt.Fprintln(os.Stderr)
+	if len(problems) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// lintSnapshot discovers what runLint checks the packs against: labels,
+// relationship types, the Tier Zero convention and the presence of the
+// properties the packs filter on.
+func lintSnapshot(ctx context.Context, driver neo4j.DriverWithContext, db string, dialect queries.Dialect, qs []queries.Query) (schema.Snapshot, error) {
+	sess := driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: db, AccessMode: neo4j.AccessModeRead})
+	defer sess.Close(ctx)
+	sum, err := schema.Discover(ctx, sess, dialect)
+	if err != nil {
+		return schema.Snapshot{}, err
+	}
+	snap := schema.Snapshot{Summary: sum}
+	tz, err := schema.DetectTierZero(ctx, sess)
+	if err != nil {
+		return snap, err
+	}
+	snap.SystemTags = tz == queries.SystemTags
+	var refs []schema.PropRef
+	for _, q := range qs {
+		refs = append(refs, schema.RequiredProps(tz.Apply(q))...)
+	}
+	snap.Props, err = schema.ProbeProps(ctx, sess, schema.PresenceFromSummary(sum), refs)
+	return snap, err
+}
+
+// undeclaredProps flags properties a query filters on (see
+// schema.FilterProps) that are missing from its RequiresProps, so the
+// declarations keep up with edits to the Cypher.
+func undeclaredProps(qs []queries.Query) []queries.Problem {
+	var out []queries.Problem
+	for _, q := range qs {
+		declared := map[schema.PropRef]bool{}
+		for _, r := range schema.RequiredProps(q) {
+			declared[r] = true
+		}
+		for _, r := range schema.FilterProps(q.Cypher) {
+			if !declared[r] {
+				out = append(out, queries.Problem{ID: q.ID, Msg: fmt.Sprintf("filters on %s.%s, which is not in RequiresProps", r.Label, r.Prop)})
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/config"
+)
+
+// poolOpts are the driver connection pool flags. The defaults match the
+// driver's own.
+type poolOpts struct {
+	Size           int
+	AcquireTimeout time.Duration
+	Lifetime       time.Duration
+	// Liveness is how long a connection may sit idle before it is checked
+	// on reuse; negative leaves the driver default (no check).
+	Liveness  time.Duration
+	KeepAlive bool
+}
+
+// option validates p against the worker count and returns the driver
+// configuration for it.
+func (p poolOpts) option(parallel int) (func(*config.Config), error) {
+	if p.Size <= 0 {
+		return nil, fmt.Errorf("--pool-size must be at least 1")
+	}
+	if p.Size < parallel {
+		fmt.Fprintf(os.Stderr, "[!] --pool-size %d is below --parallel %d; workers will wait for connections (up to --acquire-timeout %s)\n", p.Size, parallel, p.AcquireTimeout)
+	}
+	return func(c *config.Config) {
+		c.MaxConnectionPoolSize = p.Size
+		c.ConnectionAcquisitionTimeout = p.AcquireTimeout
+		c.MaxConnectionLifetime = p.Lifetime
+		c.SocketKeepalive = p.KeepAlive
+		if p.Liveness >= 0 {
+			c.ConnectionLivenessCheckTimeout = p.Liveness
+		}
+	}, nil
+}
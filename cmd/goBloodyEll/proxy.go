@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/net/proxy"
+)
+
+// proxyDialTimeout bounds reaching the server through the proxy, including
+// the proxy handshake and TLS.
+const proxyDialTimeout = 30 * time.Second
+
+type dialFunc func(ctx context.Context, addr string) (net.Conn, error)
+
+// proxyDial returns a dial function that connects through the proxy at raw:
+// socks5:// (host names resolved locally), socks5h:// (resolved by the
+// proxy) or http:// (CONNECT), with optional user:pass@ credentials.
+func proxyDial(raw string) (dialFunc, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --proxy: %v", err)
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid --proxy %q: want scheme://host:port", raw)
+	}
+	direct := &net.Dialer{Timeout: proxyDialTimeout}
+	switch u.Scheme {
+	case "socks5", "socks5h":
+		var auth *proxy.Auth
+		if u.User != nil {
+			pass, _ := u.User.Password()
+			auth = &proxy.Auth{User: u.User.Username(), Password: pass}
+		}
+		d, err := proxy.SOCKS5("tcp", hostPort(u, "1080"), auth, direct)
+		if err != nil {
+			return nil, fmt.Errorf("--proxy: %v", err)
+		}
+		cd := d.(proxy.ContextDialer)
+		resolveLocally := u.Scheme == "socks5"
+		return func(ctx context.Context, addr string) (net.Conn, error) {
+			if resolveLocally {
+				host, port, _ := net.SplitHostPort(addr)
+				if net.ParseIP(host) == nil {
+					ips, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+					if err != nil {
+						return nil, err
+					}
+					addr = net.JoinHostPort(ips[0].IP.String(), port)
+				}
+			}
+			return cd.DialContext(ctx, "tcp", addr)
+		}, nil
+	case "http":
+		paddr := hostPort(u, "8080")
+		return func(ctx context.Context, addr string) (net.Conn, error) {
+			return httpConnect(ctx, direct, paddr, u.User, addr)
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported --proxy scheme %q (use socks5, socks5h or http)", u.Scheme)
+}
+
+func hostPort(u *url.URL, defaultPort string) string {
+	port := u.Port()
+	if port == "" {
+		port = defaultPort
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// httpConnect opens a tunnel to addr with an HTTP CONNECT request to the
+// proxy at paddr.
+func httpConnect(ctx context.Context, d *net.Dialer, paddr string, user *url.Userinfo, addr string) (net.Conn, error) {
+	c, err := d.DialContext(ctx, "tcp", paddr)
+	if err != nil {
+		return nil, err
+	}
+	if dl, ok := ctx.Deadline(); ok {
+		_ = c.SetDeadline(dl)
+	}
+	req := fmt.Sprintf("CONNECT %s HTTP/1.1\r\nHost: %s\r\n", addr, addr)
+	if user != nil {
+		pass, _ := user.Password()
+		req += "Proxy-Authorization: Basic " + base64.StdEncoding.EncodeToString([]byte(user.Username()+":"+pass)) + "\r\n"
+	}
+	if _, err := io.WriteString(c, req+"\r\n"); err != nil {
+		c.Close()
+		return nil, err
+	}
+	br := bufio.NewReader(c)
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("proxy CONNECT: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		c.Close()
+		return nil, fmt.Errorf("proxy CONNECT %s: %s", addr, resp.Status)
+	}
+	// Bolt and TLS clients speak first, so the server cannot have sent
+	// anything past the proxy's response yet.
+	if br.Buffered() > 0 {
+		c.Close()
+		return nil, fmt.Errorf("proxy CONNECT %s: unexpected data after the response", addr)
+	}
+	_ = c.SetDeadline(time.Time{})
+	return c, nil
+}
+
+// proxyURI starts a loopback listener that forwards each connection through
+// dial to the server in uri and returns the bolt:// URI of the listener; the
+// driver has no dialer hook of its own. For +s/+ssc schemes the forwarder
+// does the TLS handshake with the real host name, so certificates are checked
+// against the name they were issued for. Routing (neo4j://) cannot go through
+// a proxy: the driver would dial the cluster members in the routing table
+// directly.
+func proxyURI(uri string, dial dialFunc, t tlsOpts) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", fmt.Errorf("invalid --neo4j-uri: %v", err)
+	}
+	base, sec, _ := strings.Cut(u.Scheme, "+")
+	if base == "neo4j" {
+		fmt.Fprintf(os.Stderr, "[!] Routing is not available through --proxy; using a direct bolt connection to %s\n", u.Host)
+	}
+	addr := hostPort(u, "7687")
+	var tc *tls.Config
+	if sec != "" {
+		tc = &tls.Config{ServerName: u.Hostname(), MinVersion: tls.VersionTLS12, InsecureSkipVerify: sec == "ssc"}
+		if t.CA != "" {
+			if tc.RootCAs, err = caPool(t.CA); err != nil {
+				return "", err
+			}
+		}
+		if t.Cert != "" {
+			cert, err := tls.LoadX509KeyPair(t.Cert, t.Key)
+			if err != nil {
+				return "", fmt.Errorf("--tls-cert/--tls-key: %v", err)
+			}
+			tc.Certificates = []tls.Certificate{cert}
+		}
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", fmt.Errorf("--proxy: %v", err)
+	}
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go forward(c, dial, addr, tc)
+		}
+	}()
+	return "bolt://" + ln.Addr().String(), nil
+}
+
+// forward connects to addr through dial (wrapped in TLS when tc is set) and
+// copies bytes both ways until either side closes.
+func forward(c net.Conn, dial dialFunc, addr string, tc *tls.Config) {
+	defer c.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), proxyDialTimeout)
+	up, err := dial(ctx, addr)
+	if err == nil && tc != nil {
+		tlsConn := tls.Client(up, tc)
+		if err = tlsConn.HandshakeContext(ctx); err != nil {
+			up.Close()
+		}
+		up = tlsConn
+	}
+	cancel()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[!] --proxy: %s: %v\n", addr, err)
+		return
+	}
+	defer up.Close()
+	done := make(chan struct{}, 2)
+	go func() { _, _ = io.Copy(up, c); done <- struct{}{} }()
+	go func() { _, _ = io.Copy(c, up); done <- struct{}{} }()
+	<-done
+}
+
+// redactProxy hides the password of a proxy URL for display.
+func redactProxy(raw string) string {
+	if u, err := url.Parse(raw); err == nil {
+		return u.Redacted()
+	}
+	return raw
+}
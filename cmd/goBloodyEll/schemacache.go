@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/bakw00ds/goBloodyEll/internal/schema"
+)
+
+// loadSchema returns the schema snapshot to use instead of discovery, if
+// any: --schema-from unconditionally, or --schema-cache when it was taken
+// from the same database (key) within ttl (0 = never expires).
+func loadSchema(from, cache, key string, ttl time.Duration) (schema.Snapshot, bool) {
+	if from != "" {
+		snap, err := schema.LoadSnapshot(from)
+		if err != nil {
+			fatalf("--schema-from: %v", err)
+		}
+		if snap.Key != key {
+			fmt.Fprintf(os.Stderr, "[!] --schema-from %s describes %s, not %s\n", from, snap.Key, key)
+		}
+		fmt.Fprintf(os.Stderr, "[+] Schema from %s (taken %s); skipping discovery\n", from, snap.Taken.Local().Format(time.RFC3339))
+		return snap, true
+	}
+	if cache == "" {
+		return schema.Snapshot{}, false
+	}
+	snap, err := schema.LoadSnapshot(cache)
+	switch {
+	case os.IsNotExist(err):
+		return snap, false
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "[!] Ignoring --schema-cache: %v\n", err)
+		return snap, false
+	case snap.Key != key:
+		fmt.Fprintf(os.Stderr, "[+] --schema-cache %s is for another database; rediscovering\n", cache)
+		return snap, false
+	case ttl > 0 && time.Since(snap.Taken) > ttl:
+		fmt.Fprintf(os.Stderr, "[+] --schema-cache %s is older than --cache-ttl; rediscovering\n", cache)
+		return snap, false
+	}
+	fmt.Fprintf(os.Stderr, "[+] Schema from cache %s (taken %s)\n", cache, snap.Taken.Local().Format(time.RFC3339))
+	return snap, true
+}
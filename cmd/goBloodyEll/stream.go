@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/bakw00ds/goBloodyEll/internal/neo4jrunner"
+	"github.com/bakw00ds/goBloodyEll/internal/queries"
+	"github.com/bakw00ds/goBloodyEll/internal/report"
+	"github.com/bakw00ds/goBloodyEll/internal/schema"
+)
+
+// runStream executes jobs one at a time and writes their rows to the --format
+// stream writer as they arrive. outs holds the schema-skipped outputs on entry
+// and every query's outcome (without rows) on return. When interrupted reports
+// true after the run, the output is marked as partial.
+func runStream(ctx context.Context, driver neo4j.DriverWithContext, qs []queries.Query, outs []report.Output, jobs []neo4jrunner.QueryJob, jobToQueryIdx []int, opts neo4jrunner.RunnerOpts, coll schema.Collection, formatName, outPath string, ropts report.Opts, interrupted func() bool) {
+	sw, err := report.NewStreamWriter(formatName, outPath, ropts)
+	if err != nil {
+		fatalf("%v", err)
+	}
+	// Skipped queries are finished in query order between the executed ones.
+	next := 0
+	finishSkipped := func(upto int) error {
+		for ; next < upto; next++ {
+			if outs[next].Skipped {
+				if err := sw.Finish(outs[next]); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	var writeErr error
+	// Jobs run one at a time, so the header of the current one is built at
+	// its first row (or when it finishes) and reused for the rest.
+	cur, hdr := -1, report.Output{}
+	header := func(i int) report.Output {
+		if i != cur {
+			cur, hdr = i, report.Output{Query: qs[i], Note: coll.NoteFor(qs[i].Cypher)}
+		}
+		return hdr
+	}
+	neo4jrunner.Stream(ctx, driver, jobs, opts, func(job neo4jrunner.QueryJob, cols []string, row []any) error {
+		i := jobToQueryIdx[job.Index]
+		if err := finishSkipped(i); err != nil {
+			writeErr = err
+			return err
+		}
+		if err := sw.Row(header(i), cols, row); err != nil {
+			writeErr = err
+			return err
+		}
+		return nil
+	}, func(job neo4jrunner.QueryJob, r neo4jrunner.QueryResult) {
+		i := jobToQueryIdx[job.Index]
+		o := header(i)
+		o.Result, o.Duration, o.ReturnedRows = r.ResultSet, r.Duration, r.Rows
+		if r.Err != nil {
+			o.Error, o.NotRun = r.Err.Error(), r.NotRun
+		}
+		outs[i] = o
+		if writeErr != nil {
+			return
+		}
+		if err := finishSkipped(i); err != nil {
+			writeErr = err
+			return
+		}
+		writeErr = sw.Finish(o)
+		next = i + 1
+	})
+	if writeErr == nil {
+		writeErr = finishSkipped(len(outs))
+	}
+	if interrupted() {
+		sw.Interrupted()
+	}
+	if err := sw.Close(); writeErr == nil {
+		writeErr = err
+	}
+	if writeErr != nil {
+		fatalf("write stream failed: %v", writeErr)
+	}
+}
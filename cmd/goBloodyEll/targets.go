@@ -0,0 +1,247 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/bakw00ds/goBloodyEll/internal/crypt"
+	"github.com/bakw00ds/goBloodyEll/internal/report"
+)
+
+// target is one Neo4j server of a multi-target run.
+type target struct {
+	Name, URI string
+}
+
+var targetNameRe = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+func parseTarget(s string) (target, error) {
+	name, uri, ok := strings.Cut(strings.TrimSpace(s), "=")
+	name, uri = strings.TrimSpace(name), strings.TrimSpace(uri)
+	if !ok || uri == "" {
+		return target{}, fmt.Errorf("%q: expected name=bolt://host:7687", s)
+	}
+	if !targetNameRe.MatchString(name) {
+		return target{}, fmt.Errorf("%q: target names may only use letters, digits, '.', '_' and '-'", name)
+	}
+	return target{Name: name, URI: uri}, nil
+}
+
+// targetList is the repeatable --target flag.
+type targetList []target
+
+func (l *targetList) String() string {
+	parts := make([]string, len(*l))
+	for i, t := range *l {
+		parts[i] = t.Name + "=" + t.URI
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (l *targetList) Set(s string) error {
+	t, err := parseTarget(s)
+	if err != nil {
+		return err
+	}
+	*l = append(*l, t)
+	return nil
+}
+
+// loadTargets reads a --targets-file: one name=uri per line, '#' comments.
+func loadTargets(p string) ([]target, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var out []target
+	sc := bufio.NewScanner(f)
+	for line := 1; sc.Scan(); line++ {
+		s := strings.TrimSpace(sc.Text())
+		if s == "" || strings.HasPrefix(s, "#") {
+			continue
+		}
+		t, err := parseTarget(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", p, line, err)
+		}
+		out = append(out, t)
+	}
+	return out, sc.Err()
+}
+
+// targetOutputFlags name files or directories; each target gets its own
+// copy (report.xlsx -> report-acme.xlsx, out/ -> out/acme).
+var targetOutputFlags = []string{"text", "xlsx", "out", "out-dir", "bundle", "export-core-csvs"}
+
+// targetDirFlags are the output flags naming directories.
+var targetDirFlags = map[string]bool{"out-dir": true, "export-core-csvs": true}
+
+// targetParentFlags are handled by the multi-target parent and not passed to
+// the per-target runs; credentials reach them through the environment.
+var targetParentFlags = map[string]bool{
+	"target": true, "targets-file": true, "combined-xlsx": true, "jobs": true, "jobs-parallel": true,
+	"neo4j-uri": true, "neo4j-ip": true, "results-json": true, "results-json-to": true,
+	"p": true, "password": true, "password-file": true, "password-stdin": true, "auth-token": true,
+	"t": true, "text": true, "x": true, "xlsx": true, "out": true, "out-dir": true, "bundle": true, "export-core-csvs": true,
+}
+
+// targetPath inserts the target name into an output file name, or appends
+// it to an output directory.
+func targetPath(p, name string, dir bool) string {
+	if dir {
+		return strings.TrimRight(p, "/") + "/" + name
+	}
+	ext := path.Ext(p)
+	return strings.TrimSuffix(p, ext) + "-" + name + ext
+}
+
+// targetArgs builds a target's command line from this one: the multi-target
+// flags are dropped, output paths made per-target and the URI replaced; with
+// resultsJSON set the run also writes its JSON export there, encrypted to
+// resultsTo. A
+// leading subcommand is kept ahead of the flags; otherwise it would end the
+// flag scan and --target would reach the child, which would fan out again.
+func targetArgs(args []string, t target, resultsJSON, resultsTo string) []string {
+	var out []string
+	if len(args) > 0 {
+		switch args[0] {
+		case "check", "lint", "ingest":
+			out, args = append(out, args[0]), args[1:]
+		}
+	}
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if a == "--" || a == "-" || !strings.HasPrefix(a, "-") {
+			out = append(out, args[i:]...)
+			break
+		}
+		name, _, hasVal := strings.Cut(strings.TrimLeft(a, "-"), "=")
+		tokens := args[i : i+1]
+		if f := flag.Lookup(name); f != nil && !hasVal && !isBoolFlag(f) && i+1 < len(args) {
+			tokens = args[i : i+2]
+			i++
+		}
+		if !targetParentFlags[name] {
+			out = append(out, tokens...)
+		}
+	}
+	for _, name := range targetOutputFlags {
+		if v := flag.Lookup(name).Value.String(); v != "" {
+			out = append(out, "--"+name+"="+targetPath(v, t.Name, targetDirFlags[name]))
+		}
+	}
+	if resultsJSON != "" {
+		out = append(out, "--results-json="+resultsJSON, "--results-json-to="+resultsTo)
+	}
+	return append(out, "--neo4j-uri="+t.URI)
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// targetEnv is the environment of the per-target runs: this one's, without
+// the GOBLOODYELL_* variables of flags the parent handled, and with the
+// resolved credentials so no run has to prompt.
+func targetEnv(pass, token string) []string {
+	drop := map[string]bool{"NEO4J_PASS": true, "NEO4J_AUTH_TOKEN": true}
+	for name := range targetParentFlags {
+		drop[flagEnvName(name)] = true
+	}
+	var env []string
+	for _, kv := range os.Environ() {
+		if k, _, _ := strings.Cut(kv, "="); !drop[k] {
+			env = append(env, kv)
+		}
+	}
+	if pass != "" {
+		env = append(env, "NEO4J_PASS="+pass)
+	}
+	if token != "" {
+		env = append(env, "NEO4J_AUTH_TOKEN="+token)
+	}
+	return env
+}
+
+// runTargets runs this command line once per target as child processes,
+// parallel at a time, and with combined set also writes one XLSX holding
+// every target's rows (with a leading target column). The targets hand their
+// results back encrypted to a throwaway identity, so no plaintext rows reach
+// the temp directory. It returns the exit status like --jobs does.
+func runTargets(targets []target, parallel int, combined, pass, token string, ropts report.Opts) int {
+	seen := map[string]bool{}
+	for _, t := range targets {
+		if seen[t.Name] {
+			fatalf("duplicate target name %q", t.Name)
+		}
+		seen[t.Name] = true
+	}
+	tmp := ""
+	var key *crypt.Ephemeral
+	if combined != "" {
+		var err error
+		if tmp, err = os.MkdirTemp("", "goBloodyEll-targets-"); err != nil {
+			fatalf("--combined-xlsx: %v", err)
+		}
+		defer os.RemoveAll(tmp)
+		if key, err = crypt.NewEphemeral(); err != nil {
+			fatalf("--combined-xlsx: %v", err)
+		}
+	}
+	jobs := make([]jobSpec, len(targets))
+	for i, t := range targets {
+		resultsJSON, resultsTo := "", ""
+		if tmp != "" {
+			resultsJSON, resultsTo = filepath.Join(tmp, t.Name+".json"), key.Recipient()
+		}
+		jobs[i] = jobSpec{Name: t.Name, Args: targetArgs(os.Args[1:], t, resultsJSON, resultsTo)}
+	}
+	fmt.Fprintf(os.Stderr, "[+] Running against %d targets\n", len(targets))
+	code := runJobSpecs(jobs, parallel, targetEnv(pass, token))
+	if combined == "" {
+		return code
+	}
+
+	var runs []report.TargetRun
+	for _, t := range targets {
+		outs, err := readTargetResults(filepath.Join(tmp, t.Name+".json"+crypt.SchemeAge.Ext()), key)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[!] No results from target %s for the combined workbook: %v\n", t.Name, err)
+			continue
+		}
+		runs = append(runs, report.TargetRun{Name: t.Name, Outs: outs})
+	}
+	if len(runs) == 0 {
+		fmt.Fprintf(os.Stderr, "[!] Not writing %s: no target produced results\n", combined)
+		return max(code, 1)
+	}
+	fmt.Fprintf(os.Stderr, "[+] Writing combined XLSX report -> %s\n", ropts.OutputPath(combined))
+	if err := report.WriteXLSX(report.CombineTargets(runs), combined, ropts); err != nil {
+		fatalf("write combined xlsx failed: %v", err)
+	}
+	fmt.Fprintf(os.Stderr, "[+] Wrote combined XLSX report -> %s (%d of %d targets)\n", ropts.OutputPath(combined), len(runs), len(targets))
+	return code
+}
+
+// readTargetResults reads a target's --results-json written for key.
+func readTargetResults(p string, key *crypt.Ephemeral) ([]report.Output, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r, err := key.Decrypt(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", p, err)
+	}
+	outs, _, err := report.ReadJSONFrom(r, p)
+	return outs, err
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bakw00ds/goBloodyEll/internal/crypt"
+	"github.com/bakw00ds/goBloodyEll/internal/neo4jrunner"
+	"github.com/bakw00ds/goBloodyEll/internal/queries"
+	"github.com/bakw00ds/goBloodyEll/internal/report"
+)
+
+// TestCombinedResultsEncrypted follows --combined-xlsx with encrypted outputs:
+// a target writes its --results-json for the parent's throwaway key, leaving
+// no plaintext in the temp directory, and the parent reads it back.
+func TestCombinedResultsEncrypted(t *testing.T) {
+	key, err := crypt.NewEphemeral()
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, err := crypt.NewEphemeral()
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := queries.Query{ID: "ad-kerberoastable", Title: "Kerberoastable users", Category: "AD", Severity: "high", Headers: []string{"User"}}.WithResolvedKeys()
+	outs := []report.Output{{Query: q, Result: neo4jrunner.ResultSet{Columns: []string{"user"}, Rows: [][]any{{"SVC_SQL@CORP.LOCAL"}}}}}
+
+	tmp := t.TempDir()
+	resultsJSON := filepath.Join(tmp, "acme.json")
+	ropts := report.Opts{EncryptTo: []string{user.Recipient()}, Encrypt: crypt.SchemeAge, Run: &report.RunMeta{ID: "run"}}
+	writeOutputs(outs, ropts, outputFlags{resultsJSON: resultsJSON, resultsTo: []string{key.Recipient()}, resultsScheme: crypt.SchemeAge})
+
+	if _, err := os.Stat(resultsJSON); !os.IsNotExist(err) {
+		t.Fatalf("plaintext results written: %v", err)
+	}
+	enc := resultsJSON + crypt.SchemeAge.Ext()
+	b, err := os.ReadFile(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(b, []byte("SVC_SQL")) {
+		t.Fatal("results file holds plaintext rows")
+	}
+
+	got, err := readTargetResults(enc, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || len(got[0].Result.Rows) != 1 || got[0].Result.Rows[0][0] != "SVC_SQL@CORP.LOCAL" {
+		t.Fatalf("read back %+v", got)
+	}
+	combined := filepath.Join(tmp, "all.xlsx")
+	if err := report.WriteXLSX(report.CombineTargets([]report.TargetRun{{Name: "acme", Outs: got}}), combined, ropts); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(ropts.OutputPath(combined)); err != nil {
+		t.Fatalf("combined workbook: %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/auth"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/config"
+)
+
+// neo4jSchemes are the URI schemes the driver accepts; "+s" verifies the
+// server certificate, "+ssc" accepts any (e.g. self-signed) certificate.
+var neo4jSchemes = map[string]bool{
+	"bolt": true, "bolt+s": true, "bolt+ssc": true,
+	"neo4j": true, "neo4j+s": true, "neo4j+ssc": true,
+}
+
+// tlsOpts are the --tls-* flags.
+type tlsOpts struct {
+	CA         string // PEM file of extra trusted CA certificates
+	SkipVerify bool
+	// Cert and Key are the PEM client certificate and key presented to
+	// servers requiring mutual TLS.
+	Cert, Key string
+}
+
+func (t tlsOpts) enabled() bool { return t.CA != "" || t.SkipVerify || t.Cert != "" }
+
+// tlsURI applies the TLS flags to uri. The driver only uses TLS settings with
+// the "+s"/"+ssc" schemes and derives certificate checking from the scheme,
+// so a plain scheme is upgraded and SkipVerify selects "+ssc".
+func tlsURI(uri string, t tlsOpts) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", fmt.Errorf("invalid --neo4j-uri: %v", err)
+	}
+	if !neo4jSchemes[u.Scheme] {
+		return "", fmt.Errorf("unsupported --neo4j-uri scheme %q (use bolt, bolt+s, bolt+ssc, neo4j, neo4j+s or neo4j+ssc)", u.Scheme)
+	}
+	if !t.enabled() {
+		return uri, nil
+	}
+	if t.CA != "" && t.SkipVerify {
+		return "", fmt.Errorf("--tls-ca and --tls-skip-verify are mutually exclusive")
+	}
+	base, _, _ := strings.Cut(u.Scheme, "+")
+	scheme := base + "+s"
+	if t.SkipVerify || strings.HasSuffix(u.Scheme, "+ssc") && t.CA == "" {
+		scheme = base + "+ssc"
+	}
+	if u.Scheme != scheme {
+		fmt.Fprintf(os.Stderr, "[+] Using %s:// for the TLS options\n", scheme)
+		u.Scheme = scheme
+	}
+	return u.String(), nil
+}
+
+// driverOptions returns the driver configuration for the TLS flags: the CA
+// certificates in t.CA are trusted in addition to the system pool, and
+// t.Cert/t.Key are presented as the client certificate.
+func driverOptions(t tlsOpts) ([]func(*config.Config), error) {
+	var opts []func(*config.Config)
+	if t.CA != "" {
+		pool, err := caPool(t.CA)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, func(c *config.Config) {
+			c.TlsConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+		})
+	}
+	if (t.Cert == "") != (t.Key == "") {
+		return nil, fmt.Errorf("--tls-cert and --tls-key must be given together")
+	}
+	if t.Cert != "" {
+		p, err := auth.NewStaticClientCertificateProvider(auth.ClientCertificate{CertFile: t.Cert, KeyFile: t.Key})
+		if err != nil {
+			return nil, fmt.Errorf("--tls-cert/--tls-key: %v", err)
+		}
+		opts = append(opts, func(c *config.Config) { c.ClientCertificateProvider = p })
+	}
+	return opts, nil
+}
+
+// caPool is the system certificate pool plus the PEM certificates in path.
+func caPool(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("--tls-ca: %v", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("--tls-ca: no PEM certificates in %s", path)
+	}
+	return pool, nil
+}
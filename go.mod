@@ -3,8 +3,13 @@ module github.com/bakw00ds/goBloodyEll
 go 1.23.0
 
 require (
+	filippo.io/age v1.2.1
 	github.com/neo4j/neo4j-go-driver/v5 v5.28.2
 	github.com/xuri/excelize/v2 v2.9.1
+	golang.org/x/net v0.40.0
+	golang.org/x/term v0.32.0
+	golang.org/x/text v0.25.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -14,6 +19,5 @@ require (
 	github.com/xuri/efp v0.0.1 // indirect
 	github.com/xuri/nfp v0.0.1 // indirect
 	golang.org/x/crypto v0.38.0 // indirect
-	golang.org/x/net v0.40.0 // indirect
-	golang.org/x/text v0.25.0 // indirect
+	golang.org/x/sys v0.33.0 // indirect
 )
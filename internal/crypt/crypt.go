@@ -0,0 +1,179 @@
+// Package crypt encrypts output streams to public-key recipients so that
+// reports never reach disk (or a staged upload) in plaintext.
+//
+// Recipients starting with "age1" use age (https://age-encryption.org) in
+// process; anything else is treated as a GPG key ID, fingerprint or email and
+// handed to the local gpg binary, which must already have the public key.
+package crypt
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"filippo.io/age"
+)
+
+// Scheme is the encryption backend selected by a recipient list.
+type Scheme string
+
+const (
+	SchemeNone Scheme = ""
+	SchemeAge  Scheme = "age"
+	SchemeGPG  Scheme = "gpg"
+)
+
+// ParseRecipients splits a comma-separated recipient list and picks the
+// scheme. Mixing age and GPG recipients is rejected.
+func ParseRecipients(s string) ([]string, Scheme, error) {
+	var recips []string
+	scheme := SchemeNone
+	for _, r := range strings.Split(s, ",") {
+		r = strings.TrimSpace(r)
+		if r == "" {
+			continue
+		}
+		sc := SchemeGPG
+		if strings.HasPrefix(r, "age1") {
+			sc = SchemeAge
+			if _, err := age.ParseX25519Recipient(r); err != nil {
+				return nil, SchemeNone, fmt.Errorf("invalid age recipient %q: %w", r, err)
+			}
+		}
+		if scheme != SchemeNone && scheme != sc {
+			return nil, SchemeNone, fmt.Errorf("cannot mix age and gpg recipients")
+		}
+		scheme = sc
+		recips = append(recips, r)
+	}
+	if scheme == SchemeGPG {
+		if _, err := exec.LookPath("gpg"); err != nil {
+			return nil, SchemeNone, fmt.Errorf("gpg recipients need the gpg binary in PATH")
+		}
+	}
+	return recips, scheme, nil
+}
+
+// Ext is the file extension appended to encrypted outputs.
+func (s Scheme) Ext() string {
+	switch s {
+	case SchemeAge:
+		return ".age"
+	case SchemeGPG:
+		return ".gpg"
+	}
+	return ""
+}
+
+// Wrap returns a writer that encrypts to recipients and writes ciphertext to
+// dst. Closing it finishes the encryption and then closes dst.
+func Wrap(dst io.WriteCloser, recipients []string, scheme Scheme) (io.WriteCloser, error) {
+	switch scheme {
+	case SchemeNone:
+		return dst, nil
+	case SchemeAge:
+		rs := make([]age.Recipient, 0, len(recipients))
+		for _, r := range recipients {
+			x, err := age.ParseX25519Recipient(r)
+			if err != nil {
+				return nil, err
+			}
+			rs = append(rs, x)
+		}
+		w, err := age.Encrypt(dst, rs...)
+		if err != nil {
+			return nil, err
+		}
+		return &chained{WriteCloser: w, dst: dst}, nil
+	case SchemeGPG:
+		return newGPGWriter(dst, recipients)
+	}
+	return nil, fmt.Errorf("unknown encryption scheme %q", scheme)
+}
+
+// chained closes the encrypting writer first, then the destination.
+type chained struct {
+	io.WriteCloser
+	dst io.Closer
+}
+
+func (c *chained) Close() error {
+	err := c.WriteCloser.Close()
+	if cerr := c.dst.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// gpgWriter streams plaintext into gpg's stdin; gpg writes ciphertext to dst.
+type gpgWriter struct {
+	io.WriteCloser
+	cmd    *exec.Cmd
+	dst    io.Closer
+	stderr bytes.Buffer
+}
+
+func newGPGWriter(dst io.WriteCloser, recipients []string) (io.WriteCloser, error) {
+	args := []string{"--batch", "--yes", "--trust-model", "always", "--encrypt"}
+	for _, r := range recipients {
+		args = append(args, "--recipient", r)
+	}
+	g := &gpgWriter{dst: dst}
+	g.cmd = exec.Command("gpg", args...)
+	g.cmd.Stdout = dst
+	g.cmd.Stderr = &g.stderr
+	in, err := g.cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	g.WriteCloser = in
+	if err := g.cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start gpg: %w", err)
+	}
+	return g, nil
+}
+
+func (g *gpgWriter) Close() error {
+	err := g.WriteCloser.Close()
+	if werr := g.cmd.Wait(); werr != nil && err == nil {
+		err = fmt.Errorf("gpg: %v: %s", werr, strings.TrimSpace(g.stderr.String()))
+	}
+	if cerr := g.dst.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Ephemeral is a throwaway age identity for data this process writes and
+// reads back itself, such as spool files or a child run's results; the
+// private key only ever lives in memory.
+type Ephemeral struct {
+	id *age.X25519Identity
+}
+
+// NewEphemeral generates a fresh Ephemeral identity.
+func NewEphemeral() (*Ephemeral, error) {
+	id, err := age.GenerateX25519Identity()
+	if err != nil {
+		return nil, err
+	}
+	return &Ephemeral{id: id}, nil
+}
+
+// Recipient is the age recipient that Decrypt can read, for --encrypt-to
+// style recipient lists.
+func (e *Ephemeral) Recipient() string { return e.id.Recipient().String() }
+
+// Encrypt returns a writer encrypting to e into dst; closing it does not
+// close dst.
+func (e *Ephemeral) Encrypt(dst io.Writer) (io.WriteCloser, error) {
+	return age.Encrypt(dst, e.id.Recipient())
+}
+
+// Decrypt returns the plaintext of src, which Encrypt (or Wrap to
+// Recipient) produced.
+func (e *Ephemeral) Decrypt(src io.Reader) (io.Reader, error) {
+	return age.Decrypt(src, e.id)
+}
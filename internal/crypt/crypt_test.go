@@ -0,0 +1,85 @@
+package crypt
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"filippo.io/age"
+)
+
+type bufCloser struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (b *bufCloser) Close() error { b.closed = true; return nil }
+
+func TestAgeRoundTrip(t *testing.T) {
+	id, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	recips, scheme, err := ParseRecipients(" " + id.Recipient().String() + " ,")
+	if err != nil || scheme != SchemeAge || len(recips) != 1 {
+		t.Fatalf("parse: %v %q %v", recips, scheme, err)
+	}
+
+	dst := &bufCloser{}
+	w, err := Wrap(dst, recips, scheme)
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(w, "secret report")
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !dst.closed || bytes.Contains(dst.Bytes(), []byte("secret")) {
+		t.Fatalf("destination not closed or holds plaintext")
+	}
+
+	r, err := age.Decrypt(&dst.Buffer, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := io.ReadAll(r)
+	if string(got) != "secret report" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestParseRecipientsRejectsMix(t *testing.T) {
+	id, _ := age.GenerateX25519Identity()
+	if _, _, err := ParseRecipients(id.Recipient().String() + ",alice@example.com"); err == nil {
+		t.Fatal("expected error mixing age and gpg recipients")
+	}
+}
+
+func TestEphemeralRoundTrip(t *testing.T) {
+	e, err := NewEphemeral()
+	if err != nil {
+		t.Fatal(err)
+	}
+	recips, scheme, err := ParseRecipients(e.Recipient())
+	if err != nil || scheme != SchemeAge {
+		t.Fatalf("parse %s: %q %v", e.Recipient(), scheme, err)
+	}
+	dst := &bufCloser{}
+	w, err := Wrap(dst, recips, scheme)
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(w, "spooled rows")
+	w.Close()
+	r, err := e.Decrypt(bytes.NewReader(dst.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := io.ReadAll(r); string(b) != "spooled rows" {
+		t.Fatalf("decrypted %q", b)
+	}
+	other, _ := NewEphemeral()
+	if _, err := other.Decrypt(bytes.NewReader(dst.Bytes())); err == nil {
+		t.Fatal("another identity decrypted the data")
+	}
+}
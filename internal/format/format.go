@@ -2,14 +2,21 @@ package format
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
-type Formatter struct{}
+type Formatter struct {
+	loc Locale
+}
 
 func New() *Formatter { return &Formatter{} }
 
+// NewLocale returns a Formatter that serializes numbers/dates for the given locale.
+func NewLocale(loc Locale) *Formatter { return &Formatter{loc: loc} }
+
 func (f *Formatter) OneLine(s string) string {
 	s = strings.ReplaceAll(s, "\n", " ")
 	s = strings.ReplaceAll(s, "\r", " ")
@@ -17,24 +24,101 @@ func (f *Formatter) OneLine(s string) string {
 	return s
 }
 
+// Truncate shortens s to at most max characters, ending in an ellipsis.
+// A max of 0 or less leaves s unchanged.
+func Truncate(s string, max int) string {
+	if max <= 0 || len(s) <= max {
+		return s
+	}
+	n := 0
+	for i := range s {
+		if n == max-1 {
+			if utf8.RuneCountInString(s[i:]) == 1 {
+				return s
+			}
+			return s[:i] + "…"
+		}
+		n++
+	}
+	return s
+}
+
 func (f *Formatter) Value(columnKey string, v any) string {
 	if v == nil {
 		return ""
 	}
-	lk := strings.ToLower(columnKey)
-	if strings.Contains(lk, "pwdlastset") || strings.Contains(lk, "lastlogon") || strings.Contains(lk, "lastlogontimestamp") {
+	if isTimeColumn(columnKey) {
+		if t, ok := epoch(v); ok {
+			return f.time(t)
+		}
+		if x, ok := v.(string); ok {
+			return x
+		}
+	}
+	if f.loc.DecimalSep != "" {
 		switch x := v.(type) {
-		case int64:
-			return time.Unix(x, 0).Format(time.RFC3339)
-		case int:
-			return time.Unix(int64(x), 0).Format(time.RFC3339)
 		case float64:
-			return time.Unix(int64(x), 0).Format(time.RFC3339)
+			return f.float(x)
 		case float32:
-			return time.Unix(int64(x), 0).Format(time.RFC3339)
-		case string:
-			return x
+			return f.float(float64(x))
 		}
 	}
 	return fmt.Sprintf("%v", v)
 }
+
+// Cell returns v as a native spreadsheet value: a UTC time.Time for epoch
+// timestamp columns, int64/float64 for numbers, and the Value string for
+// everything else, so spreadsheets can sort and filter on real types.
+func (f *Formatter) Cell(columnKey string, v any) any {
+	if isTimeColumn(columnKey) {
+		if t, ok := epoch(v); ok {
+			return t.UTC()
+		}
+	}
+	switch x := v.(type) {
+	case int64:
+		return x
+	case int:
+		return int64(x)
+	case float64:
+		return x
+	case float32:
+		return float64(x)
+	}
+	return f.Value(columnKey, v)
+}
+
+func isTimeColumn(columnKey string) bool {
+	lk := strings.ToLower(columnKey)
+	return strings.Contains(lk, "pwdlastset") || strings.Contains(lk, "lastlogon")
+}
+
+// epoch converts a numeric Unix timestamp (seconds) to a time.
+func epoch(v any) (time.Time, bool) {
+	switch x := v.(type) {
+	case int64:
+		return time.Unix(x, 0), true
+	case int:
+		return time.Unix(int64(x), 0), true
+	case float64:
+		return time.Unix(int64(x), 0), true
+	case float32:
+		return time.Unix(int64(x), 0), true
+	}
+	return time.Time{}, false
+}
+
+func (f *Formatter) time(t time.Time) string {
+	if f.loc.DateLayout != "" {
+		return t.Format(f.loc.DateLayout)
+	}
+	return t.Format(time.RFC3339)
+}
+
+func (f *Formatter) float(x float64) string {
+	s := strconv.FormatFloat(x, 'f', -1, 64)
+	if f.loc.DecimalSep != "." {
+		s = strings.Replace(s, ".", f.loc.DecimalSep, 1)
+	}
+	return s
+}
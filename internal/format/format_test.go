@@ -0,0 +1,51 @@
+package format
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLocaleValue(t *testing.T) {
+	loc, err := ParseLocale("de_DE.UTF-8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loc.Comma() != ';' {
+		t.Fatalf("want ';' delimiter, got %q", loc.Comma())
+	}
+	f := NewLocale(loc)
+	if got := f.Value("ratio", 1.5); got != "1,5" {
+		t.Fatalf("want 1,5 got %s", got)
+	}
+	if got := New().Value("ratio", 1.5); got != "1.5" {
+		t.Fatalf("default formatter changed: %s", got)
+	}
+	if _, err := ParseLocale("xx-YY"); err == nil {
+		t.Fatalf("expected error for unknown locale")
+	}
+}
+
+func TestCellNativeTypes(t *testing.T) {
+	f := New()
+	if got, ok := f.Cell("pwdlastset", 1700000000.0).(time.Time); !ok || got.Year() != 2023 {
+		t.Fatalf("pwdlastset: want time.Time in 2023, got %#v", f.Cell("pwdlastset", 1700000000.0))
+	}
+	if got := f.Cell("count", 42); got != int64(42) {
+		t.Fatalf("count: want int64(42), got %#v", got)
+	}
+	if got := f.Cell("name", true); got != "true" {
+		t.Fatalf("bool: want string, got %#v", got)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := Truncate("abcdef", 4); got != "abc…" {
+		t.Fatalf("got %q", got)
+	}
+	if got := Truncate("äöü", 3); got != "äöü" {
+		t.Fatalf("multi-byte value within limit was cut: %q", got)
+	}
+	if got := Truncate("abcdef", 0); got != "abcdef" {
+		t.Fatalf("got %q", got)
+	}
+}
@@ -0,0 +1,69 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Locale controls how numbers and dates are serialized for spreadsheet imports.
+// The zero value keeps the historical output (RFC3339 dates, '.' decimals, ',' CSV delimiter).
+type Locale struct {
+	Name       string
+	DecimalSep string
+	DateLayout string
+	CSVComma   rune
+}
+
+// locales is keyed by lower-case language or language-region tag.
+// Regions that use a decimal comma also get ';' as CSV delimiter, which is what Excel expects there.
+var locales = map[string]Locale{
+	"en-us": {Name: "en-US", DecimalSep: ".", DateLayout: "01/02/2006 15:04:05", CSVComma: ','},
+	"en-gb": {Name: "en-GB", DecimalSep: ".", DateLayout: "02/01/2006 15:04:05", CSVComma: ','},
+	"de":    {Name: "de-DE", DecimalSep: ",", DateLayout: "02.01.2006 15:04:05", CSVComma: ';'},
+	"fr":    {Name: "fr-FR", DecimalSep: ",", DateLayout: "02/01/2006 15:04:05", CSVComma: ';'},
+	"es":    {Name: "es-ES", DecimalSep: ",", DateLayout: "02/01/2006 15:04:05", CSVComma: ';'},
+	"it":    {Name: "it-IT", DecimalSep: ",", DateLayout: "02/01/2006 15:04:05", CSVComma: ';'},
+	"nl":    {Name: "nl-NL", DecimalSep: ",", DateLayout: "02-01-2006 15:04:05", CSVComma: ';'},
+	"pt":    {Name: "pt-PT", DecimalSep: ",", DateLayout: "02/01/2006 15:04:05", CSVComma: ';'},
+	"pl":    {Name: "pl-PL", DecimalSep: ",", DateLayout: "02.01.2006 15:04:05", CSVComma: ';'},
+	"sv":    {Name: "sv-SE", DecimalSep: ",", DateLayout: "2006-01-02 15:04:05", CSVComma: ';'},
+	"da":    {Name: "da-DK", DecimalSep: ",", DateLayout: "02-01-2006 15:04:05", CSVComma: ';'},
+	"fi":    {Name: "fi-FI", DecimalSep: ",", DateLayout: "02.01.2006 15:04:05", CSVComma: ';'},
+	"nb":    {Name: "nb-NO", DecimalSep: ",", DateLayout: "02.01.2006 15:04:05", CSVComma: ';'},
+}
+
+// ParseLocale resolves a --locale value such as "de-DE", "de_DE.UTF-8" or "en-GB".
+// An empty value returns the zero Locale (historical output).
+func ParseLocale(s string) (Locale, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Locale{}, nil
+	}
+	tag := strings.ToLower(s)
+	if i := strings.IndexAny(tag, ".@"); i >= 0 {
+		tag = tag[:i]
+	}
+	tag = strings.ReplaceAll(tag, "_", "-")
+	if l, ok := locales[tag]; ok {
+		return l, nil
+	}
+	lang := tag
+	if i := strings.Index(lang, "-"); i >= 0 {
+		lang = lang[:i]
+	}
+	if lang == "en" || lang == "c" || lang == "posix" {
+		return locales["en-us"], nil
+	}
+	if l, ok := locales[lang]; ok {
+		return l, nil
+	}
+	return Locale{}, fmt.Errorf("unsupported locale %q (expected e.g. en-US, en-GB, de-DE, fr-FR)", s)
+}
+
+// Comma returns the CSV delimiter for the locale (',' if unset).
+func (l Locale) Comma() rune {
+	if l.CSVComma == 0 {
+		return ','
+	}
+	return l.CSVComma
+}
@@ -0,0 +1,196 @@
+package ingest
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/bakw00ds/goBloodyEll/internal/queries"
+)
+
+// DefaultBatch is how many nodes or edges go into one write transaction.
+const DefaultBatch = 1000
+
+type edgeKey struct{ rel, srcLabel, dstLabel string }
+
+// Writer is a Sink that MERGEs nodes (keyed by objectid on the Base label,
+// as BloodHound CE does) and edges into Neo4j in batches. Call Flush at the
+// end.
+type Writer struct {
+	ctx   context.Context
+	sess  neo4j.SessionWithContext
+	batch int
+	nodes map[string][]map[string]any
+	edges map[edgeKey][]map[string]any
+	// Nodes and Edges count what was written.
+	Nodes, Edges int
+}
+
+func NewWriter(ctx context.Context, sess neo4j.SessionWithContext, batch int) *Writer {
+	if batch <= 0 {
+		batch = DefaultBatch
+	}
+	return &Writer{ctx: ctx, sess: sess, batch: batch, nodes: map[string][]map[string]any{}, edges: map[edgeKey][]map[string]any{}}
+}
+
+// Prepare creates the objectid index the MERGEs rely on.
+func (w *Writer) Prepare(d queries.Dialect) error {
+	if d == queries.Memgraph {
+		return w.write("CREATE INDEX ON :Base(objectid)", nil)
+	}
+	return w.write("CREATE INDEX gobloodyell_base_objectid IF NOT EXISTS FOR (n:Base) ON (n.objectid)", nil)
+}
+
+func (w *Writer) Node(label, id string, props map[string]any) error {
+	w.nodes[label] = append(w.nodes[label], map[string]any{"id": id, "props": props})
+	if len(w.nodes[label]) >= w.batch {
+		return w.flushNodes(label)
+	}
+	return nil
+}
+
+func (w *Writer) Edge(rel, src, srcLabel, dst, dstLabel string, props map[string]any) error {
+	if props == nil {
+		props = map[string]any{}
+	}
+	k := edgeKey{rel, srcLabel, dstLabel}
+	w.edges[k] = append(w.edges[k], map[string]any{"src": src, "dst": dst, "props": props})
+	if len(w.edges[k]) >= w.batch {
+		return w.flushEdges(k)
+	}
+	return nil
+}
+
+// Flush writes the pending batches, nodes first.
+func (w *Writer) Flush() error {
+	for label := range w.nodes {
+		if err := w.flushNodes(label); err != nil {
+			return err
+		}
+	}
+	for k := range w.edges {
+		if err := w.flushEdges(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *Writer) flushNodes(label string) error {
+	rows := w.nodes[label]
+	if len(rows) == 0 {
+		return nil
+	}
+	cypher := fmt.Sprintf("UNWIND $rows AS r MERGE (n:Base {objectid: r.id}) SET n += r.props, n:%s", quote(label))
+	if err := w.write(cypher, rows); err != nil {
+		return fmt.Errorf("write %s nodes: %w", label, err)
+	}
+	w.Nodes += len(rows)
+	w.nodes[label] = rows[:0]
+	return nil
+}
+
+func (w *Writer) flushEdges(k edgeKey) error {
+	rows := w.edges[k]
+	if len(rows) == 0 {
+		return nil
+	}
+	var b strings.Builder
+	b.WriteString("UNWIND $rows AS r MERGE (s:Base {objectid: r.src}) ")
+	if k.srcLabel != "" {
+		b.WriteString("SET s:" + quote(k.srcLabel) + " ")
+	}
+	b.WriteString("MERGE (t:Base {objectid: r.dst}) ")
+	if k.dstLabel != "" {
+		b.WriteString("SET t:" + quote(k.dstLabel) + " ")
+	}
+	b.WriteString("MERGE (s)-[e:" + quote(k.rel) + "]->(t) SET e += r.props")
+	if err := w.write(b.String(), rows); err != nil {
+		return fmt.Errorf("write %s edges: %w", k.rel, err)
+	}
+	w.Edges += len(rows)
+	w.edges[k] = rows[:0]
+	return nil
+}
+
+func (w *Writer) write(cypher string, rows []map[string]any) error {
+	_, err := neo4j.ExecuteWrite(w.ctx, w.sess, func(tx neo4j.ManagedTransaction) (any, error) {
+		res, err := tx.Run(w.ctx, cypher, map[string]any{"rows": rows})
+		if err != nil {
+			return nil, err
+		}
+		return res.Consume(w.ctx)
+	})
+	return err
+}
+
+// quote backtick-quotes a label or relationship type taken from the data.
+func quote(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "") + "`"
+}
+
+// FileResult describes one ingested collection file.
+type FileResult struct {
+	Name    string
+	Type    string
+	Objects int
+	// Skipped is set for files of unsupported types.
+	Skipped bool
+}
+
+// LoadFile decodes a SharpHound zip (every .json in it) or a single
+// collection .json into sink.
+func LoadFile(path string, sink Sink) ([]FileResult, error) {
+	if !strings.EqualFold(filepath.Ext(path), ".zip") {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r, err := load(filepath.Base(path), f, sink)
+		return []FileResult{r}, err
+	}
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	var out []FileResult
+	for _, zf := range zr.File {
+		if !strings.EqualFold(filepath.Ext(zf.Name), ".json") {
+			continue
+		}
+		rc, err := zf.Open()
+		if err != nil {
+			return out, err
+		}
+		r, err := load(zf.Name, rc, sink)
+		rc.Close()
+		if errors.Is(err, ErrUnsupported) {
+			r.Skipped, err = true, nil
+		}
+		if err != nil {
+			return out, err
+		}
+		out = append(out, r)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("%s: no .json collection files in the zip", path)
+	}
+	return out, nil
+}
+
+func load(name string, r io.Reader, sink Sink) (FileResult, error) {
+	kind, n, err := Decode(r, sink)
+	if err != nil {
+		return FileResult{Name: name, Type: kind, Objects: n}, fmt.Errorf("%s: %w", name, err)
+	}
+	return FileResult{Name: name, Type: kind, Objects: n}, nil
+}
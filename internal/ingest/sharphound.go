@@ -0,0 +1,352 @@
+// Package ingest loads SharpHound collections into Neo4j with the node
+// labels, objectid keys and relationship types the BloodHound queries use.
+package ingest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// typedPrincipal is SharpHound's reference to another object.
+type typedPrincipal struct {
+	ObjectIdentifier string
+	ObjectType       string
+}
+
+type ace struct {
+	PrincipalSID  string
+	PrincipalType string
+	RightName     string
+	IsInherited   bool
+}
+
+type sessionResult struct {
+	UserSID     string
+	ComputerSID string
+}
+
+type sessionAPIResult struct {
+	Results []sessionResult
+}
+
+type localGroupResult struct {
+	Name    string
+	Results []typedPrincipal
+}
+
+type apiResult struct {
+	Results []typedPrincipal
+}
+
+type spnTarget struct {
+	ComputerSID string
+	Service     string
+}
+
+type gpLink struct {
+	GUID       string
+	IsEnforced bool
+}
+
+type trust struct {
+	TargetDomainSid string
+	TrustDirection  json.RawMessage
+	IsTransitive    bool
+}
+
+// object is the union of the fields of every SharpHound object type that
+// turn into nodes and edges.
+type object struct {
+	ObjectIdentifier string
+	Properties       map[string]any
+	Aces             []ace
+	PrimaryGroupSID  string
+	Members          []typedPrincipal
+	ChildObjects     []typedPrincipal
+	Links            []gpLink
+	Trusts           []trust
+	HasSIDHistory    []typedPrincipal
+	AllowedToAct     []typedPrincipal
+	// AllowedToDelegate is a list of objects since SharpHound v4.1 and of
+	// bare SIDs before.
+	AllowedToDelegate  json.RawMessage
+	SPNTargets         []spnTarget
+	Sessions           sessionAPIResult
+	PrivilegedSessions sessionAPIResult
+	RegistrySessions   sessionAPIResult
+	LocalAdmins        apiResult
+	RemoteDesktopUsers apiResult
+	DcomUsers          apiResult
+	PSRemoteUsers      apiResult
+	LocalGroups        []localGroupResult
+}
+
+// fileLabels maps a collection file's meta.type to its node label.
+var fileLabels = map[string]string{
+	"users": "User", "computers": "Computer", "groups": "Group", "domains": "Domain",
+	"gpos": "GPO", "ous": "OU", "containers": "Container",
+}
+
+// principalLabels normalizes SharpHound object types to node labels.
+var principalLabels = map[string]string{
+	"user": "User", "computer": "Computer", "group": "Group", "domain": "Domain",
+	"gpo": "GPO", "ou": "OU", "container": "Container",
+}
+
+// localGroupEdges maps the local groups SharpHound v5+ reports to the
+// edges the legacy LocalAdmins/RemoteDesktopUsers/... fields produced.
+var localGroupEdges = map[string]string{
+	"ADMINISTRATORS":          "AdminTo",
+	"REMOTE DESKTOP USERS":    "CanRDP",
+	"DISTRIBUTED COM USERS":   "ExecuteDCOM",
+	"REMOTE MANAGEMENT USERS": "CanPSRemote",
+}
+
+// ErrUnsupported is returned for collection files of types that don't map
+// to BloodHound's AD schema (e.g. certificate templates).
+var ErrUnsupported = errors.New("unsupported collection type")
+
+// Sink receives the graph decoded from a collection file.
+type Sink interface {
+	Node(label, id string, props map[string]any) error
+	// Edge adds src-[rel]->dst; a label is "" when the file doesn't say
+	// what the endpoint is.
+	Edge(rel, src, srcLabel, dst, dstLabel string, props map[string]any) error
+}
+
+// Decode reads one SharpHound JSON file ({"data": [...], "meta": {...}})
+// and passes its nodes and edges to sink. It streams the data array, so
+// collection files larger than memory are fine. It returns the meta.type and
+// the number of objects, or an error for types it doesn't know.
+func Decode(r io.Reader, sink Sink) (string, int, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	if err := expectDelim(dec, '{'); err != nil {
+		return "", 0, err
+	}
+	var (
+		kind    string
+		pending []json.RawMessage // objects seen before meta
+		n       int
+	)
+	emit := func(raw json.RawMessage) error {
+		var o object
+		if err := json.Unmarshal(raw, &o); err != nil {
+			return err
+		}
+		n++
+		return o.emit(kind, sink)
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return kind, n, err
+		}
+		switch tok {
+		case "meta":
+			var meta struct{ Type string }
+			if err := dec.Decode(&meta); err != nil {
+				return kind, n, err
+			}
+			kind = strings.ToLower(meta.Type)
+			if fileLabels[kind] == "" {
+				return kind, n, fmt.Errorf("%w %q", ErrUnsupported, meta.Type)
+			}
+			for _, raw := range pending {
+				if err := emit(raw); err != nil {
+					return kind, n, err
+				}
+			}
+			pending = nil
+		case "data":
+			if err := expectDelim(dec, '['); err != nil {
+				return kind, n, err
+			}
+			for dec.More() {
+				var raw json.RawMessage
+				if err := dec.Decode(&raw); err != nil {
+					return kind, n, err
+				}
+				if kind == "" {
+					pending = append(pending, raw)
+					continue
+				}
+				if err := emit(raw); err != nil {
+					return kind, n, err
+				}
+			}
+			if _, err := dec.Token(); err != nil {
+				return kind, n, err
+			}
+		default:
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return kind, n, err
+			}
+		}
+	}
+	if kind == "" {
+		return "", n, fmt.Errorf("no meta.type; not a SharpHound collection file")
+	}
+	return kind, n, nil
+}
+
+func expectDelim(dec *json.Decoder, d json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != d {
+		return fmt.Errorf("expected %q, got %v", d, tok)
+	}
+	return nil
+}
+
+func (o object) emit(kind string, sink Sink) error {
+	label := fileLabels[kind]
+	id := strings.ToUpper(o.ObjectIdentifier)
+	if id == "" {
+		return nil
+	}
+	if err := sink.Node(label, id, nodeProps(o.Properties)); err != nil {
+		return err
+	}
+	var edges []edge
+	add := func(rel, src, srcType, dst, dstType string, props map[string]any) {
+		if src != "" && dst != "" && rel != "" {
+			edges = append(edges, edge{rel, strings.ToUpper(src), principalLabels[strings.ToLower(srcType)], strings.ToUpper(dst), principalLabels[strings.ToLower(dstType)], props})
+		}
+	}
+
+	for _, a := range o.Aces {
+		add(a.RightName, a.PrincipalSID, a.PrincipalType, id, label, map[string]any{"isacl": true, "isinherited": a.IsInherited})
+	}
+	if o.PrimaryGroupSID != "" {
+		add("MemberOf", id, label, o.PrimaryGroupSID, "Group", map[string]any{"isprimarygroup": true})
+	}
+	for _, m := range o.Members {
+		add("MemberOf", m.ObjectIdentifier, m.ObjectType, id, label, nil)
+	}
+	for _, c := range o.ChildObjects {
+		add("Contains", id, label, c.ObjectIdentifier, c.ObjectType, nil)
+	}
+	for _, l := range o.Links {
+		add("GPLink", l.GUID, "GPO", id, label, map[string]any{"enforced": l.IsEnforced})
+	}
+	for _, t := range o.Trusts {
+		// Inbound: this domain trusts the target; outbound: the reverse.
+		// "A TrustedBy B" means B trusts A.
+		dir := strings.Trim(string(t.TrustDirection), `"`)
+		props := map[string]any{"isacl": false, "transitive": t.IsTransitive}
+		if dir == "1" || dir == "3" || dir == "Inbound" || dir == "Bidirectional" {
+			add("TrustedBy", t.TargetDomainSid, "Domain", id, label, props)
+		}
+		if dir == "2" || dir == "3" || dir == "Outbound" || dir == "Bidirectional" {
+			add("TrustedBy", id, label, t.TargetDomainSid, "Domain", props)
+		}
+	}
+	for _, p := range o.HasSIDHistory {
+		add("HasSIDHistory", id, label, p.ObjectIdentifier, p.ObjectType, nil)
+	}
+	for _, p := range o.AllowedToAct {
+		add("AllowedToAct", p.ObjectIdentifier, p.ObjectType, id, label, nil)
+	}
+	for _, p := range delegationTargets(o.AllowedToDelegate) {
+		add("AllowedToDelegate", id, label, p.ObjectIdentifier, p.ObjectType, nil)
+	}
+	for _, s := range o.SPNTargets {
+		add(s.Service, id, label, s.ComputerSID, "Computer", nil)
+	}
+	for _, list := range []sessionAPIResult{o.Sessions, o.PrivilegedSessions, o.RegistrySessions} {
+		for _, s := range list.Results {
+			add("HasSession", s.ComputerSID, "Computer", s.UserSID, "User", nil)
+		}
+	}
+	for rel, res := range map[string]apiResult{"AdminTo": o.LocalAdmins, "CanRDP": o.RemoteDesktopUsers, "ExecuteDCOM": o.DcomUsers, "CanPSRemote": o.PSRemoteUsers} {
+		for _, p := range res.Results {
+			add(rel, p.ObjectIdentifier, p.ObjectType, id, label, nil)
+		}
+	}
+	for _, g := range o.LocalGroups {
+		name, _, _ := strings.Cut(strings.ToUpper(g.Name), "@")
+		rel := localGroupEdges[name]
+		for _, p := range g.Results {
+			add(rel, p.ObjectIdentifier, p.ObjectType, id, label, nil)
+		}
+	}
+
+	for _, e := range edges {
+		if err := sink.Edge(e.rel, e.src, e.srcLabel, e.dst, e.dstLabel, e.props); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type edge struct {
+	rel, src, srcLabel, dst, dstLabel string
+	props                             map[string]any
+}
+
+func delegationTargets(raw json.RawMessage) []typedPrincipal {
+	if len(raw) == 0 {
+		return nil
+	}
+	var typed []typedPrincipal
+	if err := json.Unmarshal(raw, &typed); err == nil {
+		return typed
+	}
+	var sids []string
+	if err := json.Unmarshal(raw, &sids); err != nil {
+		return nil
+	}
+	out := make([]typedPrincipal, len(sids))
+	for i, s := range sids {
+		out[i] = typedPrincipal{ObjectIdentifier: s, ObjectType: "Computer"}
+	}
+	return out
+}
+
+// nodeProps keeps the properties Neo4j can store: scalars and lists of
+// scalars of one type. JSON numbers become int64 or float64.
+func nodeProps(in map[string]any) map[string]any {
+	out := make(map[string]any, len(in))
+	for k, v := range in {
+		if v = storable(v); v != nil {
+			out[strings.ToLower(k)] = v
+		}
+	}
+	return out
+}
+
+func storable(v any) any {
+	switch v := v.(type) {
+	case string, bool:
+		return v
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	case []any:
+		if len(v) == 0 {
+			return []string{}
+		}
+		out := make([]any, 0, len(v))
+		for _, e := range v {
+			e = storable(e)
+			if _, nested := e.([]any); e == nil || nested || len(out) > 0 && reflect.TypeOf(e) != reflect.TypeOf(out[0]) {
+				return nil
+			}
+			out = append(out, e)
+		}
+		return out
+	default:
+		return nil
+	}
+}
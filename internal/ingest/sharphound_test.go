@@ -0,0 +1,57 @@
+package ingest
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+type recorder struct{ nodes, edges []string }
+
+func (r *recorder) Node(label, id string, props map[string]any) error {
+	r.nodes = append(r.nodes, fmt.Sprintf("%s:%s:%v", label, id, props["name"]))
+	return nil
+}
+
+func (r *recorder) Edge(rel, src, srcLabel, dst, dstLabel string, _ map[string]any) error {
+	r.edges = append(r.edges, fmt.Sprintf("%s(%s)-%s->%s(%s)", src, srcLabel, rel, dst, dstLabel))
+	return nil
+}
+
+func TestDecodeComputers(t *testing.T) {
+	// data before meta, as SharpHound writes it.
+	in := `{"data": [{
+		"ObjectIdentifier": "s-1-5-21-1-1001",
+		"Properties": {"name": "PC1.CORP.LOCAL", "enabled": true, "serviceprincipalnames": ["a", "b"], "nested": {"x": 1}},
+		"PrimaryGroupSID": "S-1-5-21-1-515",
+		"Aces": [{"PrincipalSID": "S-1-5-21-1-512", "PrincipalType": "Group", "RightName": "GenericAll", "IsInherited": false}],
+		"Sessions": {"Results": [{"UserSID": "S-1-5-21-1-1105", "ComputerSID": "S-1-5-21-1-1001"}]},
+		"LocalGroups": [{"Name": "ADMINISTRATORS@PC1.CORP.LOCAL", "Results": [{"ObjectIdentifier": "S-1-5-21-1-1106", "ObjectType": "User"}]}],
+		"AllowedToDelegate": ["S-1-5-21-1-1002"]
+	}], "meta": {"type": "computers", "version": 5, "count": 1}}`
+	var r recorder
+	kind, n, err := Decode(strings.NewReader(in), &r)
+	if err != nil || kind != "computers" || n != 1 {
+		t.Fatalf("Decode = %q, %d, %v", kind, n, err)
+	}
+	if want := []string{"Computer:S-1-5-21-1-1001:PC1.CORP.LOCAL"}; fmt.Sprint(r.nodes) != fmt.Sprint(want) {
+		t.Fatalf("nodes: got %v want %v", r.nodes, want)
+	}
+	sort.Strings(r.edges)
+	want := []string{
+		"S-1-5-21-1-1001(Computer)-AllowedToDelegate->S-1-5-21-1-1002(Computer)",
+		"S-1-5-21-1-1001(Computer)-HasSession->S-1-5-21-1-1105(User)",
+		"S-1-5-21-1-1001(Computer)-MemberOf->S-1-5-21-1-515(Group)",
+		"S-1-5-21-1-1106(User)-AdminTo->S-1-5-21-1-1001(Computer)",
+		"S-1-5-21-1-512(Group)-GenericAll->S-1-5-21-1-1001(Computer)",
+	}
+	if fmt.Sprint(r.edges) != fmt.Sprint(want) {
+		t.Fatalf("edges:\n got %v\nwant %v", r.edges, want)
+	}
+
+	if _, _, err := Decode(strings.NewReader(`{"meta": {"type": "certtemplates"}, "data": []}`), &r); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("want ErrUnsupported, got %v", err)
+	}
+}
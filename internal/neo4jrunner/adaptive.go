@@ -0,0 +1,62 @@
+package neo4jrunner
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// adaptiveStart is the initial number of concurrent queries in adaptive mode.
+const adaptiveStart = 2
+
+// tuner caps how many of the runner's workers may query at once. The cap
+// starts at adaptiveStart, grows by one after each fast query and halves when
+// a query times out, staying within [1, max].
+type tuner struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	limit  int
+	max    int
+	active int
+	fast   time.Duration
+	log    Progress
+}
+
+func newTuner(max int, perQueryTimeout time.Duration, log Progress) *tuner {
+	fast := 5 * time.Second
+	if perQueryTimeout > 0 {
+		fast = perQueryTimeout / 4
+	}
+	t := &tuner{limit: min(adaptiveStart, max), max: max, fast: fast, log: log}
+	t.cond = sync.NewCond(&t.mu)
+	return t
+}
+
+// acquire blocks until the current cap allows one more running query.
+func (t *tuner) acquire() {
+	t.mu.Lock()
+	for t.active >= t.limit {
+		t.cond.Wait()
+	}
+	t.active++
+	t.mu.Unlock()
+}
+
+// release records a finished query and adjusts the cap from its outcome.
+func (t *tuner) release(took time.Duration, err error) {
+	t.mu.Lock()
+	t.active--
+	prev := t.limit
+	switch {
+	case errors.Is(err, context.DeadlineExceeded) || (err != nil && looksTransient(err)):
+		t.limit = max(1, t.limit/2)
+	case err == nil && took < t.fast && t.limit < t.max:
+		t.limit++
+	}
+	if t.limit != prev {
+		t.log.Logf("[+] adaptive parallelism: %d -> %d", prev, t.limit)
+	}
+	t.mu.Unlock()
+	t.cond.Broadcast()
+}
@@ -0,0 +1,86 @@
+package neo4jrunner
+
+import (
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+)
+
+// Budget caps how much of a result is held in memory. Once either limit is
+// reached the remaining rows are drained and counted but not kept, and the
+// result is marked truncated. Zero fields are unlimited.
+type Budget struct {
+	MaxRows  int
+	MaxBytes int64
+}
+
+// exceeded reports whether a result of rows rows and size bytes has gone past
+// the budget. A negative limit is spent and allows nothing.
+func (b Budget) exceeded(rows int, size int64) bool {
+	return (b.MaxRows != 0 && rows > max(b.MaxRows, 0)) || (b.MaxBytes != 0 && size > max(b.MaxBytes, 0))
+}
+
+// less returns the budget left after rows rows and size bytes; a spent
+// limit becomes -1 so it is not mistaken for "unlimited".
+func (b Budget) less(rows int, size int64) Budget {
+	if b.MaxRows > 0 {
+		if b.MaxRows -= rows; b.MaxRows <= 0 {
+			b.MaxRows = -1
+		}
+	}
+	if b.MaxBytes > 0 {
+		if b.MaxBytes -= size; b.MaxBytes <= 0 {
+			b.MaxBytes = -1
+		}
+	}
+	return b
+}
+
+// approxSize estimates the in-memory size of a row value in bytes. It only
+// needs to be in the right order of magnitude.
+func approxSize(v any) int64 {
+	const word = 16
+	switch x := v.(type) {
+	case nil:
+		return word
+	case string:
+		return word + int64(len(x))
+	case []any:
+		n := int64(word)
+		for _, e := range x {
+			n += approxSize(e)
+		}
+		return n
+	case map[string]any:
+		n := int64(word)
+		for k, e := range x {
+			n += word + int64(len(k)) + approxSize(e)
+		}
+		return n
+	case dbtype.Node:
+		n := int64(word) + int64(len(x.ElementId)) + approxSize(x.Props)
+		for _, l := range x.Labels {
+			n += word + int64(len(l))
+		}
+		return n
+	case dbtype.Relationship:
+		return word + int64(len(x.ElementId)+len(x.Type)) + approxSize(x.Props)
+	case dbtype.Path:
+		n := int64(word)
+		for _, nd := range x.Nodes {
+			n += approxSize(nd)
+		}
+		for _, r := range x.Relationships {
+			n += approxSize(r)
+		}
+		return n
+	default:
+		return word
+	}
+}
+
+func rowSize(row []any) int64 {
+	var n int64
+	for _, v := range row {
+		n += approxSize(v)
+	}
+	return n
+}
@@ -0,0 +1,27 @@
+package neo4jrunner
+
+import "testing"
+
+func TestBudget(t *testing.T) {
+	b := Budget{MaxRows: 10, MaxBytes: 1000}
+	if b.exceeded(10, 1000) {
+		t.Fatal("budget exceeded at its limits")
+	}
+	if !b.exceeded(11, 0) || !b.exceeded(1, 1001) {
+		t.Fatal("budget not exceeded past its limits")
+	}
+	if (Budget{}).exceeded(1<<30, 1<<40) {
+		t.Fatal("zero budget should be unlimited")
+	}
+	left := b.less(4, 100)
+	if left.MaxRows != 6 || left.MaxBytes != 900 {
+		t.Fatalf("less = %+v", left)
+	}
+	spent := b.less(10, 0)
+	if !spent.exceeded(1, 0) {
+		t.Fatal("spent budget should allow no further rows")
+	}
+	if got := approxSize([]any{"abcd", int64(1), map[string]any{"k": "v"}}); got <= 0 {
+		t.Fatalf("approxSize = %d", got)
+	}
+}
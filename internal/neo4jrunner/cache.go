@@ -0,0 +1,78 @@
+package neo4jrunner
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Cache keeps successful result sets as files in a directory so repeated runs
+// within the TTL skip Neo4j. Entries are keyed like checkpoints (query id,
+// Cypher, parameters, row limit) plus the scope, typically the server and
+// database, so results never leak between targets.
+type Cache struct {
+	dir   string
+	ttl   time.Duration
+	scope string
+}
+
+// NewCache returns a cache in dir, creating it if needed.
+func NewCache(dir string, ttl time.Duration, scope string) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &Cache{dir: dir, ttl: ttl, scope: scope}, nil
+}
+
+// Lookup returns job's cached result and its age when it is younger than the
+// TTL.
+func (c *Cache) Lookup(job QueryJob, limit int) (QueryResult, time.Duration, bool) {
+	path := c.path(job, limit)
+	fi, err := os.Stat(path)
+	if err != nil {
+		return QueryResult{}, 0, false
+	}
+	age := time.Since(fi.ModTime())
+	if c.ttl > 0 && age > c.ttl {
+		return QueryResult{}, 0, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return QueryResult{}, 0, false
+	}
+	e, err := decodeEntry(data)
+	if err != nil {
+		return QueryResult{}, 0, false
+	}
+	return e.result(), age, true
+}
+
+// Store caches a successful result of job, replacing any older entry.
+func (c *Cache) Store(job QueryJob, limit int, r QueryResult) error {
+	path := c.path(job, limit)
+	b, err := json.Marshal(newEntry(filepath.Base(path), job, r))
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(c.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (c *Cache) path(job QueryJob, limit int) string {
+	scoped := job
+	scoped.ID = c.scope + "\x00" + job.ID
+	return filepath.Join(c.dir, checkpointKey(scoped, limit)+".json")
+}
@@ -0,0 +1,184 @@
+package neo4jrunner
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Checkpoint is an append-only state file of completed query results (one
+// JSON object per line). Run records every successful query in it and, when
+// resuming, reuses recorded results instead of re-running the query.
+// Results are keyed by query id, Cypher, parameters and row limit, so a
+// changed query or limit is re-run rather than served stale.
+type Checkpoint struct {
+	mu   sync.Mutex
+	f    *os.File
+	path string
+	done map[string]checkpointEntry
+}
+
+type checkpointEntry struct {
+	Key        string   `json:"key"`
+	ID         string   `json:"id"`
+	Columns    []string `json:"columns"`
+	Rows       [][]any  `json:"rows"`
+	Truncated  bool     `json:"truncated,omitempty"`
+	Dropped    int      `json:"dropped,omitempty"`
+	Returned   int      `json:"returnedRows"`
+	DurationMS int64    `json:"durationMs"`
+}
+
+// NewCheckpoint creates an empty state file in the temp directory.
+func NewCheckpoint() (*Checkpoint, error) {
+	f, err := os.CreateTemp("", "goBloodyEll-*.state")
+	if err != nil {
+		return nil, err
+	}
+	return &Checkpoint{f: f, path: f.Name(), done: map[string]checkpointEntry{}}, nil
+}
+
+// OpenCheckpoint loads the results recorded in path and appends new ones to
+// it. A line cut short by a crash is ignored.
+func OpenCheckpoint(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	done := map[string]checkpointEntry{}
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		e, err := decodeEntry(line)
+		if err != nil {
+			continue
+		}
+		done[e.Key] = e
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &Checkpoint{f: f, path: path, done: done}, nil
+}
+
+// Path returns the state file path.
+func (c *Checkpoint) Path() string { return c.path }
+
+// Len returns the number of recorded results.
+func (c *Checkpoint) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.done)
+}
+
+// Lookup returns the recorded result of job, if any.
+func (c *Checkpoint) Lookup(job QueryJob, limit int) (QueryResult, bool) {
+	c.mu.Lock()
+	e, ok := c.done[checkpointKey(job, limit)]
+	c.mu.Unlock()
+	if !ok {
+		return QueryResult{}, false
+	}
+	return e.result(), true
+}
+
+// Save records a successful result of job.
+func (c *Checkpoint) Save(job QueryJob, limit int, r QueryResult) error {
+	e := newEntry(checkpointKey(job, limit), job, r)
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	w := bufio.NewWriter(c.f)
+	w.Write(b)
+	w.WriteByte('\n')
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	c.done[e.Key] = e
+	return nil
+}
+
+// Close closes the state file, removing it when remove is set.
+func (c *Checkpoint) Close(remove bool) error {
+	err := c.f.Close()
+	if remove {
+		if rerr := os.Remove(c.path); err == nil {
+			err = rerr
+		}
+	}
+	return err
+}
+
+func checkpointKey(job QueryJob, limit int) string {
+	h := sha256.New()
+	// encoding/json sorts map keys, so equal params hash equally.
+	p, _ := json.Marshal(job.Params)
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%d\x00%t", job.ID, job.Cypher, p, limit, job.Paged)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func newEntry(key string, job QueryJob, r QueryResult) checkpointEntry {
+	return checkpointEntry{
+		Key: key, ID: job.ID,
+		Columns: r.ResultSet.Columns, Rows: r.ResultSet.Rows, Truncated: r.ResultSet.Truncated,
+		Dropped: r.ResultSet.Dropped, Returned: r.Rows, DurationMS: r.Duration.Milliseconds(),
+	}
+}
+
+func (e checkpointEntry) result() QueryResult {
+	return QueryResult{
+		ResultSet: ResultSet{Columns: e.Columns, Rows: e.Rows, Truncated: e.Truncated, Dropped: e.Dropped},
+		Duration:  time.Duration(e.DurationMS) * time.Millisecond,
+		Rows:      e.Returned,
+	}
+}
+
+// decodeEntry parses one recorded result, restoring Neo4j's number types.
+func decodeEntry(data []byte) (checkpointEntry, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var e checkpointEntry
+	if err := dec.Decode(&e); err != nil {
+		return checkpointEntry{}, err
+	}
+	for _, row := range e.Rows {
+		for i, v := range row {
+			row[i] = fromJSON(v)
+		}
+	}
+	return e, nil
+}
+
+// fromJSON restores the numeric types Neo4j returns (int64, float64) from a
+// json.Number-decoded value.
+func fromJSON(v any) any {
+	switch x := v.(type) {
+	case json.Number:
+		if n, err := strconv.ParseInt(string(x), 10, 64); err == nil {
+			return n
+		}
+		f, _ := x.Float64()
+		return f
+	case []any:
+		for i := range x {
+			x[i] = fromJSON(x[i])
+		}
+	case map[string]any:
+		for k := range x {
+			x[k] = fromJSON(x[k])
+		}
+	}
+	return v
+}
@@ -0,0 +1,46 @@
+package neo4jrunner
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCheckpointRoundTrip(t *testing.T) {
+	c, err := NewCheckpoint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	job := QueryJob{ID: "q1", Cypher: "RETURN 1", Params: map[string]any{"staleDays": 730}}
+	want := QueryResult{
+		ResultSet: ResultSet{Columns: []string{"name", "n", "ratio", "tags"}, Rows: [][]any{{"A", int64(3), 0.5, []any{"x", int64(1)}}, {nil, int64(-1), 2.25, nil}}},
+		Duration:  1500 * time.Millisecond,
+		Rows:      2,
+	}
+	if err := c.Save(job, 10, want); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Close(false); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err = OpenCheckpoint(c.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close(true)
+	got, ok := c.Lookup(job, 10)
+	if !ok {
+		t.Fatal("recorded result not found")
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+	if _, ok := c.Lookup(job, 20); ok {
+		t.Fatal("result reused for a different limit")
+	}
+	job.Params = map[string]any{"staleDays": 365}
+	if _, ok := c.Lookup(job, 10); ok {
+		t.Fatal("result reused for different params")
+	}
+}
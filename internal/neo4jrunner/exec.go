@@ -2,26 +2,55 @@ package neo4jrunner
 
 import (
 	"context"
-	"fmt"
 	"strings"
 
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/bakw00ds/goBloodyEll/internal/queries"
 )
 
-func ExecCypher(ctx context.Context, sess neo4j.SessionWithContext, cypher string, limit int) (ResultSet, error) {
-	cy := strings.TrimSpace(cypher)
-	if limit > 0 && !strings.Contains(strings.ToLower(cy), "limit") {
-		cy = cy + fmt.Sprintf("\nLIMIT %d", limit)
-	}
+// ExecFunc runs one query; Run takes it as a parameter so tests can stub it.
+type ExecFunc func(ctx context.Context, sess neo4j.SessionWithContext, cypher string, params map[string]any, eo ExecOpts) (ResultSet, error)
+
+// ExecOpts are the per-call settings of an ExecFunc.
+type ExecOpts struct {
+	// Limit caps the result at Limit rows (0 = unlimited).
+	Limit int
+	// Budget caps the rows and bytes kept in memory.
+	Budget Budget
+	// TxMeta is attached to the transaction, which makes it identifiable in
+	// SHOW TRANSACTIONS (see the watchdog).
+	TxMeta map[string]any
+	// Write runs the query in a write transaction.
+	Write bool
+}
 
-	anyRes, err := sess.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
-		res, err := tx.Run(ctx, cy, nil)
+// ExecCypher runs cypher with params in a read transaction (a write one with
+// eo.Write), capping the result at eo.Limit rows. Reading stops at the first
+// row beyond eo.Budget, so ResultSet.Dropped is then a lower bound (1).
+func ExecCypher(ctx context.Context, sess neo4j.SessionWithContext, cypher string, params map[string]any, eo ExecOpts) (ResultSet, error) {
+	limit, budget := eo.Limit, eo.Budget
+	cy, params := withLimit(cypher, params, limit)
+
+	execute := sess.ExecuteRead
+	if eo.Write {
+		execute = sess.ExecuteWrite
+	}
+	anyRes, err := execute(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		res, err := tx.Run(ctx, cy, params)
 		if err != nil {
 			return nil, err
 		}
 		var cols []string
 		rows := make([][]any, 0)
+		truncated := false
+		dropped := 0
+		var size int64
 		for res.Next(ctx) {
+			if limit > 0 && len(rows) >= limit {
+				truncated = true
+				break
+			}
 			rec := res.Record()
 			if cols == nil {
 				cols = append([]string(nil), rec.Keys...)
@@ -31,10 +60,14 @@ func ExecCypher(ctx context.Context, sess neo4j.SessionWithContext, cypher strin
 				v, _ := rec.Get(k)
 				row = append(row, v)
 			}
-			rows = append(rows, row)
-			if limit > 0 && len(rows) >= limit {
+			size += rowSize(row)
+			if budget.exceeded(len(rows)+1, size) {
+				// Stop reading rather than stream the rest of a huge
+				// result just to count it.
+				dropped, truncated = 1, true
 				break
 			}
+			rows = append(rows, row)
 		}
 		if err := res.Err(); err != nil {
 			return nil, err
@@ -42,10 +75,29 @@ func ExecCypher(ctx context.Context, sess neo4j.SessionWithContext, cypher strin
 		if cols == nil {
 			cols = []string{}
 		}
-		return ResultSet{Columns: cols, Rows: rows}, nil
-	})
+		return ResultSet{Columns: cols, Rows: rows, Truncated: truncated, Dropped: dropped}, nil
+	}, neo4j.WithTxMetadata(eo.TxMeta))
 	if err != nil {
 		return ResultSet{}, err
 	}
 	return anyRes.(ResultSet), nil
 }
+
+// withLimit enforces the row limit via queries.LimitCypher, asking for one
+// extra row so a truncated result can be told apart.
+func withLimit(cypher string, params map[string]any, limit int) (string, map[string]any) {
+	cy := strings.TrimSpace(cypher)
+	if limit <= 0 {
+		return cy, params
+	}
+	cy, ok := queries.LimitCypher(cy)
+	if !ok {
+		return cy, params
+	}
+	p := make(map[string]any, len(params)+1)
+	for k, v := range params {
+		p[k] = v
+	}
+	p[queries.LimitParam] = limit + 1
+	return cy, p
+}
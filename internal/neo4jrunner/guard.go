@@ -0,0 +1,27 @@
+package neo4jrunner
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// panicError is the Err of a job whose execution panicked.
+type panicError struct {
+	value any
+}
+
+func (e *panicError) Error() string { return fmt.Sprintf("internal error: panic: %v", e.value) }
+
+// guard runs fn for job and turns a panic, e.g. while decoding an unusual
+// Neo4j value such as a spatial or duration type, into a *panicError so only
+// that query fails instead of the whole run and its report. The stack goes to
+// the progress log for a bug report.
+func guard(job QueryJob, opts RunnerOpts, fn func() error) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			opts.Progress.Logf("[!] %s: recovered from panic: %v\n%s", job.ID, p, debug.Stack())
+			err = &panicError{value: p}
+		}
+	}()
+	return fn()
+}
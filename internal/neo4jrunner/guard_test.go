@@ -0,0 +1,34 @@
+package neo4jrunner
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func TestRunRecoversPanic(t *testing.T) {
+	// Sessions connect lazily and the stub never uses them.
+	driver, err := neo4j.NewDriverWithContext("bolt://127.0.0.1:1", neo4j.NoAuth())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer driver.Close(context.Background())
+
+	exec := func(ctx context.Context, sess neo4j.SessionWithContext, cypher string, params map[string]any, eo ExecOpts) (ResultSet, error) {
+		if cypher == "boom" {
+			panic("unsupported value")
+		}
+		return ResultSet{Columns: []string{"n"}, Rows: [][]any{{int64(1)}}}, nil
+	}
+	jobs := []QueryJob{{Index: 0, ID: "a", Cypher: "ok"}, {Index: 1, ID: "b", Cypher: "boom"}, {Index: 2, ID: "c", Cypher: "ok"}}
+	opts := RunnerOpts{Parallel: 1, Progress: NewLines(io.Discard, len(jobs), true)}
+	out := Run(context.Background(), driver, jobs, opts, exec)
+	if _, ok := out[1].Err.(*panicError); !ok {
+		t.Fatalf("panicking job: err = %v", out[1].Err)
+	}
+	if out[0].Err != nil || out[2].Err != nil || out[2].Rows != 1 {
+		t.Fatalf("other jobs affected: %+v %+v", out[0], out[2])
+	}
+}
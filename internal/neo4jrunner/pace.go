@@ -0,0 +1,33 @@
+package neo4jrunner
+
+import (
+	"context"
+	"time"
+)
+
+// pacer spaces out query starts so a full pack doesn't monopolise a shared
+// (production) Neo4j. The zero interval never waits.
+type pacer struct {
+	interval time.Duration
+	next     time.Time
+}
+
+// wait blocks until the next query may start, or ctx is done.
+func (p *pacer) wait(ctx context.Context) error {
+	if p.interval <= 0 {
+		return nil
+	}
+	now := time.Now()
+	if d := p.next.Sub(now); d > 0 {
+		t := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+		now = p.next
+	}
+	p.next = now.Add(p.interval)
+	return nil
+}
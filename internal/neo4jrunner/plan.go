@@ -0,0 +1,114 @@
+package neo4jrunner
+
+import (
+	"context"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// Plan is a query's execution plan from EXPLAIN or PROFILE, flattened
+// depth-first from the root operator.
+type Plan struct {
+	Profiled  bool
+	Operators []PlanOperator
+	// DBHits totals the operators' database hits and Rows is the root's
+	// record count; both are PROFILE-only.
+	DBHits int64
+	Rows   int64
+	// Scans lists the label, type and all-node scans in the plan, the usual
+	// sign of a missing index.
+	Scans []string
+}
+
+// PlanOperator is one step of a Plan.
+type PlanOperator struct {
+	Depth         int     `json:"depth"`
+	Operator      string  `json:"operator"`
+	Details       string  `json:"details,omitempty"`
+	EstimatedRows float64 `json:"estimatedRows"`
+	DBHits        int64   `json:"dbHits"`
+	Rows          int64   `json:"rows"`
+}
+
+// scanOperators read a whole label, relationship type or the whole graph.
+var scanOperators = map[string]bool{
+	"AllNodesScan":                   true,
+	"NodeByLabelScan":                true,
+	"DirectedAllRelationshipsScan":   true,
+	"UndirectedAllRelationshipsScan": true,
+	"DirectedRelationshipTypeScan":   true,
+	"UndirectedRelationshipTypeScan": true,
+}
+
+// PlanExec returns an ExecFunc that runs each query under EXPLAIN (planning
+// only) or PROFILE (executing it) and returns the plan in ResultSet.Plan
+// instead of rows.
+func PlanExec(profile bool) ExecFunc {
+	return func(ctx context.Context, sess neo4j.SessionWithContext, cypher string, params map[string]any, eo ExecOpts) (ResultSet, error) {
+		cy, params := withLimit(cypher, params, eo.Limit)
+		prefix := "EXPLAIN "
+		if profile {
+			prefix = "PROFILE "
+		}
+		anyPlan, err := sess.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+			res, err := tx.Run(ctx, prefix+cy, params)
+			if err != nil {
+				return nil, err
+			}
+			sum, err := res.Consume(ctx)
+			if err != nil {
+				return nil, err
+			}
+			p := &Plan{Profiled: profile}
+			if profile && sum.Profile() != nil {
+				flattenProfile(p, sum.Profile(), 0)
+				p.Rows = sum.Profile().Records()
+			} else if sum.Plan() != nil {
+				flattenPlan(p, sum.Plan(), 0)
+			}
+			return p, nil
+		}, neo4j.WithTxMetadata(eo.TxMeta))
+		if err != nil {
+			return ResultSet{}, err
+		}
+		return ResultSet{Columns: []string{}, Rows: [][]any{}, Plan: anyPlan.(*Plan)}, nil
+	}
+}
+
+func flattenPlan(p *Plan, n neo4j.Plan, depth int) {
+	p.add(newPlanOperator(n.Operator(), n.Arguments(), depth))
+	for _, c := range n.Children() {
+		flattenPlan(p, c, depth+1)
+	}
+}
+
+func flattenProfile(p *Plan, n neo4j.ProfiledPlan, depth int) {
+	op := newPlanOperator(n.Operator(), n.Arguments(), depth)
+	op.DBHits, op.Rows = n.DbHits(), n.Records()
+	p.add(op)
+	p.DBHits += op.DBHits
+	for _, c := range n.Children() {
+		flattenProfile(p, c, depth+1)
+	}
+}
+
+func (p *Plan) add(op PlanOperator) {
+	p.Operators = append(p.Operators, op)
+	if scanOperators[op.Operator] {
+		s := op.Operator
+		if op.Details != "" {
+			s += " " + op.Details
+		}
+		p.Scans = append(p.Scans, s)
+	}
+}
+
+func newPlanOperator(name string, args map[string]any, depth int) PlanOperator {
+	// Neo4j 5 suffixes operators with the runtime database, e.g. "Filter@neo4j".
+	name, _, _ = strings.Cut(name, "@")
+	op := PlanOperator{Depth: depth, Operator: name}
+	op.Details, _ = args["Details"].(string)
+	op.EstimatedRows, _ = args["EstimatedRows"].(float64)
+	return op
+}
@@ -0,0 +1,224 @@
+package neo4jrunner
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Progress receives the runner's events. Run calls it from several workers
+// at once, so implementations must be safe for concurrent use; the runner
+// closes it when done.
+type Progress interface {
+	// Start is called when worker begins executing job.
+	Start(worker int, job QueryJob)
+	// Done is called when job has finished. from names where a result that
+	// was not executed came from (e.g. "checkpoint") and is "" otherwise;
+	// worker is -1 then.
+	Done(worker int, job QueryJob, r QueryResult, from string)
+	// Logf prints a message line without disturbing the display.
+	Logf(format string, args ...any)
+	Close()
+}
+
+// NewLines returns the plain Progress: one stderr-style line per event,
+// suitable for logs and non-terminals. With quiet, only Logf prints.
+func NewLines(w io.Writer, total int, quiet bool) Progress {
+	return &lines{w: w, total: total, quiet: quiet}
+}
+
+type lines struct {
+	mu    sync.Mutex
+	w     io.Writer
+	total int
+	quiet bool
+}
+
+func (p *lines) Start(worker int, job QueryJob) {
+	if !p.quiet {
+		p.Logf("[+] (%d/%d) %s [%s]", job.Index+1, p.total, job.Name, job.ID)
+	}
+}
+
+func (p *lines) Done(worker int, job QueryJob, r QueryResult, from string) {
+	switch {
+	case p.quiet:
+	case r.Err != nil:
+		p.Logf("[!] (%d/%d) %s failed after %s: %v", job.Index+1, p.total, job.ID, r.Duration.Round(time.Millisecond), r.Err)
+	case from != "":
+		p.Logf("[+] (%d/%d) %s: %d rows from %s", job.Index+1, p.total, job.ID, len(r.ResultSet.Rows), from)
+	default:
+		p.Logf("[+] (%d/%d) %s: %d rows in %s", job.Index+1, p.total, job.ID, r.Rows, r.Duration.Round(time.Millisecond))
+	}
+}
+
+func (p *lines) Logf(format string, args ...any) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprintf(p.w, format+"\n", args...)
+}
+
+func (p *lines) Close() {}
+
+// liveRefresh is how often the live display redraws while nothing happens,
+// to keep elapsed times current.
+const liveRefresh = 500 * time.Millisecond
+
+// NewLive returns a Progress that redraws a status block on a terminal:
+// completed/total, rows so far, elapsed time, ETA and what each worker is
+// running. Failures and Logf messages scroll above the block. width is the
+// terminal width; longer lines are cut so the block can be redrawn in place.
+func NewLive(w io.Writer, total, workers, width int) Progress {
+	if width < 40 {
+		width = 80
+	}
+	p := &live{
+		w: w, total: total, workers: workers, width: width,
+		started: time.Now(), running: map[int]liveJob{}, stop: make(chan struct{}), stopped: make(chan struct{}),
+	}
+	go p.tick()
+	return p
+}
+
+type live struct {
+	mu      sync.Mutex
+	w       io.Writer
+	total   int
+	workers int
+	width   int
+	started time.Time
+	done    int
+	rows    int
+	running map[int]liveJob
+	drawn   int // lines of the last frame
+	closed  bool
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+type liveJob struct {
+	id    string
+	since time.Time
+}
+
+func (p *live) tick() {
+	defer close(p.stopped)
+	t := time.NewTicker(liveRefresh)
+	defer t.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-t.C:
+			p.mu.Lock()
+			p.redraw("")
+			p.mu.Unlock()
+		}
+	}
+}
+
+func (p *live) Start(worker int, job QueryJob) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.running[worker] = liveJob{id: job.ID, since: time.Now()}
+	p.redraw("")
+}
+
+func (p *live) Done(worker int, job QueryJob, r QueryResult, from string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.running, worker)
+	p.done++
+	p.rows += r.Rows
+	msg := ""
+	if r.Err != nil {
+		msg = fmt.Sprintf("[!] (%d/%d) %s failed after %s: %v", job.Index+1, p.total, job.ID, r.Duration.Round(time.Millisecond), r.Err)
+	}
+	p.redraw(msg)
+}
+
+func (p *live) Logf(format string, args ...any) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.redraw(fmt.Sprintf(format, args...))
+}
+
+// Close draws the final frame and leaves it on screen.
+func (p *live) Close() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	p.closed = true
+	p.running = map[int]liveJob{}
+	p.redraw("")
+	p.mu.Unlock()
+	close(p.stop)
+	<-p.stopped
+}
+
+// redraw replaces the previous frame, printing msg (if any) above the new
+// one. The caller holds p.mu.
+func (p *live) redraw(msg string) {
+	var b strings.Builder
+	if p.drawn > 0 {
+		// back to the first line of the old frame, then clear to the end
+		fmt.Fprintf(&b, "\x1b[%dF\x1b[J", p.drawn)
+	}
+	if msg != "" {
+		b.WriteString(msg)
+		b.WriteByte('\n')
+	}
+	frame := p.frame()
+	for _, l := range frame {
+		b.WriteString(l)
+		b.WriteByte('\n')
+	}
+	p.drawn = len(frame)
+	io.WriteString(p.w, b.String())
+}
+
+func (p *live) frame() []string {
+	elapsed := time.Since(p.started)
+	const barWidth = 30
+	filled := 0
+	if p.total > 0 {
+		filled = barWidth * p.done / p.total
+	}
+	eta := "-"
+	if p.done > 0 && p.done < p.total {
+		eta = (elapsed / time.Duration(p.done) * time.Duration(p.total-p.done)).Round(time.Second).String()
+	} else if p.done >= p.total {
+		eta = "done"
+	}
+	out := []string{p.cut(fmt.Sprintf("[%s%s] %d/%d queries  %d rows  elapsed %s  ETA %s",
+		strings.Repeat("#", filled), strings.Repeat(".", barWidth-filled),
+		p.done, p.total, p.rows, elapsed.Round(time.Second), eta))}
+
+	ws := make([]int, 0, len(p.running))
+	for w := range p.running {
+		ws = append(ws, w)
+	}
+	sort.Ints(ws)
+	for _, w := range ws {
+		j := p.running[w]
+		out = append(out, p.cut(fmt.Sprintf("  worker %d: %s (%s)", w+1, j.id, time.Since(j.since).Round(time.Second))))
+	}
+	if idle := p.workers - len(ws); idle > 0 && p.done < p.total {
+		out = append(out, fmt.Sprintf("  %d worker(s) idle", idle))
+	}
+	return out
+}
+
+// cut shortens s to the terminal width so it never wraps.
+func (p *live) cut(s string) string {
+	r := []rune(s)
+	if len(r) < p.width {
+		return s
+	}
+	return string(r[:p.width-1])
+}
@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/rand/v2"
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/bakw00ds/goBloodyEll/internal/queries"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 )
 
@@ -17,15 +20,40 @@ type QueryJob struct {
 	ID     string
 	Name   string
 	Cypher string
+	// Params are the query's $parameters.
+	Params map[string]any
+	// Paged marks Cypher as a keyset-paged query (see queries.PagedCypher)
+	// taking $after and $pageSize and returning its page key as the last
+	// column; it runs page by page when RunnerOpts.PageSize > 0, and the key
+	// column is not part of the result.
+	Paged bool
+	// Limit and Timeout, when set, replace RunnerOpts.Limit and
+	// PerQueryTimeout for this job (see RunnerOpts.ForJob).
+	Limit   int
+	Timeout time.Duration
+	// Write runs the job in a write transaction; its results are not
+	// cached, since a cache hit would skip the write.
+	Write bool
 }
 
 type QueryResult struct {
 	ResultSet ResultSet
 	Err       error
-	Skipped   bool
-	SkipWhy   string
+	// Duration is the wall-clock time of the query including retries.
+	Duration time.Duration
+	// Rows is the number of rows the server returned.
+	Rows    int
+	Skipped bool
+	SkipWhy string
+	// NotRun marks a job that never started because the run was cancelled
+	// or stopped by FailFast; Err says which.
+	NotRun bool
 }
 
+// ErrNotRun is the Err of jobs never started because FailFast stopped the
+// run after an earlier query failed.
+var ErrNotRun = errors.New("not run: the run stopped after an earlier query failed")
+
 type RunnerOpts struct {
 	DB              string
 	Limit           int
@@ -33,7 +61,40 @@ type RunnerOpts struct {
 	PerQueryTimeout time.Duration
 	Retries         int
 	FailFast        bool
-	Verbose         bool
+	// Verbose prints a line per query event when Progress is nil.
+	Verbose bool
+	// Progress receives query events (default: NewLines on stderr).
+	Progress Progress
+	// PageSize is the rows per page for paged jobs.
+	PageSize int
+	// RetryBase and RetryMax bound the exponential backoff between retries
+	// (defaults 200ms and 10s).
+	RetryBase time.Duration
+	RetryMax  time.Duration
+	// RetryBudget caps the retries of the whole run (0 = unlimited), so a
+	// dying server can't stretch every query to Retries attempts; once it is
+	// spent, failures are recorded without retrying.
+	RetryBudget int
+	retryBudget *retryBudget
+	// Interval is the minimum gap between query starts (0 = no pacing).
+	Interval time.Duration
+	// Adaptive tunes concurrency from query latency, treating Parallel as
+	// the ceiling (see tuner).
+	Adaptive bool
+	// Checkpoint, when set, supplies results recorded by an earlier run and
+	// records each successful query.
+	Checkpoint *Checkpoint
+	// RunID tags every transaction's metadata so the watchdog (and a DBA)
+	// can tell this run's queries apart.
+	RunID string
+	// KillRunaway starts a watchdog terminating this run's transactions
+	// still running on the server past PerQueryTimeout (see watch).
+	KillRunaway bool
+	// Budget caps the rows and approximate bytes kept per query.
+	Budget Budget
+	// Cache, when set, supplies results cached within its TTL and caches each
+	// successful query.
+	Cache *Cache
 }
 
 func Run(
@@ -41,8 +102,30 @@ func Run(
 	driver neo4j.DriverWithContext,
 	jobs []QueryJob,
 	opts RunnerOpts,
-	exec func(context.Context, neo4j.SessionWithContext, string, int) (ResultSet, error),
+	exec ExecFunc,
 ) []QueryResult {
+	return runJobs(ctx, driver, jobs, opts, nil, func(ctx context.Context, sess *session, job QueryJob, opts RunnerOpts) (ResultSet, int, error) {
+		var rs ResultSet
+		var err error
+		if job.Paged && opts.PageSize > 0 {
+			rs, err = execPaged(ctx, sess, job, opts, exec)
+		} else {
+			rs, err = execWithRetries(ctx, sess, job.Cypher, job.Params, opts.execOpts(job, opts.Limit, opts.Budget), opts, exec)
+		}
+		return rs, len(rs.Rows), err
+	})
+}
+
+// jobFunc executes one job on a worker's session, returning its result and
+// how many rows the server returned.
+type jobFunc func(ctx context.Context, sess *session, job QueryJob, opts RunnerOpts) (ResultSet, int, error)
+
+// runJobs is the worker pool behind Run and RunStream: Parallel workers (or
+// the tuner's share of them) each run do for the jobs they pick up. Results
+// come from and go to opts.Checkpoint and opts.Cache when set. done, when
+// set, is called after each job, including jobs never started, possibly from
+// several goroutines at once.
+func runJobs(ctx context.Context, driver neo4j.DriverWithContext, jobs []QueryJob, opts RunnerOpts, done func(QueryJob, QueryResult), do jobFunc) []QueryResult {
 	if opts.Parallel < 1 {
 		opts.Parallel = 1
 	}
@@ -50,19 +133,31 @@ func Run(
 		opts.Retries = 0
 	}
 
+	opts.Progress = opts.progress(len(jobs))
+	defer opts.Progress.Close()
+	opts.retryBudget = newRetryBudget(opts.RetryBudget)
 	out := make([]QueryResult, len(jobs))
+	dispatched := make([]bool, len(jobs))
+	defer startWatchdog(ctx, driver, opts, jobs)()
 
 	jobsCh := make(chan QueryJob)
 	stopCh := make(chan struct{})
 	var stopOnce sync.Once
 	stop := func() { stopOnce.Do(func() { close(stopCh) }) }
 
+	var tune *tuner
+	if opts.Adaptive {
+		tune = newTuner(opts.Parallel, opts.PerQueryTimeout, opts.Progress)
+	}
+
 	var wg sync.WaitGroup
 	wg.Add(opts.Parallel)
 	for w := 0; w < opts.Parallel; w++ {
 		go func() {
 			defer wg.Done()
-			sess := driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: opts.DB})
+			// Read mode lets a neo4j:// (routing) driver spread the queries
+			// across cluster followers.
+			sess := newSession(ctx, driver, neo4j.SessionConfig{DatabaseName: opts.DB, AccessMode: neo4j.AccessModeRead})
 			defer sess.Close(ctx)
 
 			for {
@@ -73,19 +168,42 @@ func Run(
 					if !ok {
 						return
 					}
-					if opts.Verbose {
-						fmt.Fprintf(os.Stderr, "[+] (%d/%d) %s [%s]\n", job.Index+1, len(jobs), job.Name, job.ID)
+					opts := opts.ForJob(job)
+					if tune != nil {
+						tune.acquire()
 					}
+					opts.Progress.Start(w, job)
 					qctx := ctx
 					var cancel context.CancelFunc
 					if opts.PerQueryTimeout > 0 {
 						qctx, cancel = context.WithTimeout(ctx, opts.PerQueryTimeout)
 					}
-					rs, err := execWithRetries(qctx, sess, job.Cypher, opts.Limit, opts.Retries, exec)
+					start := time.Now()
+					var rs ResultSet
+					var rows int
+					err := guard(job, opts, func() (err error) {
+						rs, rows, err = do(qctx, sess, job, opts)
+						return err
+					})
+					if _, ok := err.(*panicError); ok {
+						// The panic may have left a transaction open.
+						sess.reopen(ctx)
+					}
 					if cancel != nil {
 						cancel()
 					}
-					out[job.Index] = QueryResult{ResultSet: rs, Err: err}
+					took := time.Since(start)
+					if tune != nil {
+						tune.release(took, err)
+					}
+					out[job.Index] = QueryResult{ResultSet: rs, Err: err, Duration: took, Rows: rows}
+					opts.Progress.Done(w, job, out[job.Index], "")
+					if done != nil {
+						done(job, out[job.Index])
+					}
+					if err == nil {
+						record(job, opts, out[job.Index])
+					}
 					if err != nil && opts.FailFast {
 						stop()
 					}
@@ -94,49 +212,292 @@ func Run(
 		}()
 	}
 
+	dispatchDone := make(chan struct{})
 	go func() {
+		defer close(dispatchDone)
 		defer close(jobsCh)
+		pace := pacer{interval: opts.Interval}
 		for _, job := range jobs {
+			if r, from, ok := lookupRecorded(job, opts.ForJob(job)); ok {
+				dispatched[job.Index] = true
+				out[job.Index] = r
+				opts.Progress.Done(-1, job, r, from)
+				if done != nil {
+					done(job, r)
+				}
+				continue
+			}
+			if pace.wait(ctx) != nil {
+				return
+			}
 			select {
 			case <-stopCh:
 				return
+			case <-ctx.Done():
+				return
 			case jobsCh <- job:
+				dispatched[job.Index] = true
 			}
 		}
 	}()
 
 	wg.Wait()
+	<-dispatchDone
+	// Jobs never started report why instead of looking like empty results.
+	for _, job := range jobs {
+		if !dispatched[job.Index] {
+			out[job.Index] = notRun(ctx)
+			if done != nil {
+				done(job, out[job.Index])
+			}
+		}
+	}
 	return out
 }
 
-func execWithRetries(ctx context.Context, sess neo4j.SessionWithContext, cypher string, limit int, retries int, exec func(context.Context, neo4j.SessionWithContext, string, int) (ResultSet, error)) (ResultSet, error) {
-	var lastErr error
-	for attempt := 0; attempt <= retries; attempt++ {
-		rs, err := exec(ctx, sess, cypher, limit)
+// notRun is the result of a job the run never started.
+func notRun(ctx context.Context) QueryResult {
+	err := ctx.Err()
+	if err == nil {
+		err = ErrNotRun
+	}
+	return QueryResult{Err: err, NotRun: true}
+}
+
+// ForJob returns o with job's own Limit and Timeout in place of the run-wide
+// ones where the job sets them.
+func (o RunnerOpts) ForJob(job QueryJob) RunnerOpts {
+	if job.Limit > 0 {
+		o.Limit = job.Limit
+	}
+	if job.Timeout > 0 {
+		o.PerQueryTimeout = job.Timeout
+	}
+	return o
+}
+
+// progress returns opts.Progress, defaulting to plain stderr lines.
+func (o RunnerOpts) progress(total int) Progress {
+	if o.Progress != nil {
+		return o.Progress
+	}
+	return NewLines(os.Stderr, total, !o.Verbose)
+}
+
+// execOpts returns the ExecOpts of one execution of job, tagging its
+// transaction with the run and query id.
+func (o RunnerOpts) execOpts(job QueryJob, limit int, budget Budget) ExecOpts {
+	meta := map[string]any{txMetaApp: txMetaAppName, "query": job.ID}
+	if o.RunID != "" {
+		meta["run"] = o.RunID
+	}
+	return ExecOpts{Limit: limit, Budget: budget, TxMeta: meta, Write: job.Write}
+}
+
+// lookupRecorded returns job's result from the checkpoint or the cache,
+// naming where it came from. A cache hit is also checkpointed so a resumed
+// run does not depend on the entry still being fresh.
+func lookupRecorded(job QueryJob, opts RunnerOpts) (QueryResult, string, bool) {
+	if opts.Checkpoint != nil {
+		if r, ok := opts.Checkpoint.Lookup(job, opts.Limit); ok {
+			return r, "checkpoint", true
+		}
+	}
+	if opts.Cache != nil && !job.Write {
+		if r, age, ok := opts.Cache.Lookup(job, opts.Limit); ok {
+			if opts.Checkpoint != nil {
+				if err := opts.Checkpoint.Save(job, opts.Limit, r); err != nil {
+					opts.Progress.Logf("[!] checkpoint %s: %v", job.ID, err)
+				}
+			}
+			return r, fmt.Sprintf("cache (%s old)", age.Round(time.Second)), true
+		}
+	}
+	return QueryResult{}, "", false
+}
+
+// record saves a successful result to the checkpoint and the cache.
+func record(job QueryJob, opts RunnerOpts, r QueryResult) {
+	if opts.Checkpoint != nil {
+		if err := opts.Checkpoint.Save(job, opts.Limit, r); err != nil {
+			opts.Progress.Logf("[!] checkpoint %s: %v", job.ID, err)
+		}
+	}
+	if opts.Cache != nil && !job.Write {
+		if err := opts.Cache.Store(job, opts.Limit, r); err != nil {
+			opts.Progress.Logf("[!] cache %s: %v", job.ID, err)
+		}
+	}
+}
+
+// execPaged fetches a paged query one page (and transaction) at a time until
+// a short page, the row limit or the budget is reached. Pages after the one
+// that spent the budget are not fetched, so Dropped is then a lower bound.
+func execPaged(ctx context.Context, sess *session, job QueryJob, opts RunnerOpts, exec ExecFunc) (ResultSet, error) {
+	var out ResultSet
+	var size int64
+	var after any = ""
+	for {
+		params := pageParams(job.Params, after, opts.PageSize)
+		page, err := execWithRetries(ctx, sess, job.Cypher, params, opts.execOpts(job, 0, opts.Budget.less(len(out.Rows), size)), opts, exec)
+		if err != nil {
+			return ResultSet{}, fmt.Errorf("page at row %d: %w", len(out.Rows), err)
+		}
+		if out.Columns == nil && len(page.Columns) > 0 {
+			out.Columns = page.Columns[:len(page.Columns)-1]
+		}
+		for i, row := range page.Rows {
+			page.Rows[i], after = cutPageKey(row)
+		}
+		out.Rows = append(out.Rows, page.Rows...)
+		if page.Dropped > 0 {
+			out.Dropped, out.Truncated = page.Dropped, true
+			return out, nil
+		}
+		for _, row := range page.Rows {
+			size += rowSize(row)
+		}
+		if opts.Limit > 0 && len(out.Rows) > opts.Limit {
+			out.Rows = out.Rows[:opts.Limit]
+			out.Truncated = true
+			return out, nil
+		}
+		if len(page.Rows) < opts.PageSize {
+			return out, nil
+		}
+	}
+}
+
+// Prepared returns the Cypher and parameters that job's first execution (or
+// first page) sends to the server under opts, e.g. for a dry run.
+func Prepared(job QueryJob, opts RunnerOpts) (string, map[string]any) {
+	opts = opts.ForJob(job)
+	if job.Paged && opts.PageSize > 0 {
+		return job.Cypher, pageParams(job.Params, "", opts.PageSize)
+	}
+	return withLimit(job.Cypher, job.Params, opts.Limit)
+}
+
+// pageParams adds the $after and $pageSize parameters of a paged query; the
+// first page starts after "", below every objectid.
+func pageParams(params map[string]any, after any, size int) map[string]any {
+	p := make(map[string]any, len(params)+2)
+	for k, v := range params {
+		p[k] = v
+	}
+	p[queries.PageAfterParam], p[queries.PageSizeParam] = after, size
+	return p
+}
+
+// cutPageKey splits a paged query's row into its columns and its page key.
+func cutPageKey(row []any) ([]any, any) {
+	if len(row) == 0 {
+		return row, nil
+	}
+	return row[:len(row)-1], row[len(row)-1]
+}
+
+// execWithRetries runs exec, retrying transient errors (see withRetries).
+func execWithRetries(ctx context.Context, sess *session, cypher string, params map[string]any, eo ExecOpts, opts RunnerOpts, exec ExecFunc) (ResultSet, error) {
+	var rs ResultSet
+	err := withRetries(ctx, sess, eo.TxMeta["query"], opts, func() (bool, error) {
+		var err error
+		rs, err = exec(ctx, sess.get(), cypher, params, eo)
+		return false, err
+	})
+	if err != nil {
+		return ResultSet{}, err
+	}
+	return rs, nil
+}
+
+// withRetries calls try until it succeeds, retrying transient errors with
+// backoff. When the connection is lost the session is reopened first, and
+// the first loss earns one attempt beyond opts.Retries. A failure after try
+// delivered rows (partial) is not retried, since they can't be taken back,
+// though the session is still reopened for the next job.
+func withRetries(ctx context.Context, sess *session, id any, opts RunnerOpts, try func() (partial bool, err error)) error {
+	retries, reopened := opts.Retries, false
+	for attempt := 0; ; attempt++ {
+		partial, err := try()
 		if err == nil {
-			return rs, nil
+			return nil
 		}
-		lastErr = err
 		if ctx.Err() != nil {
-			return ResultSet{}, ctx.Err()
+			return ctx.Err()
 		}
-		if !looksTransient(err) {
-			return ResultSet{}, err
+		lost := connectionLost(err)
+		if lost {
+			opts.Progress.Logf("[!] %v: connection lost, reopening session: %v", id, err)
+			sess.reopen(ctx)
+			if !reopened {
+				retries++
+				reopened = true
+			}
+		}
+		if partial || !lost && !looksTransient(err) || attempt >= retries || !opts.retryBudget.take(opts.Progress) {
+			return err
 		}
-		// small backoff
-		sleep := time.Duration(200*(attempt+1)) * time.Millisecond
-		t := time.NewTimer(sleep)
+		t := time.NewTimer(backoff(attempt, opts.RetryBase, opts.RetryMax))
 		select {
 		case <-ctx.Done():
 			t.Stop()
-			return ResultSet{}, ctx.Err()
+			return ctx.Err()
 		case <-t.C:
 		}
 	}
-	return ResultSet{}, lastErr
+}
+
+// retryBudget is the run-wide retry allowance shared by all workers; a nil
+// budget is unlimited.
+type retryBudget struct {
+	size   int
+	left   atomic.Int64
+	warned atomic.Bool
+}
+
+func newRetryBudget(n int) *retryBudget {
+	if n <= 0 {
+		return nil
+	}
+	b := &retryBudget{size: n}
+	b.left.Store(int64(n))
+	return b
+}
+
+// take spends one retry, reporting false once the budget is exhausted.
+func (b *retryBudget) take(p Progress) bool {
+	if b == nil || b.left.Add(-1) >= 0 {
+		return true
+	}
+	if !b.warned.Swap(true) {
+		p.Logf("[!] Run-wide retry budget of %d spent; further failures are not retried", b.size)
+	}
+	return false
+}
+
+// backoff returns the delay before retry attempt+1: base*2^attempt capped at
+// max, with "equal jitter" (a random point in the upper half) so parallel
+// workers hitting the same flaky link don't retry in lockstep.
+func backoff(attempt int, base, max time.Duration) time.Duration {
+	if base <= 0 {
+		base = 200 * time.Millisecond
+	}
+	if max <= 0 {
+		max = 10 * time.Second
+	}
+	d := max
+	if attempt < 30 && base<<attempt < max {
+		d = base << attempt
+	}
+	return d/2 + rand.N(d/2+1)
 }
 
 func looksTransient(err error) bool {
+	// The driver knows which server and connectivity errors are worth retrying.
+	if neo4j.IsRetryable(err) {
+		return true
+	}
 	var neo4jErr *neo4j.Neo4jError
 	if errors.As(err, &neo4jErr) {
 		if neo4jErr.Classification() == "TransientError" {
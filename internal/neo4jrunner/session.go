@@ -0,0 +1,58 @@
+package neo4jrunner
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// session is a worker's Neo4j session that is replaced when the connection
+// under it is lost (VPN blip, server restart), so one drop doesn't fail every
+// job the worker has left.
+type session struct {
+	driver neo4j.DriverWithContext
+	cfg    neo4j.SessionConfig
+	sess   neo4j.SessionWithContext
+}
+
+func newSession(ctx context.Context, driver neo4j.DriverWithContext, cfg neo4j.SessionConfig) *session {
+	return &session{driver: driver, cfg: cfg, sess: driver.NewSession(ctx, cfg)}
+}
+
+func (s *session) get() neo4j.SessionWithContext { return s.sess }
+
+// reopen discards the current session and starts a new one; the driver
+// dials a fresh connection for its next query.
+func (s *session) reopen(ctx context.Context) {
+	_ = s.sess.Close(ctx)
+	s.sess = s.driver.NewSession(ctx, s.cfg)
+}
+
+func (s *session) Close(ctx context.Context) error { return s.sess.Close(ctx) }
+
+// connectionLost reports whether err means the connection to the server
+// went away, as opposed to the query itself failing.
+func connectionLost(err error) bool {
+	var ce *neo4j.ConnectivityError
+	if errors.As(err, &ce) {
+		return true
+	}
+	// Managed transactions give up with the errors of every attempt.
+	var tl *neo4j.TransactionExecutionLimit
+	if errors.As(err, &tl) {
+		for _, e := range tl.Errors {
+			if connectionLost(e) {
+				return true
+			}
+		}
+	}
+	msg := strings.ToLower(err.Error())
+	for _, sub := range []string{"connection refused", "broken pipe", "reset by peer", "unexpected eof", "serviceunavailable"} {
+		if strings.Contains(msg, sub) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package neo4jrunner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// RowFunc receives one streamed row. cols is the same slice for every row of
+// a query; row must not be retained after the call returns.
+type RowFunc func(job QueryJob, cols []string, row []any) error
+
+// StreamCypher runs cypher in an auto-commit transaction and hands each row
+// to fn as it arrives instead of buffering the result. It returns the number
+// of rows delivered and whether eo.Limit cut the result off. eo.Budget does
+// not apply since no rows are held.
+func StreamCypher(ctx context.Context, sess neo4j.SessionWithContext, cypher string, params map[string]any, eo ExecOpts, fn func(cols []string, row []any) error) (int, bool, error) {
+	limit := eo.Limit
+	cy, params := withLimit(cypher, params, limit)
+	res, err := sess.Run(ctx, cy, params, neo4j.WithTxMetadata(eo.TxMeta))
+	if err != nil {
+		return 0, false, err
+	}
+	n := 0
+	var cols []string
+	var row []any
+	for res.Next(ctx) {
+		if limit > 0 && n >= limit {
+			_, _ = res.Consume(ctx)
+			return n, true, nil
+		}
+		rec := res.Record()
+		if cols == nil {
+			cols = append([]string(nil), rec.Keys...)
+			row = make([]any, len(cols))
+		}
+		copy(row, rec.Values)
+		if err := fn(cols, row); err != nil {
+			_, _ = res.Consume(ctx)
+			return n, false, err
+		}
+		n++
+	}
+	return n, false, res.Err()
+}
+
+// Stream runs jobs one at a time, delivering rows to fn as they arrive so
+// memory stays flat regardless of result size; done is called after each
+// job, including jobs never started (QueryResult.NotRun). It is RunStream
+// over ExecStream with Parallel 1.
+func Stream(ctx context.Context, driver neo4j.DriverWithContext, jobs []QueryJob, opts RunnerOpts, fn RowFunc, done func(QueryJob, QueryResult)) []QueryResult {
+	opts.Parallel = 1
+	return RunStream(ctx, driver, jobs, opts, ExecStream, fn, done)
+}
+
+var errLimitReached = errors.New("row limit reached")
+
+// StreamExecFunc runs one query like ExecFunc but hands each row to emit as
+// it arrives instead of collecting it. The returned ResultSet carries
+// Columns and Truncated but no rows; emit's row must not be retained.
+type StreamExecFunc func(ctx context.Context, sess neo4j.SessionWithContext, cypher string, params map[string]any, eo ExecOpts, emit func(cols []string, row []any) error) (ResultSet, error)
+
+// ExecStream is the StreamExecFunc over StreamCypher.
+func ExecStream(ctx context.Context, sess neo4j.SessionWithContext, cypher string, params map[string]any, eo ExecOpts, emit func(cols []string, row []any) error) (ResultSet, error) {
+	var cols []string
+	_, truncated, err := StreamCypher(ctx, sess, cypher, params, eo, func(c []string, row []any) error {
+		cols = c
+		return emit(c, row)
+	})
+	return ResultSet{Columns: cols, Truncated: truncated}, err
+}
+
+// RunStream is Run for consumers that process rows as they arrive rather
+// than holding whole results: exec emits each row, which is passed to fn
+// with its job. Jobs run Parallel at a time, so fn and done are called from
+// several goroutines, though one job's rows arrive in order from a single
+// one. Checkpoint and Cache are not used (there are no rows to record), and
+// a failed query is only retried when it had not emitted any rows yet. The
+// returned results carry no rows; Columns is set once a job has finished.
+func RunStream(ctx context.Context, driver neo4j.DriverWithContext, jobs []QueryJob, opts RunnerOpts, exec StreamExecFunc, fn RowFunc, done func(QueryJob, QueryResult)) []QueryResult {
+	opts.Checkpoint, opts.Cache = nil, nil
+	return runJobs(ctx, driver, jobs, opts, done, func(ctx context.Context, sess *session, job QueryJob, opts RunnerOpts) (ResultSet, int, error) {
+		n := 0
+		emit := func(cols []string, row []any) error {
+			n++
+			return fn(job, cols, row)
+		}
+		var rs ResultSet
+		err := withRetries(ctx, sess, job.ID, opts, func() (bool, error) {
+			var err error
+			if job.Paged && opts.PageSize > 0 {
+				rs, err = execStreamPaged(ctx, sess, job, opts, exec, emit)
+			} else {
+				rs, err = exec(ctx, sess.get(), job.Cypher, job.Params, opts.execOpts(job, opts.Limit, Budget{}), emit)
+			}
+			return n > 0, err
+		})
+		return rs, n, err
+	})
+}
+
+// execStreamPaged streams a paged query through exec one page at a time.
+func execStreamPaged(ctx context.Context, sess *session, job QueryJob, opts RunnerOpts, exec StreamExecFunc, emit func([]string, []any) error) (ResultSet, error) {
+	var out ResultSet
+	total := 0
+	var after any = ""
+	for {
+		size := opts.PageSize
+		if opts.Limit > 0 && total+size > opts.Limit {
+			// one extra row tells whether the limit truncated the result
+			size = opts.Limit - total + 1
+		}
+		n := 0
+		page, err := exec(ctx, sess.get(), job.Cypher, pageParams(job.Params, after, size), opts.execOpts(job, 0, Budget{}), func(cols []string, row []any) error {
+			if opts.Limit > 0 && total >= opts.Limit {
+				return errLimitReached
+			}
+			n++
+			total++
+			row, after = cutPageKey(row)
+			return emit(cols[:len(cols)-1], row)
+		})
+		if out.Columns == nil && len(page.Columns) > 0 {
+			out.Columns = page.Columns[:len(page.Columns)-1]
+		}
+		if errors.Is(err, errLimitReached) {
+			out.Truncated = true
+			return out, nil
+		}
+		if err != nil {
+			return out, fmt.Errorf("page at row %d: %w", total, err)
+		}
+		if n < size {
+			return out, nil
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package neo4jrunner
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func TestRunStream(t *testing.T) {
+	driver, err := neo4j.NewDriverWithContext("bolt://127.0.0.1:1", neo4j.NoAuth())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer driver.Close(context.Background())
+
+	exec := func(ctx context.Context, sess neo4j.SessionWithContext, cypher string, params map[string]any, eo ExecOpts, emit func(cols []string, row []any) error) (ResultSet, error) {
+		for i := range 3 {
+			if err := emit([]string{"q", "i"}, []any{cypher, int64(i)}); err != nil {
+				return ResultSet{}, err
+			}
+		}
+		return ResultSet{Columns: []string{"q", "i"}}, nil
+	}
+	jobs := []QueryJob{{Index: 0, ID: "a", Cypher: "a"}, {Index: 1, ID: "b", Cypher: "b"}}
+	opts := RunnerOpts{Parallel: 2, Progress: NewLines(io.Discard, len(jobs), true)}
+	var mu sync.Mutex
+	got := map[string][]int64{}
+	out := RunStream(context.Background(), driver, jobs, opts, exec, func(job QueryJob, cols []string, row []any) error {
+		mu.Lock()
+		defer mu.Unlock()
+		got[job.ID] = append(got[job.ID], row[1].(int64))
+		return nil
+	}, nil)
+	for _, job := range jobs {
+		r := out[job.Index]
+		if r.Err != nil || r.Rows != 3 || len(r.ResultSet.Rows) != 0 || len(r.ResultSet.Columns) != 2 {
+			t.Fatalf("%s: %+v", job.ID, r)
+		}
+		if rows := got[job.ID]; len(rows) != 3 || rows[0] != 0 || rows[2] != 2 {
+			t.Fatalf("%s: rows %v", job.ID, rows)
+		}
+	}
+}
+
+func TestRunStreamPaged(t *testing.T) {
+	driver, err := neo4j.NewDriverWithContext("bolt://127.0.0.1:1", neo4j.NoAuth())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer driver.Close(context.Background())
+
+	keys := []string{"a", "b", "c", "d", "e"}
+	var afters []any
+	exec := func(ctx context.Context, sess neo4j.SessionWithContext, cypher string, params map[string]any, eo ExecOpts, emit func(cols []string, row []any) error) (ResultSet, error) {
+		after := params["after"].(string)
+		afters = append(afters, after)
+		n := 0
+		for _, k := range keys {
+			if k <= after || n == params["pageSize"].(int) {
+				continue
+			}
+			n++
+			if err := emit([]string{"name", "pageKey"}, []any{"n" + k, k}); err != nil {
+				return ResultSet{}, err
+			}
+		}
+		return ResultSet{Columns: []string{"name", "pageKey"}}, nil
+	}
+	jobs := []QueryJob{{Index: 0, ID: "a", Cypher: "paged", Paged: true}}
+	opts := RunnerOpts{PageSize: 2, Progress: NewLines(io.Discard, len(jobs), true)}
+	var got []any
+	out := RunStream(context.Background(), driver, jobs, opts, exec, func(job QueryJob, cols []string, row []any) error {
+		if len(cols) != 1 || len(row) != 1 {
+			t.Fatalf("page key not cut: %v %v", cols, row)
+		}
+		got = append(got, row[0])
+		return nil
+	}, nil)
+	if r := out[0]; r.Err != nil || r.Rows != 5 || len(r.ResultSet.Columns) != 1 {
+		t.Fatalf("%+v", r)
+	}
+	if len(got) != 5 || got[4] != "ne" {
+		t.Fatalf("rows %v", got)
+	}
+	if len(afters) != 3 || afters[0] != "" || afters[1] != "b" || afters[2] != "d" {
+		t.Fatalf("pages after %v", afters)
+	}
+}
@@ -1,8 +1,20 @@
 package neo4jrunner
 
+import (
+	"fmt"
+	"strings"
+)
+
 type ResultSet struct {
 	Columns []string
 	Rows    [][]any
+	// Truncated is set when the row limit cut off further results.
+	Truncated bool
+	// Dropped is a lower bound on the rows discarded because the result
+	// exceeded its Budget; the rest of the result is not read.
+	Dropped int
+	// Plan is set, instead of rows, by PlanExec.
+	Plan *Plan
 }
 
 func (rs ResultSet) ColumnIndex() map[string]int {
@@ -12,3 +24,38 @@ func (rs ResultSet) ColumnIndex() map[string]int {
 	}
 	return m
 }
+
+// Dedupe drops rows identical to an earlier row, keeping first-seen order.
+// When countCol is set, a column of that name is appended holding how many
+// times each remaining row occurred.
+func (rs ResultSet) Dedupe(countCol string) ResultSet {
+	seen := make(map[string]int, len(rs.Rows))
+	var counts []int64
+	rows := make([][]any, 0, len(rs.Rows))
+	for _, row := range rs.Rows {
+		k := rowIdentity(row)
+		if i, ok := seen[k]; ok {
+			counts[i]++
+			continue
+		}
+		seen[k] = len(rows)
+		rows = append(rows, row)
+		counts = append(counts, 1)
+	}
+	out := ResultSet{Columns: rs.Columns, Rows: rows, Truncated: rs.Truncated, Dropped: rs.Dropped}
+	if countCol != "" {
+		out.Columns = append(append([]string(nil), rs.Columns...), countCol)
+		for i, row := range rows {
+			rows[i] = append(append(make([]any, 0, len(row)+1), row...), counts[i])
+		}
+	}
+	return out
+}
+
+func rowIdentity(row []any) string {
+	var b strings.Builder
+	for _, v := range row {
+		fmt.Fprintf(&b, "%#v\x00", v)
+	}
+	return b.String()
+}
@@ -0,0 +1,170 @@
+package neo4jrunner
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// Transaction metadata identifying goBloodyEll's queries on the server.
+const (
+	txMetaApp     = "app"
+	txMetaAppName = "goBloodyEll"
+)
+
+const (
+	// watchInterval is how often the watchdog polls the server.
+	watchInterval = 5 * time.Second
+	// watchGrace is how long past the per-query timeout a transaction may
+	// run before it is terminated, leaving the client time to cancel it.
+	watchGrace = 5 * time.Second
+)
+
+// Neo4j 5 (and 4.4) list and terminate transactions with SHOW/TERMINATE;
+// older servers only have the dbms procedures.
+const (
+	showRunaway = `SHOW TRANSACTIONS YIELD transactionId, metaData, elapsedTime
+WHERE metaData.app = $app AND metaData.run = $run AND elapsedTime.milliseconds > $ms
+RETURN transactionId, metaData.query AS query, elapsedTime.milliseconds AS ms`
+	terminateTransactions = `TERMINATE TRANSACTIONS $ids`
+
+	legacyListRunaway = `CALL dbms.listTransactions() YIELD transactionId, metaData, elapsedTimeMillis
+WHERE metaData.app = $app AND metaData.run = $run AND elapsedTimeMillis > $ms
+RETURN transactionId, metaData.query AS query, elapsedTimeMillis AS ms`
+	legacyKillTransactions = `CALL dbms.killTransactions($ids) YIELD transactionId RETURN transactionId`
+)
+
+// runaway is a server transaction of this run past its deadline.
+type runaway struct {
+	ID      string
+	QueryID string
+	Elapsed time.Duration
+}
+
+// startWatchdog runs watch when opts ask for it; the returned func stops it
+// and waits for it to finish.
+func startWatchdog(ctx context.Context, driver neo4j.DriverWithContext, opts RunnerOpts, jobs []QueryJob) func() {
+	if !opts.KillRunaway || opts.RunID == "" || opts.PerQueryTimeout <= 0 {
+		return func() {}
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		watch(ctx, driver, opts, jobs)
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// watch terminates this run's transactions that are still running on the
+// server longer than the per-query timeout (plus watchGrace) until ctx is
+// done. Cancelling the client context alone does not always stop the server
+// from working on a query. It needs opts.RunID and opts.PerQueryTimeout;
+// jobs with their own Timeout get that instead.
+func watch(ctx context.Context, driver neo4j.DriverWithContext, opts RunnerOpts, jobs []QueryJob) {
+	sess := driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: opts.DB})
+	defer sess.Close(ctx)
+	// The server lists transactions past the shortest threshold; each one is
+	// then checked against its own job's deadline.
+	thresholds := map[string]time.Duration{}
+	global := opts.PerQueryTimeout + watchGrace
+	threshold := global
+	for _, job := range jobs {
+		if job.Timeout > 0 {
+			thresholds[job.ID] = job.Timeout + watchGrace
+			threshold = min(threshold, thresholds[job.ID])
+		}
+	}
+	legacy := false
+
+	t := time.NewTicker(watchInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+		found, err := listRunaway(ctx, sess, opts.RunID, threshold, legacy)
+		if err != nil && !legacy && ctx.Err() == nil {
+			legacy = true
+			found, err = listRunaway(ctx, sess, opts.RunID, threshold, legacy)
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				opts.Progress.Logf("[!] watchdog disabled: cannot list server transactions: %v", err)
+			}
+			return
+		}
+		found = overdue(found, thresholds, global)
+		if len(found) == 0 {
+			continue
+		}
+		ids := make([]string, len(found))
+		for i, r := range found {
+			ids[i] = r.ID
+		}
+		if err := terminate(ctx, sess, ids, legacy); err != nil {
+			if ctx.Err() == nil {
+				opts.Progress.Logf("[!] watchdog: terminating %d runaway transaction(s) failed: %v", len(ids), err)
+			}
+			continue
+		}
+		for _, r := range found {
+			opts.Progress.Logf("[!] watchdog: terminated runaway transaction %s (%s, running %s)", r.ID, r.QueryID, r.Elapsed.Round(time.Second))
+		}
+	}
+}
+
+// overdue keeps the transactions running longer than their job's threshold,
+// or the global one for jobs without their own timeout.
+func overdue(found []runaway, thresholds map[string]time.Duration, global time.Duration) []runaway {
+	return slices.DeleteFunc(found, func(r runaway) bool {
+		t, ok := thresholds[r.QueryID]
+		if !ok {
+			t = global
+		}
+		return r.Elapsed <= t
+	})
+}
+
+func listRunaway(ctx context.Context, sess neo4j.SessionWithContext, runID string, threshold time.Duration, legacy bool) ([]runaway, error) {
+	cy := showRunaway
+	if legacy {
+		cy = legacyListRunaway
+	}
+	res, err := sess.Run(ctx, cy, map[string]any{txMetaApp: txMetaAppName, "run": runID, "ms": threshold.Milliseconds()})
+	if err != nil {
+		return nil, err
+	}
+	recs, err := res.Collect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]runaway, 0, len(recs))
+	for _, rec := range recs {
+		id, _ := rec.Values[0].(string)
+		q, _ := rec.Values[1].(string)
+		ms, _ := rec.Values[2].(int64)
+		out = append(out, runaway{ID: id, QueryID: q, Elapsed: time.Duration(ms) * time.Millisecond})
+	}
+	return out, nil
+}
+
+func terminate(ctx context.Context, sess neo4j.SessionWithContext, ids []string, legacy bool) error {
+	cy := terminateTransactions
+	if legacy {
+		cy = legacyKillTransactions
+	}
+	res, err := sess.Run(ctx, cy, map[string]any{"ids": ids})
+	if err != nil {
+		return err
+	}
+	_, err = res.Consume(ctx)
+	return err
+}
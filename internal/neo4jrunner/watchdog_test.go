@@ -0,0 +1,23 @@
+package neo4jrunner
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOverdueUsesEachJobsThreshold(t *testing.T) {
+	// A short per-job timeout lowers the server-side listing threshold, but
+	// jobs without one must still get the global timeout.
+	thresholds := map[string]time.Duration{"fast": 10 * time.Second}
+	global := 65 * time.Second
+	found := []runaway{
+		{ID: "tx1", QueryID: "fast", Elapsed: 12 * time.Second},
+		{ID: "tx2", QueryID: "slow", Elapsed: 12 * time.Second},
+		{ID: "tx3", QueryID: "slow", Elapsed: 70 * time.Second},
+		{ID: "tx4", QueryID: "fast", Elapsed: 8 * time.Second},
+	}
+	got := overdue(found, thresholds, global)
+	if len(got) != 2 || got[0].ID != "tx1" || got[1].ID != "tx3" {
+		t.Fatalf("overdue = %+v, want tx1 and tx3", got)
+	}
+}
@@ -0,0 +1,13 @@
+package queries
+
+// UseAPOC returns in with each query's APOCCypher, where it has one, in place
+// of its Cypher.
+func UseAPOC(in []Query) []Query {
+	out := append([]Query(nil), in...)
+	for i, q := range out {
+		if q.APOCCypher != "" {
+			out[i].Cypher = q.APOCCypher
+		}
+	}
+	return out
+}
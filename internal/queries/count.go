@@ -0,0 +1,13 @@
+package queries
+
+import "strings"
+
+// CountColumn is the single column returned by CountCypher.
+const CountColumn = "count"
+
+// CountCypher wraps a query in a CALL subquery that only returns how many
+// rows it produces, so the server does the counting and no rows are shipped.
+func CountCypher(cypher string) string {
+	cy := strings.TrimRight(strings.TrimSpace(cypher), ";")
+	return "CALL {\n" + cy + "\n}\nRETURN count(*) AS " + CountColumn
+}
@@ -0,0 +1,48 @@
+package queries
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Dialect is the Cypher flavour spoken by the target database.
+type Dialect string
+
+const (
+	Neo4j    Dialect = "neo4j"
+	Memgraph Dialect = "memgraph"
+)
+
+func ParseDialect(s string) (Dialect, error) {
+	switch d := Dialect(strings.ToLower(strings.TrimSpace(s))); d {
+	case "", Neo4j:
+		return Neo4j, nil
+	case Memgraph:
+		return d, nil
+	default:
+		return "", fmt.Errorf("unknown dialect %q (expected: neo4j|memgraph)", s)
+	}
+}
+
+// memgraphRewrites swap the Neo4j-only constructs the built-in queries use
+// for Memgraph equivalents: Memgraph has no datetime().epochseconds
+// (timestamp() is in microseconds) and no exists() on properties.
+var memgraphRewrites = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	{regexp.MustCompile(`(?i)\bdatetime\(\)\.epochseconds\b`), "(timestamp() / 1000000)"},
+	{regexp.MustCompile(`(?i)\bEXISTS\s*\(\s*(\w+\.\w+)\s*\)`), "$1 IS NOT NULL"},
+}
+
+// Rewrite adapts cypher to the dialect.
+func (d Dialect) Rewrite(cypher string) string {
+	if d != Memgraph {
+		return cypher
+	}
+	for _, r := range memgraphRewrites {
+		cypher = r.re.ReplaceAllString(cypher, r.repl)
+	}
+	return cypher
+}
@@ -0,0 +1,90 @@
+package queries
+
+import (
+	"regexp"
+	"strings"
+)
+
+// LimitParam is the parameter LimitCypher's row cap is bound to.
+const LimitParam = "limit"
+
+var reTrailingLimit = regexp.MustCompile(`(?is)\bLIMIT\s+(?:\d+|\$[A-Za-z_][A-Za-z0-9_]*)\s*;?\s*$`)
+
+// LimitCypher caps a query's rows at $limit. Queries that already end in a
+// LIMIT are returned unchanged (ok is false). Otherwise the query is wrapped
+// as CALL { ... } RETURN <columns> LIMIT $limit, which also covers UNION
+// queries; if its columns are not all aliased (CALL subqueries require
+// that), LIMIT $limit is appended instead. String literals and comments are
+// ignored when looking for an existing LIMIT.
+func LimitCypher(cypher string) (string, bool) {
+	cy := strings.TrimRight(strings.TrimSpace(cypher), ";")
+	if reTrailingLimit.MatchString(stripLiterals(cy)) {
+		return cy, false
+	}
+	if cols, ok := returnAliases(stripLiteralsKeepLen(cy)); ok {
+		list := strings.Join(cols, ", ")
+		return "CALL {\n" + cy + "\n}\nRETURN " + list + "\nLIMIT $" + LimitParam, true
+	}
+	return cy + "\nLIMIT $" + LimitParam, true
+}
+
+// stripLiterals blanks string literals and drops // and /* */ comments.
+func stripLiterals(s string) string {
+	var b strings.Builder
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+				b.WriteByte(c)
+			}
+			continue
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '/' && i+1 < len(s) && s[i+1] == '/':
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+			b.WriteByte('\n')
+			continue
+		case c == '/' && i+1 < len(s) && s[i+1] == '*':
+			end := strings.Index(s[i+2:], "*/")
+			if end < 0 {
+				return b.String()
+			}
+			i += end + 3
+			b.WriteByte(' ')
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// stripLiteralsKeepLen blanks the inside of string literals with spaces so
+// keywords inside strings are not matched, keeping everything else as is.
+func stripLiteralsKeepLen(s string) string {
+	b := []byte(s)
+	var quote byte
+	for i := 0; i < len(b); i++ {
+		c := b[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && i+1 < len(b) {
+				b[i], b[i+1] = ' ', ' '
+				i++
+			} else if c == quote {
+				quote = 0
+			} else {
+				b[i] = ' '
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,119 @@
+package queries
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var reBareColumn = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Problem is something wrong with a query definition found by Lint.
+type Problem struct {
+	ID  string
+	Msg string
+}
+
+// Lint checks query definitions for mistakes that otherwise only show at
+// run time: missing or duplicate IDs, unknown categories, headers whose
+// column keys aren't aliases of the final RETURN (and APOC variants
+// returning other columns), declared requirements the Cypher doesn't use,
+// page keys PagedCypher can't page on, and write clauses in queries not
+// marked Write.
+func Lint(qs []Query) []Problem {
+	var out []Problem
+	add := func(id, format string, args ...any) {
+		out = append(out, Problem{ID: id, Msg: fmt.Sprintf(format, args...)})
+	}
+	seen := map[string]int{}
+	for i, q := range qs {
+		if q.ID == "" {
+			add(fmt.Sprintf("#%d", i+1), "missing ID")
+		} else if j, dup := seen[q.ID]; dup {
+			add(q.ID, "duplicate ID (also query #%d)", j+1)
+		} else {
+			seen[q.ID] = i
+		}
+		switch strings.ToLower(q.Category) {
+		case "ad", "entraid", "info":
+		default:
+			add(q.ID, "unknown category %q (expected AD, EntraID or INFO)", q.Category)
+		}
+		if strings.TrimSpace(q.Cypher) == "" {
+			add(q.ID, "empty Cypher")
+			continue
+		}
+		if len(q.ColumnKeys) != len(q.Headers) {
+			add(q.ID, "ColumnKeys not resolved from Headers (missing WithResolvedKeys?)")
+		}
+		cols, ok := returnColumns(q.Cypher)
+		if !ok {
+			add(q.ID, "final RETURN has expressions without an alias")
+		} else {
+			aliases := map[string]bool{}
+			for _, c := range cols {
+				aliases[c] = true
+			}
+			for i, k := range q.ColumnKeys {
+				if !aliases[k] {
+					add(q.ID, "header %q (key %q) is not a RETURN alias (have %s)", q.Headers[i], k, strings.Join(cols, ", "))
+				}
+			}
+		}
+		if q.APOCCypher != "" {
+			if apoc, _ := returnColumns(q.APOCCypher); strings.Join(apoc, ",") != strings.Join(cols, ",") {
+				add(q.ID, "APOC variant returns %s, not %s", strings.Join(apoc, ", "), strings.Join(cols, ", "))
+			}
+		}
+		cy := stripLiterals(q.Cypher)
+		for _, l := range q.RequiresLabels {
+			if !regexp.MustCompile(`:` + regexp.QuoteMeta(l) + `\b`).MatchString(cy) {
+				add(q.ID, "requires label %s, which the Cypher does not use", l)
+			}
+		}
+		for _, r := range q.RequiresRels {
+			if !regexp.MustCompile(`[:|]` + regexp.QuoteMeta(r) + `\b`).MatchString(cy) {
+				add(q.ID, "requires relationship type %s, which the Cypher does not use", r)
+			}
+		}
+		for _, lp := range q.RequiresProps {
+			if _, prop, ok := strings.Cut(lp, "."); !ok || !regexp.MustCompile(`\b`+regexp.QuoteMeta(prop)+`\b`).MatchString(cy) {
+				add(q.ID, "requires property %q, which the Cypher does not use (want Label.prop)", lp)
+			}
+		}
+		if q.PageKey != "" {
+			if _, ok := q.PagedCypher(); !ok {
+				add(q.ID, "PageKey %q cannot be paged: want MATCH binding (%s...) [WHERE ...] RETURN <aliased columns> [ORDER BY ...]", q.PageKey, q.PageKey)
+			}
+		}
+		if c := WriteClause(q.Cypher + "\n" + q.APOCCypher); c != "" && !q.Write {
+			add(q.ID, "uses %s but is not marked Write", c)
+		}
+	}
+	return out
+}
+
+// returnColumns lists the column names of the query's last RETURN clause:
+// aliases, and variables returned as they are (RETURN label, ...).
+func returnColumns(cypher string) ([]string, bool) {
+	m := reLastReturn.FindStringSubmatch(stripLiteralsKeepLen(cypher))
+	if m == nil {
+		return nil, false
+	}
+	proj := m[1]
+	if loc := reReturnEnd.FindStringIndex(proj); loc != nil {
+		proj = proj[:loc[0]]
+	}
+	var cols []string
+	for _, item := range splitTopLevel(proj) {
+		item = strings.TrimSpace(item)
+		if a := reAlias.FindStringSubmatch(item); a != nil {
+			cols = append(cols, a[1])
+		} else if reBareColumn.MatchString(item) {
+			cols = append(cols, item)
+		} else {
+			return nil, false
+		}
+	}
+	return cols, len(cols) > 0
+}
@@ -0,0 +1,64 @@
+package queries
+
+// MaintenanceQueries update the graph instead of reading it: they mark
+// principals and groups the way an operator would in the BloodHound UI and
+// prune stale data. They are never part of a normal run; --maintenance
+// selects them and --allow-write must be given (see WriteClause).
+var MaintenanceQueries = []Query{
+	Query{
+		ID:          "maint-mark-owned",
+		Title:       "Mark principals as owned",
+		Category:    "INFO",
+		Severity:    "info",
+		SheetName:   "Marked Owned",
+		Headers:     []string{"Principal", "Type"},
+		Description: "Sets owned=true on the users, computers and groups named (or given by objectid) in $owned, a comma-separated list.",
+		Cypher: `UNWIND [p IN split($owned, ',') WHERE trim(p) <> '' | toUpper(trim(p))] AS p
+MATCH (n:Base)
+WHERE toUpper(n.name) = p OR toUpper(n.objectid) = p
+SET n.owned = true
+RETURN n.name AS principal, labels(n) AS type
+ORDER BY principal`,
+		Params:         map[string]any{"owned": ""},
+		Write:          true,
+		RequiresLabels: []string{"Base"},
+	}.WithResolvedKeys(),
+	Query{
+		ID:          "maint-tier-zero-highvalue",
+		Title:       "Mark custom Tier Zero groups high value",
+		Category:    "INFO",
+		Severity:    "info",
+		SheetName:   "Marked Tier Zero",
+		Headers:     []string{"Group"},
+		Description: "Sets highvalue=true on the groups in $tierZero (comma-separated names, with or without @DOMAIN, or objectids) so path queries treat them like the built-in Tier Zero groups.",
+		Cypher: `UNWIND [g IN split($tierZero, ',') WHERE trim(g) <> '' | toUpper(trim(g))] AS name
+MATCH (g:Group)
+WHERE toUpper(g.name) = name OR toUpper(g.name) STARTS WITH name + '@' OR toUpper(g.objectid) = name
+SET g.highvalue = true
+RETURN g.name AS group
+ORDER BY group`,
+		Params:         map[string]any{"tierZero": ""},
+		Write:          true,
+		RequiresLabels: []string{"Group"},
+	}.WithResolvedKeys(),
+	Query{
+		ID:          "maint-clear-stale-sessions",
+		Title:       "Clear stale sessions",
+		Category:    "INFO",
+		Severity:    "info",
+		SheetName:   "Cleared Sessions",
+		Headers:     []string{"Hostname", "User"},
+		Description: "Deletes HasSession edges from computers that have not logged on for $sessionDays days, so session-based paths reflect current activity.",
+		Cypher: `MATCH (c:Computer)-[s:HasSession]->(u:User)
+WHERE c.lastlogontimestamp > 0
+  AND c.lastlogontimestamp < (datetime().epochseconds - ($sessionDays * 86400))
+DELETE s
+RETURN c.name AS computer, u.name AS user
+ORDER BY computer, user`,
+		Params:         map[string]any{"sessionDays": 90},
+		Write:          true,
+		RequiresLabels: []string{"Computer", "User"},
+		RequiresRels:   []string{"HasSession"},
+		RequiresProps:  []string{"Computer.lastlogontimestamp"},
+	}.WithResolvedKeys(),
+}
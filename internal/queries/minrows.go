@@ -0,0 +1,58 @@
+package queries
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ParseMinRows parses --min-rows values: a bare number sets the global
+// threshold, "<query-id>=<n>" a per-query one.
+func ParseMinRows(specs []string) (int, map[string]int, error) {
+	global := 0
+	per := map[string]int{}
+	for _, s := range specs {
+		id, val, ok := strings.Cut(s, "=")
+		if !ok {
+			id, val = "", s
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(val))
+		if err != nil || n < 0 {
+			return 0, nil, fmt.Errorf("%q: expected <n> or <query-id>=<n>", s)
+		}
+		if id = strings.TrimSpace(id); id == "" {
+			global = n
+		} else {
+			per[id] = n
+		}
+	}
+	return global, per, nil
+}
+
+// ApplyMinRows sets MinRows on finding queries (INFO queries are not
+// findings): the per-query value when given, otherwise global. It returns the
+// per-query IDs that matched no query.
+func ApplyMinRows(in []Query, global int, per map[string]int) ([]Query, []string) {
+	out := append([]Query(nil), in...)
+	used := map[string]bool{}
+	for i, q := range out {
+		if strings.EqualFold(q.Category, "INFO") {
+			continue
+		}
+		if n, ok := per[q.ID]; ok {
+			out[i].MinRows = n
+			used[q.ID] = true
+		} else if global > 0 {
+			out[i].MinRows = global
+		}
+	}
+	var unknown []string
+	for id := range per {
+		if !used[id] {
+			unknown = append(unknown, id)
+		}
+	}
+	sort.Strings(unknown)
+	return out, unknown
+}
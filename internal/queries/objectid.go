@@ -0,0 +1,55 @@
+package queries
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ObjectIDSuffix marks the columns added by WithObjectIDs.
+const ObjectIDSuffix = "_objectid"
+
+// reNameProjection matches "x.name AS alias" (optionally wrapped, as in
+// "distinct(x.name) AS alias") in a RETURN clause.
+var reNameProjection = regexp.MustCompile(`\b([A-Za-z_][A-Za-z0-9_]*)\.(?:name|samaccountname)\)? AS ([A-Za-z_][A-Za-z0-9_]*)`)
+
+// WithObjectIDs appends "<alias>_objectid" columns carrying the objectid/SID
+// of each principal a query returns by name, so findings can be correlated
+// with AD objects even when names collide. Only single-line RETURN clauses
+// are rewritten; other queries are left unchanged.
+func WithObjectIDs(in []Query) []Query {
+	out := make([]Query, 0, len(in))
+	for _, q := range in {
+		qq := withObjectID(q)
+		if q.APOCCypher != "" {
+			qq.APOCCypher = withObjectID(Query{Cypher: q.APOCCypher}).Cypher
+		}
+		out = append(out, qq)
+	}
+	return out
+}
+
+func withObjectID(q Query) Query {
+	lines := strings.Split(q.Cypher, "\n")
+	ret := -1
+	for i, l := range lines {
+		if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(l)), "RETURN ") {
+			ret = i // the last RETURN is the query's projection
+		}
+	}
+	if ret < 0 || strings.HasSuffix(strings.TrimSpace(lines[ret]), ",") {
+		return q
+	}
+	matches := reNameProjection.FindAllStringSubmatch(lines[ret], -1)
+	if len(matches) == 0 {
+		return q
+	}
+	var extra []string
+	for _, m := range matches {
+		alias := m[2] + ObjectIDSuffix
+		extra = append(extra, m[1]+".objectid AS "+alias)
+		q.Headers = append(append([]string(nil), q.Headers...), m[2]+" objectid")
+	}
+	lines[ret] = strings.TrimRight(lines[ret], " ") + ", " + strings.Join(extra, ", ")
+	q.Cypher = strings.Join(lines, "\n")
+	return q.WithResolvedKeys()
+}
@@ -0,0 +1,66 @@
+package queries
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadOrder reads a sheet-order file: a YAML map of query ID (or glob) to
+// Order weight. The built-in core tabs weigh 10, 20, 30 and 40 (see
+// defaultOrder), so e.g. 1 pins a tab before All Users and 15 slots it between
+// All Users and All Computers.
+//
+//	ad-dcsync-rights: 1
+//	ad-kerberoast*: 2
+func LoadOrder(p string) (map[string]int, error) {
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]int{}
+	if err := yaml.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", p, err)
+	}
+	for pat := range m {
+		if _, err := path.Match(pat, ""); err != nil {
+			return nil, fmt.Errorf("%s: bad pattern %q: %w", p, pat, err)
+		}
+	}
+	return m, nil
+}
+
+// ApplyOrder overrides Order weights. Exact IDs win over globs; among globs
+// the longest (most specific) pattern wins.
+func ApplyOrder(in []Query, weights map[string]int) []Query {
+	if len(weights) == 0 {
+		return in
+	}
+	globs := make([]string, 0, len(weights))
+	for pat := range weights {
+		globs = append(globs, pat)
+	}
+	sort.Slice(globs, func(i, j int) bool {
+		if len(globs[i]) != len(globs[j]) {
+			return len(globs[i]) > len(globs[j])
+		}
+		return globs[i] < globs[j]
+	})
+	out := append([]Query(nil), in...)
+	for i, q := range out {
+		if w, ok := weights[q.ID]; ok {
+			out[i].Order = w
+			continue
+		}
+		for _, pat := range globs {
+			if ok, _ := path.Match(pat, q.ID); ok {
+				out[i].Order = weights[pat]
+				break
+			}
+		}
+	}
+	return out
+}
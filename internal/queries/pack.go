@@ -0,0 +1,95 @@
+package queries
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// packFile is the YAML layout of an external query pack.
+type packFile struct {
+	Queries []packQuery `yaml:"queries"`
+}
+
+type packQuery struct {
+	ID             string         `yaml:"id"`
+	Title          string         `yaml:"title"`
+	Category       string         `yaml:"category"`
+	Severity       string         `yaml:"severity"`
+	SheetName      string         `yaml:"sheet"`
+	Headers        []string       `yaml:"headers"`
+	Description    string         `yaml:"description"`
+	FindingTitle   string         `yaml:"finding"`
+	Cypher         string         `yaml:"cypher"`
+	APOCCypher     string         `yaml:"apocCypher"`
+	Params         map[string]any `yaml:"params"`
+	Remediation    string         `yaml:"remediation"`
+	Order          int            `yaml:"order"`
+	Sort           string         `yaml:"sort"`
+	PageKey        string         `yaml:"pageKey"`
+	MinRows        int            `yaml:"minRows"`
+	Limit          int            `yaml:"limit"`
+	Timeout        time.Duration  `yaml:"timeout"`
+	RequiresLabels []string       `yaml:"requiresLabels"`
+	RequiresRels   []string       `yaml:"requiresRels"`
+	RequiresProps  []string       `yaml:"requiresProps"`
+	Write          bool           `yaml:"write"`
+}
+
+// LoadPack reads an external query pack: a YAML file listing queries with
+// the fields of the built-in ones. Unknown keys are errors, so a misspelt
+// field doesn't silently drop a setting. A query whose Cypher could modify
+// the graph must say write: true (Lint flags it otherwise), and like the
+// built-in maintenance queries it only runs with --allow-write (see
+// WriteClause).
+//
+//	queries:
+//	  - id: custom-stale-admins
+//	    title: Stale admin accounts
+//	    category: AD
+//	    severity: high
+//	    sheet: Stale Admins
+//	    headers: [User, Last logon]
+//	    cypher: |
+//	      MATCH (u:User {admincount: true})
+//	      WHERE u.lastlogontimestamp < (datetime().epochSeconds - $staleDays * 86400)
+//	      RETURN u.name AS user, u.lastlogontimestamp AS last_logon
+//	    params: {staleDays: 90}
+//	    timeout: 2m
+func LoadPack(p string) ([]Query, error) {
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	var f packFile
+	if err := dec.Decode(&f); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", p, err)
+	}
+	if len(f.Queries) == 0 {
+		return nil, fmt.Errorf("%s: no queries", p)
+	}
+	out := make([]Query, 0, len(f.Queries))
+	for _, pq := range f.Queries {
+		q := Query{
+			ID: pq.ID, Title: pq.Title, Category: pq.Category, Severity: pq.Severity,
+			SheetName: pq.SheetName, Headers: pq.Headers, Description: pq.Description,
+			FindingTitle: pq.FindingTitle, Cypher: pq.Cypher, APOCCypher: pq.APOCCypher,
+			Params: pq.Params, Remediation: pq.Remediation, Order: pq.Order,
+			PageKey: pq.PageKey, MinRows: pq.MinRows, Limit: pq.Limit, Timeout: pq.Timeout,
+			RequiresLabels: pq.RequiresLabels, RequiresRels: pq.RequiresRels, RequiresProps: pq.RequiresProps,
+			Write: pq.Write,
+		}
+		if pq.Sort != "" {
+			if q.Sort, err = ParseSort(pq.Sort); err != nil {
+				return nil, fmt.Errorf("%s: query %s: sort: %w", p, pq.ID, err)
+			}
+		}
+		out = append(out, q.WithResolvedKeys())
+	}
+	return out, nil
+}
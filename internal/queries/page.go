@@ -0,0 +1,121 @@
+package queries
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Parameters and the key column of the queries PagedCypher builds.
+const (
+	PageAfterParam = "after"
+	PageSizeParam  = "pageSize"
+	PageKeyColumn  = "pageKey"
+)
+
+var (
+	reLastReturn = regexp.MustCompile(`(?is).*\bRETURN\s+(?:DISTINCT\s+)?(.*)$`)
+	reReturnEnd  = regexp.MustCompile(`(?is)\s(?:ORDER\s+BY|SKIP|LIMIT)\b`)
+	reAlias      = regexp.MustCompile(`(?i)\bAS\s+([A-Za-z_][A-Za-z0-9_]*)\s*$`)
+
+	rePageShape   = regexp.MustCompile(`(?is)^MATCH\s+(.*?)(?:\s+WHERE\s+(.*?))?\s+RETURN\s+(.*?)(?:\s+ORDER\s+BY\s+.*)?$`)
+	rePageClause  = regexp.MustCompile(`(?i)\b(?:MATCH|OPTIONAL|WHERE|WITH|UNWIND|CALL|UNION|RETURN|DISTINCT|SKIP|LIMIT|ORDER)\b`)
+	rePageAggFunc = regexp.MustCompile(`(?i)\b(?:count|collect|sum|avg|min|max|stDev|stDevP|percentileCont|percentileDisc)\s*\(`)
+)
+
+// PagedCypher rewrites a query declaring a PageKey for keyset paging: the
+// key node's objectid must exceed $after, rows are ordered by it and capped
+// at $pageSize, and it is returned as the last column, PageKeyColumn, so the
+// next page can start after the last row. Each page is then an index-backed
+// range read rather than a re-run of the whole query. Only the plain shape
+// MATCH <pattern binding PageKey> [WHERE ...] RETURN <aliased columns>
+// [ORDER BY ...] is rewritten, since anything else (WITH, UNWIND,
+// aggregations, DISTINCT) breaks the one-row-per-key-node assumption; it
+// returns false otherwise, or when PageKey is empty. The query's own ORDER
+// BY is replaced, and key nodes without an objectid are not returned.
+func (q Query) PagedCypher() (string, bool) {
+	if q.PageKey == "" {
+		return "", false
+	}
+	cy := strings.TrimRight(strings.TrimSpace(q.Cypher), ";")
+	m := rePageShape.FindStringSubmatchIndex(stripLiteralsKeepLen(cy))
+	if m == nil {
+		return "", false
+	}
+	part := func(i int) string {
+		if m[2*i] < 0 {
+			return ""
+		}
+		return strings.TrimSpace(cy[m[2*i]:m[2*i+1]])
+	}
+	pattern, cond, proj := part(1), part(2), part(3)
+	for _, s := range []string{pattern, cond, proj} {
+		if rePageClause.MatchString(stripLiterals(s)) {
+			return "", false
+		}
+	}
+	if rePageAggFunc.MatchString(stripLiterals(proj)) {
+		return "", false
+	}
+	if _, ok := returnAliases(stripLiteralsKeepLen(cy)); !ok {
+		return "", false
+	}
+	if !regexp.MustCompile(`\(\s*` + regexp.QuoteMeta(q.PageKey) + `\b`).MatchString(stripLiterals(pattern)) {
+		return "", false
+	}
+	key := q.PageKey + ".objectid"
+	where := key + " > $" + PageAfterParam
+	if cond != "" {
+		where += " AND (" + cond + ")"
+	}
+	return "MATCH " + pattern +
+		"\nWHERE " + where +
+		"\nRETURN " + proj + ", " + key + " AS " + PageKeyColumn +
+		"\nORDER BY " + key +
+		"\nLIMIT $" + PageSizeParam, true
+}
+
+// returnAliases lists the column aliases of the query's last RETURN clause.
+func returnAliases(cypher string) ([]string, bool) {
+	m := reLastReturn.FindStringSubmatch(cypher)
+	if m == nil {
+		return nil, false
+	}
+	proj := m[1]
+	if loc := reReturnEnd.FindStringIndex(proj); loc != nil {
+		proj = proj[:loc[0]]
+	}
+	var cols []string
+	for _, item := range splitTopLevel(proj) {
+		a := reAlias.FindStringSubmatch(strings.TrimSpace(item))
+		if a == nil {
+			return nil, false
+		}
+		cols = append(cols, a[1])
+	}
+	return cols, len(cols) > 0
+}
+
+// splitTopLevel splits s on commas outside brackets and string literals.
+func splitTopLevel(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	var quote rune
+	for i, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '(' || r == '[' || r == '{':
+			depth++
+		case r == ')' || r == ']' || r == '}':
+			depth--
+		case r == ',' && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
@@ -0,0 +1,95 @@
+package queries
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ParseParams parses --param values of the form "name=value". Integers,
+// floats and true/false are passed to Neo4j typed; anything else as a string.
+func ParseParams(specs []string) (map[string]any, error) {
+	m := make(map[string]any, len(specs))
+	for _, s := range specs {
+		name, val, ok := strings.Cut(s, "=")
+		name = strings.TrimPrefix(strings.TrimSpace(name), "$")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%q: expected <name>=<value>", s)
+		}
+		m[name] = paramValue(strings.TrimSpace(val))
+	}
+	return m, nil
+}
+
+func paramValue(s string) any {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f
+	}
+	if b, err := strconv.ParseBool(s); err == nil {
+		return b
+	}
+	return s
+}
+
+// ApplyParams overrides the default values of query parameters and returns
+// the override names no selected query uses.
+func ApplyParams(in []Query, overrides map[string]any) ([]Query, []string) {
+	out := append([]Query(nil), in...)
+	used := map[string]bool{}
+	for i, q := range out {
+		var p map[string]any
+		for name, v := range overrides {
+			if _, ok := q.Params[name]; !ok {
+				continue
+			}
+			if p == nil {
+				p = make(map[string]any, len(q.Params))
+				for k, dv := range q.Params {
+					p[k] = dv
+				}
+			}
+			p[name] = v
+			used[name] = true
+		}
+		if p != nil {
+			out[i].Params = p
+		}
+	}
+	var unknown []string
+	for name := range overrides {
+		if !used[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	return out, unknown
+}
+
+// Literal renders a parameter value as a Cypher literal, e.g. for a Neo4j
+// Browser ":param" line.
+func Literal(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(x) + "'"
+	case []any:
+		parts := make([]string, len(x))
+		for i, e := range x {
+			parts[i] = Literal(e)
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	case []string:
+		parts := make([]string, len(x))
+		for i, e := range x {
+			parts[i] = Literal(e)
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	default:
+		return fmt.Sprint(x)
+	}
+}
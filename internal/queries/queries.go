@@ -4,18 +4,56 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 )
 
 type Query struct {
 	ID           string
 	Title        string
 	Category     string // AD | EntraID | INFO
+	Severity     string // critical | high | medium | low | info
 	SheetName    string
 	Headers      []string
 	Description  string
 	FindingTitle string
 	Cypher       string
-	ColumnKeys   []string // resolved from Headers
+	// APOCCypher, when set, is an equivalent of Cypher using APOC procedures
+	// (e.g. apoc.path.expandConfig instead of variable-length MemberOf
+	// walks); UseAPOC swaps it in when the server has APOC. It must RETURN
+	// the same columns, and the generic rewrites (WithObjectIDs, CountCypher)
+	// keep it in step; per-ID display rewrites do not, so queries those touch
+	// have no variant.
+	APOCCypher string
+	// Params holds the default values of the $parameters Cypher uses
+	// (thresholds like $staleDays); --param overrides them.
+	Params      map[string]any
+	Remediation string   // overrides the built-in write-up; see RemediationText
+	ColumnKeys  []string // resolved from Headers
+	// Order pins the query's tab ahead of unweighted ones; lower weights come
+	// first. Zero means "no weight": defaultOrder, then category order applies.
+	Order int
+	// Sort re-orders result rows client-side before writing (see --sort).
+	Sort []SortKey
+	// PageKey names the node variable of a query that may return very large
+	// results, one row per such node; with --page-size it is fetched in
+	// keyset pages on that node's objectid (see PagedCypher).
+	PageKey string
+	// MinRows hides the finding unless it returns at least this many rows.
+	MinRows int
+	// Limit and Timeout override --limit and --query-timeout for this query,
+	// e.g. for path queries that need longer than inventory ones.
+	Limit   int
+	Timeout time.Duration
+	// Write marks queries that modify the graph (MaintenanceQueries); they
+	// run in write transactions and are never cached.
+	Write bool
+	// RequiresLabels, RequiresRels and RequiresProps ("Label.prop") are what
+	// the query cannot return rows without; with --schema-skip it is skipped
+	// when the graph lacks one. Alternatives (n:User OR n:Computer,
+	// [:GenericAll|Owns]) are not requirements.
+	RequiresLabels []string
+	RequiresRels   []string
+	RequiresProps  []string
 }
 
 func (q Query) WithResolvedKeys() Query {
@@ -77,32 +115,22 @@ func FilterCategoryStrict(in []Query, category string) ([]Query, error) {
 	return out, nil
 }
 
-// Order enforces tab ordering:
-// 1) All Users
-// 2) All Computers
-// 3) Domain Admins
-// 4) Domain Controllers
-// then grouped by Category: AD, EntraID, INFO (each in existing order)
+// Order enforces tab ordering: queries with an Order weight first (ascending;
+// by default All Users, All Computers, Domain Admins, Domain Controllers),
+// then grouped by Category: AD, EntraID, INFO (each in existing order).
 func Order(in []Query) []Query {
-	idx := map[string]int{
-		"ad-all-users-samaccountname": 1,
-		"ad-all-computers-fqdn":       2,
-		"ad-domain-admins":            3,
-		"ad-domain-controllers":       4,
-	}
 	out := append([]Query(nil), in...)
 	sort.SliceStable(out, func(i, j int) bool {
 		iq, jq := out[i], out[j]
-		ii, iok := idx[iq.ID]
-		jj, jok := idx[jq.ID]
-		if iok || jok {
-			if !iok {
+		iw, jw := weight(iq), weight(jq)
+		if iw != 0 || jw != 0 {
+			if iw == 0 {
 				return false
 			}
-			if !jok {
+			if jw == 0 {
 				return true
 			}
-			return ii < jj
+			return iw < jw
 		}
 
 		ci := catRank(iq.Category)
@@ -116,6 +144,39 @@ func Order(in []Query) []Query {
 	return out
 }
 
+// defaultOrder pins the core inventory tabs when a query sets no Order.
+var defaultOrder = map[string]int{
+	"ad-all-users-samaccountname": 10,
+	"ad-all-computers-fqdn":       20,
+	"ad-domain-admins":            30,
+	"ad-domain-controllers":       40,
+}
+
+func weight(q Query) int {
+	if q.Order != 0 {
+		return q.Order
+	}
+	return defaultOrder[q.ID]
+}
+
+// SeverityRank orders severities from most (0) to least severe; unknown values sort last.
+func SeverityRank(sev string) int {
+	switch strings.ToLower(strings.TrimSpace(sev)) {
+	case "critical":
+		return 0
+	case "high":
+		return 1
+	case "medium":
+		return 2
+	case "low":
+		return 3
+	case "info":
+		return 4
+	default:
+		return 5
+	}
+}
+
 func catRank(cat string) int {
 	switch strings.ToLower(cat) {
 	case "ad":
@@ -1,6 +1,12 @@
 package queries
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
 
 func TestOrder(t *testing.T) {
 	in := []Query{
@@ -20,3 +26,194 @@ func TestOrder(t *testing.T) {
 		}
 	}
 }
+
+func TestApplyOrderPinsFirst(t *testing.T) {
+	in := []Query{
+		{ID: "ad-all-users-samaccountname", Category: "AD"},
+		{ID: "ad-kerberoastable", Category: "AD"},
+		{ID: "ad-dcsync-rights", Category: "AD"},
+		{ID: "info-x", Category: "INFO"},
+	}
+	out := Order(ApplyOrder(in, map[string]int{"ad-dcsync-rights": 1, "ad-kerberoast*": 2}))
+	want := []string{"ad-dcsync-rights", "ad-kerberoastable", "ad-all-users-samaccountname", "info-x"}
+	for i, id := range want {
+		if out[i].ID != id {
+			t.Fatalf("pos %d want %s got %s", i, id, out[i].ID)
+		}
+	}
+}
+
+func TestWithObjectIDs(t *testing.T) {
+	q := Query{
+		ID:      "x",
+		Headers: []string{"Principal", "Right", "Domain"},
+		Cypher: `MATCH (p)-[r:GetChanges]->(d:Domain)
+RETURN p.name AS principal, type(r) AS right, d.name AS domain
+ORDER BY principal`,
+	}.WithResolvedKeys()
+	got := WithObjectIDs([]Query{q})[0]
+	wantLine := "RETURN p.name AS principal, type(r) AS right, d.name AS domain, p.objectid AS principal_objectid, d.objectid AS domain_objectid"
+	if !strings.Contains(got.Cypher, wantLine+"\nORDER BY principal") {
+		t.Fatalf("cypher not rewritten:\n%s", got.Cypher)
+	}
+	if n := len(got.ColumnKeys); n != 5 || got.ColumnKeys[3] != "principal_objectid" || got.ColumnKeys[4] != "domain_objectid" {
+		t.Fatalf("column keys: %v", got.ColumnKeys)
+	}
+	if len(q.Headers) != 3 {
+		t.Fatalf("input query mutated: %v", q.Headers)
+	}
+}
+
+func TestSortRows(t *testing.T) {
+	keys, err := ParseSort("Count:desc,user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := [][]any{{"bob", int64(2)}, {"amy", nil}, {"Carl", int64(10)}, {"al", int64(2)}}
+	SortRows([]string{"user", "count"}, rows, keys)
+	var got []string
+	for _, r := range rows {
+		got = append(got, r[0].(string))
+	}
+	if strings.Join(got, ",") != "Carl,al,bob,amy" {
+		t.Fatalf("unexpected order: %v", got)
+	}
+	if _, err := ParseSortOverrides([]string{"ad-x=user:sideways"}); err == nil {
+		t.Fatalf("expected error for bad direction")
+	}
+}
+
+func TestPagedCypher(t *testing.T) {
+	q := Query{PageKey: "u", Cypher: "MATCH (u:User)\nWHERE u.pwdneverexpires = true\nRETURN u.name AS user, u.enabled AS enabled\nORDER BY user"}
+	want := "MATCH (u:User)\nWHERE u.objectid > $after AND (u.pwdneverexpires = true)\nRETURN u.name AS user, u.enabled AS enabled, u.objectid AS pageKey\nORDER BY u.objectid\nLIMIT $pageSize"
+	if got, ok := q.PagedCypher(); !ok || got != want {
+		t.Fatalf("unexpected paged cypher (ok=%v):\n%s", ok, got)
+	}
+	q = Query{PageKey: "c", Cypher: "MATCH (c:Computer) RETURN c.name AS fqdn"}
+	if got, ok := q.PagedCypher(); !ok || !strings.HasPrefix(got, "MATCH (c:Computer)\nWHERE c.objectid > $after\n") {
+		t.Fatalf("unexpected paged cypher (ok=%v):\n%s", ok, got)
+	}
+	for _, q := range []Query{
+		{Cypher: "MATCH (u:User) RETURN u.name AS user"},
+		{PageKey: "n", Cypher: "MATCH (n) RETURN n.name, n.objectid AS id"},
+		{PageKey: "n", Cypher: "MATCH (n:User) RETURN n.enabled AS enabled, count(*) AS c"},
+		{PageKey: "c", Cypher: "MATCH (c:Computer)\nWITH c, split(c.name,'.') AS parts\nRETURN parts[0] AS hostname"},
+		{PageKey: "g", Cypher: "MATCH (u:User) RETURN u.name AS user"},
+	} {
+		if cy, ok := q.PagedCypher(); ok {
+			t.Errorf("%q must not be paged, got:\n%s", q.Cypher, cy)
+		}
+	}
+}
+
+func TestLoadPack(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "pack.yaml")
+	pack := `queries:
+  - id: custom-stale-admins
+    title: Stale admin accounts
+    category: AD
+    severity: high
+    sheet: Stale Admins
+    headers: [User, Last logon]
+    cypher: |
+      MATCH (u:User {admincount: true})
+      WHERE u.lastlogontimestamp < (datetime().epochSeconds - $staleDays * 86400)
+      RETURN u.name AS user, u.lastlogontimestamp AS last_logon
+    params: {staleDays: 90}
+    sort: last_logon
+    timeout: 2m
+`
+	if err := os.WriteFile(p, []byte(pack), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	qs, err := LoadPack(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(qs) != 1 || qs[0].Timeout != 2*time.Minute || qs[0].Params["staleDays"] != 90 || len(qs[0].Sort) != 1 {
+		t.Fatalf("unexpected pack: %+v", qs)
+	}
+	if problems := Lint(qs); len(problems) != 0 {
+		t.Fatalf("pack example does not lint clean: %+v", problems)
+	}
+
+	if err := os.WriteFile(p, []byte("queries:\n  - id: x\n    cyhper: MATCH (n) RETURN n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadPack(p); err == nil {
+		t.Fatalf("misspelt field must be an error")
+	}
+}
+
+func TestLimitCypher(t *testing.T) {
+	cases := []struct {
+		in, want string
+		ok       bool
+	}{
+		{"MATCH (u:User) RETURN u.name AS user LIMIT 5", "MATCH (u:User) RETURN u.name AS user LIMIT 5", false},
+		{"MATCH (u:User) WHERE u.description CONTAINS 'limit' RETURN u.name AS user",
+			"CALL {\nMATCH (u:User) WHERE u.description CONTAINS 'limit' RETURN u.name AS user\n}\nRETURN user\nLIMIT $limit", true},
+		{"MATCH (u:User) RETURN u.name AS name UNION MATCH (c:Computer) RETURN c.name AS name",
+			"CALL {\nMATCH (u:User) RETURN u.name AS name UNION MATCH (c:Computer) RETURN c.name AS name\n}\nRETURN name\nLIMIT $limit", true},
+		{"MATCH (u:User) RETURN u.name", "MATCH (u:User) RETURN u.name\nLIMIT $limit", true},
+	}
+	for _, c := range cases {
+		got, ok := LimitCypher(c.in)
+		if got != c.want || ok != c.ok {
+			t.Errorf("LimitCypher(%q) = %q, %v; want %q, %v", c.in, got, ok, c.want, c.ok)
+		}
+	}
+}
+
+func TestMemgraphRewrite(t *testing.T) {
+	in := "MATCH (c:Computer) WHERE EXISTS(c.description) AND c.pwdlastset > (datetime().epochseconds - 86400) RETURN c"
+	want := "MATCH (c:Computer) WHERE c.description IS NOT NULL AND c.pwdlastset > ((timestamp() / 1000000) - 86400) RETURN c"
+	if got := Memgraph.Rewrite(in); got != want {
+		t.Fatalf("got  %s\nwant %s", got, want)
+	}
+	if got := Neo4j.Rewrite(in); got != in {
+		t.Fatalf("neo4j dialect must not rewrite: %s", got)
+	}
+}
+
+func TestSystemTagsRewrite(t *testing.T) {
+	for in, want := range map[string]string{
+		"WHERE g.highvalue=true AND u.hasspn=true": "WHERE coalesce(g.system_tags, '') CONTAINS 'admin_tier_0' AND u.hasspn=true",
+		"WHERE n.highvalue = false":                "WHERE NOT coalesce(n.system_tags, '') CONTAINS 'admin_tier_0'",
+		"RETURN n.highvalue AS hv":                 "RETURN (coalesce(n.system_tags, '') CONTAINS 'admin_tier_0') AS hv",
+		"SET g.highvalue = true":                   "SET g.system_tags = CASE WHEN coalesce(g.system_tags, '') CONTAINS 'admin_tier_0' THEN g.system_tags ELSE trim(coalesce(g.system_tags, '') + ' admin_tier_0') END",
+	} {
+		if got := SystemTags.Rewrite(in); got != want {
+			t.Errorf("Rewrite(%q) = %q, want %q", in, got, want)
+		}
+		if got := HighValue.Rewrite(in); got != in {
+			t.Errorf("HighValue.Rewrite(%q) = %q", in, got)
+		}
+	}
+}
+
+func TestLint(t *testing.T) {
+	all := append(append(append([]Query{}, FindingQueries...), InfoQueries...), MaintenanceQueries...)
+	for _, p := range Lint(all) {
+		t.Errorf("built-in %s: %s", p.ID, p.Msg)
+	}
+	bad := []Query{
+		Query{ID: "x", Category: "AD", Headers: []string{"User"}, Cypher: "MATCH (u:User) RETURN u.name AS name"}.WithResolvedKeys(),
+		Query{ID: "x", Category: "Azure", Cypher: "MATCH (u:User) SET u.owned = true RETURN u", RequiresLabels: []string{"Computer"}},
+	}
+	want := []string{
+		`x: header "User" (key "user") is not a RETURN alias (have name)`,
+		"x: duplicate ID (also query #1)",
+		`x: unknown category "Azure" (expected AD, EntraID or INFO)`,
+		"x: requires label Computer, which the Cypher does not use",
+		"x: uses SET but is not marked Write",
+	}
+	var got []string
+	for _, p := range Lint(bad) {
+		got = append(got, p.ID+": "+p.Msg)
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Lint = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,27 @@
+package queries
+
+import (
+	"regexp"
+	"strings"
+)
+
+// writeClause matches Cypher that can modify the graph or the server: write
+// clauses, LOAD CSV (server-side file and URL access), dbms and db.create*
+// procedures, and the APOC procedures that create, merge, refactor or run
+// arbitrary (write) Cypher. The keyword must not follow a '.' or '$', so
+// properties and parameters named e.g. "set" don't count.
+var writeClause = regexp.MustCompile(`(?i)(?:^|[^.\w$])(CREATE|MERGE|DELETE|SET|REMOVE|DROP|LOAD\s+CSV|` +
+	`CALL\s+(?:dbms\.\w+(?:\.\w+)*|db\.create\w*)|` +
+	`apoc\.(?:create|merge|refactor|periodic|do)\.\w+|apoc\.cypher\.(?:runWrite\w*|runSchema\w*|doIt))\b`)
+
+// WriteClause returns the first clause in cypher that can write to the
+// database (e.g. "MERGE" or "CALL dbms.security.createUser"), or "" when
+// the query is read-only. String literals, comments and backquoted names
+// are ignored.
+func WriteClause(cypher string) string {
+	m := writeClause.FindStringSubmatch(stripLiterals(cypher))
+	if m == nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(m[1]), " ")
+}
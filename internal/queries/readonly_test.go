@@ -0,0 +1,39 @@
+package queries
+
+import "testing"
+
+func TestWriteClause(t *testing.T) {
+	for cypher, want := range map[string]string{
+		"MATCH (u:User) RETURN u.name":                         "",
+		"MATCH (n) WHERE n.name = 'CREATE ME' RETURN n":        "",
+		"MATCH (n) // DELETE n\nRETURN n.set AS `set`, $merge": "",
+		"MATCH (n) DETACH DELETE n":                            "DELETE",
+		"MATCH (u:User {name: $n}) SET u.owned = true":         "SET",
+		"merge (g:Group {objectid: 'x'})":                      "merge",
+		"CALL dbms.security.createUser('x', 'y', false)":       "CALL dbms.security.createUser",
+		"CALL db.labels()":                                     "",
+
+		"CALL apoc.create.node(['User'], {name: 'x'})":                        "apoc.create.node",
+		"CALL apoc.merge.relationship(a, 'MemberOf', {}, {}, b, {})":          "apoc.merge.relationship",
+		"CALL apoc.refactor.mergeNodes([a, b])":                               "apoc.refactor.mergeNodes",
+		"CALL apoc.cypher.runWrite('MATCH (n) DETACH DELETE n', {})":          "apoc.cypher.runWrite",
+		"CALL apoc.cypher.doIt('RETURN 1', {})":                               "apoc.cypher.doIt",
+		"CALL apoc.periodic.iterate('MATCH (n) RETURN n', 'SET n.x = 1', {})": "apoc.periodic.iterate",
+		"CALL apoc.do.when(true, 'CREATE (n)', '', {})":                       "apoc.do.when",
+		"LOAD CSV FROM 'file:///users.csv' AS row RETURN row":                 "LOAD CSV",
+		"load  csv with headers from $url AS row RETURN row":                  "load csv",
+		"CALL db.createLabel('Owned')":                                        "CALL db.createLabel",
+		"CALL apoc.cypher.run('MATCH (n) RETURN n', {})":                      "",
+		"CALL apoc.path.expandConfig(u, {maxLevel: 3})":                       "",
+		"MATCH (n) WHERE n.name = 'apoc.create.node' RETURN n":                "",
+	} {
+		if got := WriteClause(cypher); got != want {
+			t.Errorf("WriteClause(%q) = %q, want %q", cypher, got, want)
+		}
+	}
+	for _, q := range append(FindingQueries, InfoQueries...) {
+		if c := WriteClause(q.Cypher + "\n" + q.APOCCypher); c != "" {
+			t.Errorf("built-in %s contains %s", q.ID, c)
+		}
+	}
+}
@@ -3,12 +3,20 @@ package queries
 // Registry holds the built-in query packs.
 // Ported from bloodyEll_example + later additions.
 
+import "time"
+
+// pathQueryTimeout is the Timeout of queries walking unbounded group
+// nesting or the whole graph, which take longer than --query-timeout's
+// default on large environments.
+const pathQueryTimeout = 120 * time.Second
+
 var FindingQueries = []Query{
 	// --- Baseline inventory (always first tabs) ---
 	Query{
 		ID:           "ad-all-users-samaccountname",
 		Title:        "All users (samAccountName)",
 		Category:     "AD",
+		Severity:     "info",
 		SheetName:    "All Users",
 		Headers:      []string{"samaccountname"},
 		Description:  "All users in the domain (samAccountName)",
@@ -17,11 +25,15 @@ var FindingQueries = []Query{
 WHERE u.samaccountname IS NOT NULL
 RETURN u.samaccountname AS samaccountname
 ORDER BY samaccountname`,
+		PageKey:        "u",
+		RequiresLabels: []string{"User"},
+		RequiresProps:  []string{"User.samaccountname"},
 	}.WithResolvedKeys(),
 	Query{
 		ID:           "ad-all-computers-fqdn",
 		Title:        "All computers (FQDN)",
 		Category:     "AD",
+		Severity:     "info",
 		SheetName:    "All Computers",
 		Headers:      []string{"fqdn"},
 		Description:  "All computers in the domain (FQDN/hostname)",
@@ -29,11 +41,14 @@ ORDER BY samaccountname`,
 		Cypher: `MATCH (c:Computer)
 RETURN c.name AS fqdn
 ORDER BY fqdn`,
+		PageKey:        "c",
+		RequiresLabels: []string{"Computer"},
 	}.WithResolvedKeys(),
 	Query{
 		ID:           "ad-domain-admins",
 		Title:        "Domain Admins",
 		Category:     "AD",
+		Severity:     "info",
 		SheetName:    "Domain Admins",
 		Headers:      []string{"Principal", "Type"},
 		Description:  "Members of Domain Admins.",
@@ -43,11 +58,20 @@ WHERE toUpper(g.name) ENDS WITH "DOMAIN ADMINS" OR g.objectid ENDS WITH "-512"
 MATCH (u)-[:MemberOf*1..]->(g)
 RETURN u.name AS principal, labels(u) AS type
 ORDER BY principal`,
+		APOCCypher: `MATCH (g:Group)
+WHERE toUpper(g.name) ENDS WITH "DOMAIN ADMINS" OR g.objectid ENDS WITH "-512"
+CALL apoc.path.expandConfig(g, {relationshipFilter: '<MemberOf', minLevel: 1, uniqueness: 'NODE_GLOBAL'}) YIELD path
+WITH last(nodes(path)) AS u
+RETURN u.name AS principal, labels(u) AS type
+ORDER BY principal`,
+		RequiresLabels: []string{"Group"},
+		RequiresRels:   []string{"MemberOf"},
 	}.WithResolvedKeys(),
 	Query{
 		ID:           "ad-domain-controllers",
 		Title:        "Domain Controllers",
 		Category:     "AD",
+		Severity:     "info",
 		SheetName:    "Domain Controllers",
 		Headers:      []string{"Hostname", "Operating System"},
 		Description:  "Computer objects that are members of the Domain Controllers group.",
@@ -56,6 +80,9 @@ ORDER BY principal`,
 WHERE g.objectid ENDS WITH '-516'
 RETURN c.name AS computer, c.operatingsystem AS os
 ORDER BY computer`,
+		RequiresLabels: []string{"Computer", "Group"},
+		RequiresRels:   []string{"MemberOf"},
+		RequiresProps:  []string{"Group.objectid"},
 	}.WithResolvedKeys(),
 
 	// --- Ported from bloodyEll_example (findings) ---
@@ -63,6 +90,7 @@ ORDER BY computer`,
 		ID:           "ad-unconstrained-delegation-non-dc",
 		Title:        "Non-DCs w/ Unconstrained Delegation enabled",
 		Category:     "AD",
+		Severity:     "high",
 		SheetName:    "Uncons. Delegation",
 		Headers:      []string{"Hostname", "Operating System"},
 		Description:  "Non-DCs w/ Unconstrained Delegation enabled",
@@ -74,11 +102,15 @@ MATCH (c2:Computer {unconstraineddelegation:true})
 WHERE NOT c2.name IN domainControllers
 RETURN c2.name AS computer, c2.operatingsystem AS os
 ORDER BY computer ASC`,
+		RequiresLabels: []string{"Computer", "Group"},
+		RequiresRels:   []string{"MemberOf"},
+		RequiresProps:  []string{"Group.objectid", "Computer.name", "Computer.unconstraineddelegation"},
 	}.WithResolvedKeys(),
 	Query{
 		ID:           "ad-unsupported-os-recent",
 		Title:        "Unsupported operating system(s) in use (recently active)",
 		Category:     "AD",
+		Severity:     "high",
 		SheetName:    "Unsupported OS (recently active)",
 		Headers:      []string{"Hostname", "Operating System"},
 		Description:  "AD Computer objects identified as running unsupported operating systems (checked in last 90 days)",
@@ -86,14 +118,18 @@ ORDER BY computer ASC`,
 		Cypher: `MATCH (c:Computer)
 WHERE c.operatingsystem =~ '.*(2000|2003|2008|xp|vista|7|me).*'
   AND c.operatingsystem =~ '.*Windows.*'
-  AND c.pwdlastset > (datetime().epochseconds - (90 * 86400))
+  AND c.pwdlastset > (datetime().epochseconds - ($activeDays * 86400))
 RETURN c.name AS computer, c.operatingsystem AS os
 ORDER BY computer`,
+		Params:         map[string]any{"activeDays": 90},
+		RequiresLabels: []string{"Computer"},
+		RequiresProps:  []string{"Computer.operatingsystem", "Computer.pwdlastset"},
 	}.WithResolvedKeys(),
 	Query{
 		ID:           "ad-domain-users-local-admin",
 		Title:        "Domain Users are local admins",
 		Category:     "AD",
+		Severity:     "critical",
 		SheetName:    "All Users LA",
 		Headers:      []string{"Hostname"},
 		Description:  "Systems where the Domain Users group is in the local Administrators group",
@@ -102,11 +138,15 @@ ORDER BY computer`,
 WHERE m.name =~ 'DOMAIN USERS@.*'
 MATCH (m)-[:AdminTo]->(n:Computer)
 RETURN n.name AS computer`,
+		RequiresLabels: []string{"Group", "Computer"},
+		RequiresRels:   []string{"AdminTo"},
+		RequiresProps:  []string{"Group.name"},
 	}.WithResolvedKeys(),
 	Query{
 		ID:           "ad-highvalue-kerberoast",
 		Title:        "High value accounts with SPNs",
 		Category:     "AD",
+		Severity:     "high",
 		SheetName:    "High Value Kerberoast",
 		Headers:      []string{"User"},
 		Description:  "High value users with SPNs that could allow kerberoasting",
@@ -115,26 +155,42 @@ RETURN n.name AS computer`,
 WHERE g.highvalue=true AND u.hasspn=true
 RETURN distinct(u.name) AS user
 ORDER BY user`,
+		APOCCypher: `MATCH (g:Group)
+WHERE g.highvalue=true
+CALL apoc.path.expandConfig(g, {relationshipFilter: '<MemberOf', labelFilter: '/User', minLevel: 1, uniqueness: 'NODE_GLOBAL'}) YIELD path
+WITH last(nodes(path)) AS u
+WHERE u.hasspn=true
+RETURN distinct(u.name) AS user
+ORDER BY user`,
+		Timeout:        pathQueryTimeout,
+		RequiresLabels: []string{"User", "Group"},
+		RequiresRels:   []string{"MemberOf"},
+		RequiresProps:  []string{"Group.highvalue", "User.hasspn"},
 	}.WithResolvedKeys(),
 	Query{
 		ID:           "ad-old-passwords-2y",
 		Title:        "Enabled accounts with old passwords",
 		Category:     "AD",
+		Severity:     "medium",
 		SheetName:    "Old Passwords",
 		Headers:      []string{"User", "Password Set", "Service Acct?"},
 		Description:  "Enabled accounts with passwords older than two years. Service accounts first.",
 		FindingTitle: "Old Active Directory password(s)",
 		Cypher: `MATCH (u:User)
-WHERE u.pwdlastset < (datetime().epochseconds - (730 * 86400))
+WHERE u.pwdlastset < (datetime().epochseconds - ($staleDays * 86400))
   AND NOT u.pwdlastset IN [-1.0, 0.0]
   AND u.enabled=true
 RETURN u.name AS user, u.pwdlastset AS pwdlastset, u.hasspn AS service_acct
 ORDER BY service_acct DESC, pwdlastset DESC`,
+		Params:         map[string]any{"staleDays": 730},
+		RequiresLabels: []string{"User"},
+		RequiresProps:  []string{"User.pwdlastset", "User.enabled"},
 	}.WithResolvedKeys(),
 	Query{
 		ID:           "ad-domain-admin-sessions-non-dc",
 		Title:        "Domain Admin sessions on non-DCs",
 		Category:     "AD",
+		Severity:     "high",
 		SheetName:    "DAs on Non-DCs",
 		Headers:      []string{"User", "Computer"},
 		Description:  "Domain admin sessions on systems that are not domain controllers.",
@@ -147,11 +203,16 @@ WHERE g2.objectid ENDS WITH '-512'
 MATCH (c:Computer)-[:HasSession]->(n)
 WHERE NOT c.name IN domainControllers
 RETURN n.name AS user, c.name AS computer`,
+		Timeout:        pathQueryTimeout,
+		RequiresLabels: []string{"Computer", "Group", "User"},
+		RequiresRels:   []string{"MemberOf", "HasSession"},
+		RequiresProps:  []string{"Group.objectid", "Computer.name"},
 	}.WithResolvedKeys(),
 	Query{
 		ID:           "ad-userpassword-attr",
 		Title:        "userPassword attribute set",
 		Category:     "AD",
+		Severity:     "critical",
 		SheetName:    "Users with userpassword",
 		Headers:      []string{"username", "userpassword"},
 		Description:  "AD users in the domain with the userpassword attribute set",
@@ -159,22 +220,28 @@ RETURN n.name AS user, c.name AS computer`,
 		Cypher: `MATCH (u:User)
 WHERE u.userpassword IS NOT NULL
 RETURN u.name AS user, u.userpassword AS userpassword`,
+		RequiresLabels: []string{"User"},
+		RequiresProps:  []string{"User.userpassword"},
 	}.WithResolvedKeys(),
 	Query{
 		ID:           "ad-asrep-roastable",
 		Title:        "AS-REP roastable users",
 		Category:     "AD",
+		Severity:     "high",
 		SheetName:    "ASREP Roastable Users",
 		Headers:      []string{"username"},
 		Description:  "AD users with dontreqpreauth set to true",
 		FindingTitle: "Kerberos preauthentication not required by domain account(s)",
 		Cypher: `MATCH (u:User {dontreqpreauth: true})
 RETURN u.name AS user`,
+		RequiresLabels: []string{"User"},
+		RequiresProps:  []string{"User.dontreqpreauth"},
 	}.WithResolvedKeys(),
 	Query{
 		ID:           "ad-gpo-acl-weirdness",
 		Title:        "Unusual rights over GPOs",
 		Category:     "AD",
+		Severity:     "high",
 		SheetName:    "GPO Weirdness",
 		Headers:      []string{"User", "GPO", "ACL"},
 		Description:  "AD users with unusual GPO privileges",
@@ -182,11 +249,13 @@ RETURN u.name AS user`,
 		Cypher: `MATCH (u:User)-[a:AllExtendedRights|GenericAll|Owns|GenericWrite|WriteOwner|WriteDacl]->(g:GPO)
 RETURN u.name AS user, g.name AS gpo, type(a) AS acl
 ORDER BY user, gpo`,
+		RequiresLabels: []string{"User", "GPO"},
 	}.WithResolvedKeys(),
 	Query{
 		ID:           "ad-password-not-required",
 		Title:        "Password not required (enabled users)",
 		Category:     "AD",
+		Severity:     "medium",
 		SheetName:    "Pass Not Reqd",
 		Headers:      []string{"User"},
 		Description:  "Enabled users with passwordnotreqd=true",
@@ -194,6 +263,8 @@ ORDER BY user, gpo`,
 		Cypher: `MATCH (u:User)
 WHERE u.passwordnotreqd AND u.enabled
 RETURN u.name AS user`,
+		RequiresLabels: []string{"User"},
+		RequiresProps:  []string{"User.passwordnotreqd", "User.enabled"},
 	}.WithResolvedKeys(),
 
 	// --- Additional defender cleanup / hygiene ---
@@ -201,6 +272,7 @@ RETURN u.name AS user`,
 		ID:           "ad-admincount",
 		Title:        "adminCount=1 principals",
 		Category:     "AD",
+		Severity:     "low",
 		SheetName:    "AdminCount=1",
 		Headers:      []string{"Principal", "Type"},
 		Description:  "Principals protected by AdminSDHolder (adminCount=1).",
@@ -214,6 +286,7 @@ ORDER BY principal`,
 		ID:           "ad-password-never-expires",
 		Title:        "Password never expires",
 		Category:     "AD",
+		Severity:     "low",
 		SheetName:    "Pwd Never Expires",
 		Headers:      []string{"User", "Enabled"},
 		Description:  "Users with password never expires set.",
@@ -222,11 +295,15 @@ ORDER BY principal`,
 WHERE u.pwdneverexpires = true
 RETURN u.name AS user, u.enabled AS enabled
 ORDER BY user`,
+		PageKey:        "u",
+		RequiresLabels: []string{"User"},
+		RequiresProps:  []string{"User.pwdneverexpires"},
 	}.WithResolvedKeys(),
 	Query{
 		ID:           "ad-kerberoastable",
 		Title:        "Service accounts (SPNs present)",
 		Category:     "AD",
+		Severity:     "medium",
 		SheetName:    "SPN Users",
 		Headers:      []string{"User", "SPNs"},
 		Description:  "Users with SPNs.",
@@ -235,11 +312,14 @@ ORDER BY user`,
 WHERE u.hasspn = true
 RETURN u.name AS user, u.serviceprincipalnames AS spns
 ORDER BY user`,
+		RequiresLabels: []string{"User"},
+		RequiresProps:  []string{"User.hasspn"},
 	}.WithResolvedKeys(),
 	Query{
 		ID:           "ad-highvalue-objects",
 		Title:        "High value objects",
 		Category:     "AD",
+		Severity:     "info",
 		SheetName:    "High Value",
 		Headers:      []string{"Name", "Type"},
 		Description:  "Objects marked highvalue=true.",
@@ -253,6 +333,7 @@ ORDER BY type, name`,
 		ID:           "ad-users-description-possible-creds",
 		Title:        "User descriptions containing pw/pass",
 		Category:     "AD",
+		Severity:     "high",
 		SheetName:    "User Desc pw/pass",
 		Headers:      []string{"User", "Description"},
 		Description:  "User accounts with 'pw' or 'pass' in description",
@@ -261,6 +342,7 @@ ORDER BY type, name`,
 WHERE toLower(n.description) CONTAINS 'pw' OR toLower(n.description) CONTAINS 'pass'
 RETURN n.name AS user, n.description AS description
 ORDER BY user`,
+		RequiresLabels: []string{"User"},
 	}.WithResolvedKeys(),
 
 	// --- Entra ID (best-effort) ---
@@ -268,6 +350,7 @@ ORDER BY user`,
 		ID:           "entra-guest-users",
 		Title:        "Entra ID guest users",
 		Category:     "EntraID",
+		Severity:     "low",
 		SheetName:    "Entra Guests",
 		Headers:      []string{"Guest"},
 		Description:  "List guest users (external identities) for review.",
@@ -276,11 +359,13 @@ ORDER BY user`,
 WHERE toLower(u.usertype) = "guest" OR toLower(u.userType) = "guest"
 RETURN u.name AS guest
 ORDER BY guest`,
+		RequiresLabels: []string{"AzureUser"},
 	}.WithResolvedKeys(),
 	Query{
 		ID:           "entra-privileged-roles",
 		Title:        "Entra ID privileged role assignments",
 		Category:     "EntraID",
+		Severity:     "medium",
 		SheetName:    "Entra Roles",
 		Headers:      []string{"Role", "Sample Members"},
 		Description:  "Privileged/admin role assignments (best-effort).",
@@ -290,11 +375,14 @@ WHERE toLower(r.name) CONTAINS "admin" OR toLower(r.name) CONTAINS "privileged"
 OPTIONAL MATCH (p)-[:AZRoleMember]->(r)
 RETURN r.name AS role, collect(distinct p.name)[0..50] AS sample_members
 ORDER BY role`,
+		RequiresLabels: []string{"AzureRole"},
+		RequiresRels:   []string{"AZRoleMember"},
 	}.WithResolvedKeys(),
 	Query{
 		ID:           "entra-service-principals",
 		Title:        "Entra ID service principals",
 		Category:     "EntraID",
+		Severity:     "info",
 		SheetName:    "Service Principals",
 		Headers:      []string{"Service Principal"},
 		Description:  "Surface application identities for review.",
@@ -303,11 +391,13 @@ ORDER BY role`,
 RETURN sp.name AS service_principal
 ORDER BY service_principal
 LIMIT 500`,
+		RequiresLabels: []string{"ServicePrincipal"},
 	}.WithResolvedKeys(),
 	Query{
 		ID:           "ad-dcsync-rights",
 		Title:        "Principals with DCSync rights",
 		Category:     "AD",
+		Severity:     "critical",
 		SheetName:    "DCSync Rights",
 		Headers:      []string{"Principal", "Right", "Domain"},
 		Description:  "Principals with replication (DCSync) rights on the domain object.",
@@ -316,11 +406,13 @@ LIMIT 500`,
 MATCH (p)-[r:GetChanges|GetChangesAll|GetChangesInFilteredSet]->(d)
 RETURN p.name AS principal, type(r) AS right, d.name AS domain
 ORDER BY principal`,
+		RequiresLabels: []string{"Domain"},
 	}.WithResolvedKeys(),
 	Query{
 		ID:           "ad-computers-unconstrained-delegation",
 		Title:        "Computers with unconstrained delegation",
 		Category:     "AD",
+		Severity:     "medium",
 		SheetName:    "Uncons. Delegation (All)",
 		Headers:      []string{"Computer", "OS"},
 		Description:  "All computers with unconstrained delegation enabled.",
@@ -329,11 +421,14 @@ ORDER BY principal`,
 WHERE c.unconstraineddelegation = true
 RETURN c.name AS computer, c.operatingsystem AS os
 ORDER BY computer`,
+		RequiresLabels: []string{"Computer"},
+		RequiresProps:  []string{"Computer.unconstraineddelegation"},
 	}.WithResolvedKeys(),
 	Query{
 		ID:           "ad-users-unconstrained-delegation",
 		Title:        "Users with unconstrained delegation",
 		Category:     "AD",
+		Severity:     "high",
 		SheetName:    "User Unconstrained Deleg",
 		Headers:      []string{"User"},
 		Description:  "Users with unconstrained delegation enabled.",
@@ -342,49 +437,61 @@ ORDER BY computer`,
 WHERE u.unconstraineddelegation = true
 RETURN u.name AS user
 ORDER BY user`,
+		RequiresLabels: []string{"User"},
+		RequiresProps:  []string{"User.unconstraineddelegation"},
 	}.WithResolvedKeys(),
 	Query{
 		ID:           "ad-rbcd-allowedtoact",
 		Title:        "Resource-based constrained delegation (RBCD) relationships",
 		Category:     "AD",
+		Severity:     "medium",
 		SheetName:    "RBCD AllowedToAct",
-		Headers:      []string{"From", "To"},
+		Headers:      []string{"Principal", "Computer"},
 		Description:  "Principals that can act on behalf of other identities to a computer (AllowedToAct edge).",
 		FindingTitle: "Review RBCD configuration",
 		Cypher: `MATCH (p)-[:AllowedToAct]->(c:Computer)
 RETURN p.name AS principal, c.name AS computer
 ORDER BY principal, computer`,
+		RequiresLabels: []string{"Computer"},
+		RequiresRels:   []string{"AllowedToAct"},
 	}.WithResolvedKeys(),
 	Query{
 		ID:           "ad-genericall-users",
 		Title:        "Users with GenericAll over other principals",
 		Category:     "AD",
+		Severity:     "high",
 		SheetName:    "GenericAll (Users)",
-		Headers:      []string{"From", "To", "ToType"},
+		Headers:      []string{"Principal", "Target", "Target Type"},
 		Description:  "GenericAll is effectively full control. Review and remediate excessive rights.",
 		FindingTitle: "Excessive object control (GenericAll)",
 		Cypher: `MATCH (a:User)-[:GenericAll]->(b)
 RETURN a.name AS principal, b.name AS target, labels(b) AS target_type
 ORDER BY principal, target
 LIMIT 2000`,
+		RequiresLabels: []string{"User"},
+		RequiresRels:   []string{"GenericAll"},
 	}.WithResolvedKeys(),
 	Query{
 		ID:           "ad-genericwrite-users",
 		Title:        "Users with GenericWrite over other principals",
 		Category:     "AD",
+		Severity:     "medium",
 		SheetName:    "GenericWrite (Users)",
-		Headers:      []string{"From", "To", "ToType"},
+		Headers:      []string{"Principal", "Target", "Target Type"},
 		Description:  "GenericWrite can allow attribute abuse depending on target type. Review for least privilege.",
 		FindingTitle: "Excessive object write rights",
 		Cypher: `MATCH (a:User)-[:GenericWrite]->(b)
 RETURN a.name AS principal, b.name AS target, labels(b) AS target_type
 ORDER BY principal, target
 LIMIT 2000`,
+		RequiresLabels: []string{"User"},
+		RequiresRels:   []string{"GenericWrite"},
 	}.WithResolvedKeys(),
 	Query{
 		ID:           "ad-owned-objects",
 		Title:        "Non-admin owners of high value objects",
 		Category:     "AD",
+		Severity:     "high",
 		SheetName:    "Owned HighValue",
 		Headers:      []string{"Owner", "Object", "Type"},
 		Description:  "Ownership can enable permission changes. Review owners of high value objects.",
@@ -394,11 +501,13 @@ WHERE n.highvalue = true
 RETURN o.name AS owner, n.name AS object, labels(n) AS type
 ORDER BY owner, object
 LIMIT 2000`,
+		RequiresRels: []string{"Owns"},
 	}.WithResolvedKeys(),
 	Query{
 		ID:           "entra-admin-role-membership",
 		Title:        "Entra admin roles and members (top 50 per role)",
 		Category:     "EntraID",
+		Severity:     "medium",
 		SheetName:    "Entra Admin Roles",
 		Headers:      []string{"Role", "Members"},
 		Description:  "Role membership for roles containing 'admin'. Collector schema varies.",
@@ -408,11 +517,15 @@ WHERE toLower(r.name) CONTAINS "admin"
 OPTIONAL MATCH (p)-[:AZRoleMember]->(r)
 RETURN r.name AS role, collect(distinct p.name)[0..50] AS members
 ORDER BY role`,
+		RequiresLabels: []string{"AzureRole"},
+		RequiresRels:   []string{"AZRoleMember"},
+		RequiresProps:  []string{"AzureRole.name"},
 	}.WithResolvedKeys(),
 	Query{
 		ID:           "entra-oauth-grants",
 		Title:        "OAuth permission grants (consents)",
 		Category:     "EntraID",
+		Severity:     "medium",
 		SheetName:    "OAuth Grants",
 		Headers:      []string{"Client", "Resource", "Scope"},
 		Description:  "Consent grants can create long-lived access paths. This is best-effort; labels/edges differ by tool.",
@@ -425,19 +538,24 @@ RETURN coalesce(c.name, c.appid, c.objectid) AS client,
        g.scope AS scope
 ORDER BY client
 LIMIT 2000`,
+		RequiresLabels: []string{"OAuth2PermissionGrant"},
+		RequiresRels:   []string{"Client", "Resource"},
 	}.WithResolvedKeys(),
 	Query{
 		ID:           "entra-app-role-assignments",
 		Title:        "App role assignments",
 		Category:     "EntraID",
+		Severity:     "low",
 		SheetName:    "AppRole Assign",
-		Headers:      []string{"Principal", "ServicePrincipal", "Role"},
+		Headers:      []string{"Principal", "Service Principal", "Role"},
 		Description:  "App role assignments can grant app-specific privileges. Best-effort schema.",
 		FindingTitle: "Review app role assignments",
 		Cypher: `MATCH (u)-[r:AppRoleAssignment]->(sp:ServicePrincipal)
 RETURN u.name AS principal, sp.name AS service_principal, r.appRoleId AS role
 ORDER BY principal
 LIMIT 2000`,
+		RequiresLabels: []string{"ServicePrincipal"},
+		RequiresRels:   []string{"AppRoleAssignment"},
 	}.WithResolvedKeys(),
 }
 
@@ -446,6 +564,7 @@ var InfoQueries = []Query{
 		ID:           "info-groups-admin-to",
 		Title:        "Groups with admin rights to AD computers",
 		Category:     "INFO",
+		Severity:     "info",
 		SheetName:    "Groups with admin privs",
 		Headers:      []string{"Group Names"},
 		Description:  "[INFO] Groups with admin rights to AD computers [INFO]",
@@ -453,11 +572,14 @@ var InfoQueries = []Query{
 		Cypher: `MATCH (m:Group)-[:AdminTo]->(n:Computer)
 RETURN distinct(m.name) AS group
 ORDER BY group`,
+		RequiresLabels: []string{"Group", "Computer"},
+		RequiresRels:   []string{"AdminTo"},
 	}.WithResolvedKeys(),
 	Query{
 		ID:           "info-users-in-vpn-groups",
 		Title:        "Users in VPN groups",
 		Category:     "INFO",
+		Severity:     "info",
 		SheetName:    "Users in VPN group",
 		Headers:      []string{"username", "groupname"},
 		Description:  "[INFO] AD users that are in a group that contains the string VPN [INFO]",
@@ -465,34 +587,44 @@ ORDER BY group`,
 		Cypher: `Match (u:User)-[:MemberOf]->(g:Group)
 WHERE g.name =~ '.*VPN.*'
 RETURN u.name AS user, g.name AS groupname`,
+		RequiresLabels: []string{"User", "Group"},
+		RequiresRels:   []string{"MemberOf"},
+		RequiresProps:  []string{"Group.name"},
 	}.WithResolvedKeys(),
 	Query{
 		ID:           "info-groups-force-change-password",
 		Title:        "Groups with ForceChangePassword",
 		Category:     "INFO",
+		Severity:     "info",
 		SheetName:    "Groups with forceChangePassword",
 		Headers:      []string{"group", "count"},
 		Description:  "[INFO] Groups with the ForceChangePassword privilege in the domain [INFO]",
 		FindingTitle: "[VARIABLE]",
 		Cypher: `MATCH (m:Group)-[:ForceChangePassword]->(n:User)
 RETURN m.name AS group, count(n) AS count`,
+		RequiresLabels: []string{"Group", "User"},
+		RequiresRels:   []string{"ForceChangePassword"},
 	}.WithResolvedKeys(),
 	Query{
 		ID:           "info-constrained-delegation-users",
 		Title:        "Users with constrained delegation",
 		Category:     "INFO",
+		Severity:     "info",
 		SheetName:    "const. deleg computers",
 		Headers:      []string{"username", "services"},
 		Description:  "[INFO] AD users that have constrained delegation turned on and to which services [INFO]",
 		FindingTitle: "Constrained Delegation present",
 		Cypher: `MATCH (u:User)
 WHERE u.allowedtodelegate IS NOT NULL
-RETURN u.name AS user, u.allowedtodelegate AS allowedtodelegate`,
+RETURN u.name AS user, u.allowedtodelegate AS services`,
+		RequiresLabels: []string{"User"},
+		RequiresProps:  []string{"User.allowedtodelegate"},
 	}.WithResolvedKeys(),
 	Query{
 		ID:           "info-linux-computers",
 		Title:        "Linux OS computer objects",
 		Category:     "INFO",
+		Severity:     "info",
 		SheetName:    "Linux OS",
 		Headers:      []string{"Hostname", "Operating System"},
 		Description:  "[INFO] AD Linux based computer objects [INFO]",
@@ -500,11 +632,13 @@ RETURN u.name AS user, u.allowedtodelegate AS allowedtodelegate`,
 		Cypher: `MATCH (c:Computer)
 WHERE c.operatingsystem =~ '.*Linux.*' OR c.operatingsystem =~ '.*(Debian|Ubuntu|Fedora|BSD).*'
 RETURN c.name AS computer, c.operatingsystem AS os`,
+		RequiresLabels: []string{"Computer"},
 	}.WithResolvedKeys(),
 	Query{
 		ID:           "info-systems-with-descriptions",
 		Title:        "Systems with descriptions",
 		Category:     "INFO",
+		Severity:     "info",
 		SheetName:    "Systems with Descriptions",
 		Headers:      []string{"Hostname", "Operating System", "Description"},
 		Description:  "[INFO] AD Computer objects with Descriptions to investigate [INFO]",
@@ -512,11 +646,13 @@ RETURN c.name AS computer, c.operatingsystem AS os`,
 		Cypher: `MATCH (c:Computer)
 WHERE EXISTS(c.description)
 RETURN c.name AS computer, c.operatingsystem AS os, c.description AS description`,
+		RequiresLabels: []string{"Computer"},
 	}.WithResolvedKeys(),
 	Query{
 		ID:           "info-web-apps",
 		Title:        "Web applications (inventory)",
 		Category:     "INFO",
+		Severity:     "info",
 		SheetName:    "Web Applications",
 		Headers:      []string{"Hostname", "Operating System", "Description"},
 		Description:  "[INFO] Web Application Servers to inventory and harden [INFO]",
@@ -525,5 +661,99 @@ RETURN c.name AS computer, c.operatingsystem AS os, c.description AS description
 WHERE toLower(c.name) CONTAINS 'web' OR toLower(c.description) CONTAINS 'web'
    OR toLower(c.name) CONTAINS 'appli' OR toLower(c.description) CONTAINS 'appli'
 RETURN c.name AS computer, c.operatingsystem AS os, c.description AS description`,
+		RequiresLabels: []string{"Computer"},
+	}.WithResolvedKeys(),
+	// --- Graph statistics (dataset size/shape; helps spot incomplete collections) ---
+	Query{
+		ID:           "info-graph-node-counts",
+		Title:        "Node counts per label",
+		Category:     "INFO",
+		Severity:     "info",
+		SheetName:    "Graph Node Counts",
+		Headers:      []string{"Label", "Count"},
+		Description:  "[INFO] Number of nodes per label in the dataset [INFO]",
+		FindingTitle: "[VARIABLE]",
+		Cypher: `MATCH (n)
+UNWIND labels(n) AS label
+RETURN label, count(*) AS count
+ORDER BY count DESC`,
+	}.WithResolvedKeys(),
+	Query{
+		ID:           "info-graph-edge-counts",
+		Title:        "Edge counts per relationship type",
+		Category:     "INFO",
+		Severity:     "info",
+		SheetName:    "Graph Edge Counts",
+		Headers:      []string{"Relationship", "Count"},
+		Description:  "[INFO] Number of edges per relationship type; missing session/ACL edges usually mean a partial collection [INFO]",
+		FindingTitle: "[VARIABLE]",
+		Cypher: `MATCH ()-[r]->()
+RETURN type(r) AS relationship, count(*) AS count
+ORDER BY count DESC`,
+	}.WithResolvedKeys(),
+	Query{
+		ID:           "info-graph-top-degree",
+		Title:        "Highest-degree nodes",
+		Category:     "INFO",
+		Severity:     "info",
+		SheetName:    "Graph Top Degree",
+		Headers:      []string{"Name", "Type", "Degree"},
+		Description:  "[INFO] The 25 most connected nodes (in + out edges) [INFO]",
+		FindingTitle: "[VARIABLE]",
+		Cypher: `MATCH (n)-[r]-()
+WITH n, count(r) AS degree
+ORDER BY degree DESC
+LIMIT 25
+RETURN coalesce(n.name, n.objectid) AS name, labels(n) AS type, degree`,
+		Timeout: pathQueryTimeout,
+	}.WithResolvedKeys(),
+	Query{
+		ID:           "info-graph-degree-distribution",
+		Title:        "Node degree distribution",
+		Category:     "INFO",
+		Severity:     "info",
+		SheetName:    "Graph Degree Dist",
+		Headers:      []string{"Degree Bucket", "Nodes"},
+		Description:  "[INFO] How many nodes fall into each degree bucket; many isolated nodes hint at missing edges [INFO]",
+		FindingTitle: "[VARIABLE]",
+		Cypher: `MATCH (n)
+OPTIONAL MATCH (n)-[r]-()
+WITH n, count(r) AS d
+WITH d, CASE
+  WHEN d = 0 THEN '0'
+  WHEN d <= 10 THEN '1-10'
+  WHEN d <= 100 THEN '11-100'
+  WHEN d <= 1000 THEN '101-1000'
+  ELSE '1000+'
+END AS degree_bucket
+WITH degree_bucket, count(*) AS nodes, min(d) AS lo
+ORDER BY lo
+RETURN degree_bucket, nodes`,
+		Timeout: pathQueryTimeout,
+	}.WithResolvedKeys(),
+	Query{
+		ID:           "info-environment-kpis",
+		Title:        "Environment KPIs",
+		Category:     "INFO",
+		Severity:     "info",
+		SheetName:    "Environment KPIs",
+		Headers:      []string{"Enabled Users", "Kerberoastable Users", "ASREP Users", "Enabled Computers", "LAPS Computers", "Unconstrained Non DC", "Tier Zero"},
+		Description:  "[INFO] Raw counts behind the executive summary KPIs [INFO]",
+		FindingTitle: "[VARIABLE]",
+		Cypher: `MATCH (u:User)
+WITH sum(CASE WHEN u.enabled THEN 1 ELSE 0 END) AS enabled_users,
+     sum(CASE WHEN u.enabled AND u.hasspn AND toUpper(coalesce(u.samaccountname, '')) <> 'KRBTGT' THEN 1 ELSE 0 END) AS kerberoastable_users,
+     sum(CASE WHEN u.enabled AND u.dontreqpreauth THEN 1 ELSE 0 END) AS asrep_users
+OPTIONAL MATCH (c:Computer)
+WITH enabled_users, kerberoastable_users, asrep_users,
+     sum(CASE WHEN c.enabled THEN 1 ELSE 0 END) AS enabled_computers,
+     sum(CASE WHEN c.enabled AND c.haslaps THEN 1 ELSE 0 END) AS laps_computers,
+     sum(CASE WHEN c.unconstraineddelegation AND NOT EXISTS { MATCH (c)-[:MemberOf*1..]->(g:Group) WHERE g.objectid ENDS WITH '-516' } THEN 1 ELSE 0 END) AS unconstrained_non_dc
+OPTIONAL MATCH (t)
+WHERE t.highvalue = true OR 'Tag_Tier_Zero' IN labels(t)
+RETURN enabled_users, kerberoastable_users, asrep_users, enabled_computers, laps_computers, unconstrained_non_dc, count(t) AS tier_zero`,
+		RequiresLabels: []string{"User", "Computer", "Group"},
+		RequiresRels:   []string{"MemberOf"},
+		RequiresProps:  []string{"Group.objectid"},
 	}.WithResolvedKeys(),
 }
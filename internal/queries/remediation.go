@@ -0,0 +1,95 @@
+package queries
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// remediation is the built-in remediation guidance for finding queries, keyed
+// by query ID. Reports render it as the "finding write-up" for a sheet or section.
+var remediation = map[string]string{
+	"ad-unconstrained-delegation-non-dc":    "Remove unconstrained delegation (TRUSTED_FOR_DELEGATION) from every computer that is not a domain controller. Where delegation is required, use constrained delegation or resource-based constrained delegation scoped to the specific services. Add privileged accounts to Protected Users or mark them \"sensitive and cannot be delegated\".",
+	"ad-computers-unconstrained-delegation": "Restrict unconstrained delegation to domain controllers. Migrate other hosts to constrained or resource-based constrained delegation, and mark privileged accounts as \"sensitive and cannot be delegated\" so their TGTs are never forwarded.",
+	"ad-users-unconstrained-delegation":     "Clear TRUSTED_FOR_DELEGATION on user accounts. If a service account needs delegation, configure constrained delegation to named SPNs and rotate the account's password to a long random value.",
+	"ad-unsupported-os-recent":              "Upgrade or decommission hosts running operating systems that no longer receive security updates. Until then, isolate them in restricted network segments, remove privileged sessions from them and monitor them closely.",
+	"ad-domain-users-local-admin":           "Remove Domain Users (and other broad groups) from local Administrators. Grant local admin through dedicated, tiered groups or Windows LAPS, and enforce the change via GPO or Intune so it cannot drift back.",
+	"ad-highvalue-kerberoast":               "Remove unnecessary SPNs from privileged accounts. For privileged service accounts that must keep an SPN, convert them to (g)MSAs or set 25+ character random passwords, enforce AES-only Kerberos encryption, and remove them from privileged groups where possible.",
+	"ad-kerberoastable":                     "Replace user-based service accounts with group Managed Service Accounts, or set long (25+ character) random passwords and rotate them. Disable RC4 for these accounts (msDS-SupportedEncryptionTypes = AES only) and remove SPNs that are no longer used.",
+	"ad-old-passwords-2y":                   "Rotate the passwords of these accounts, starting with service accounts and privileged users. Move service accounts to gMSAs where possible, and disable accounts that are no longer needed.",
+	"ad-domain-admin-sessions-non-dc":       "Domain Admins should only log on to domain controllers and dedicated Tier 0 admin workstations. Enforce this with logon restrictions (\"Deny log on\" user rights or authentication policy silos) and use separate, lower-privileged accounts for day-to-day administration.",
+	"ad-userpassword-attr":                  "Clear the userPassword (and unixUserPassword) attributes on these accounts, reset the affected passwords, and identify the process that populated them.",
+	"ad-asrep-roastable":                    "Enable Kerberos pre-authentication on these accounts (clear \"Do not require Kerberos preauthentication\"). If an application needs it disabled, give the account a long random password and monitor for AS-REQ requests without pre-authentication.",
+	"ad-gpo-acl-weirdness":                  "Review the delegated rights on these GPOs and remove write/modify permissions held by non-administrative principals. Anyone who can edit a linked GPO can run code on every computer or user in its scope.",
+	"ad-password-not-required":              "Clear the PASSWD_NOTREQD flag (userAccountControl 0x20) on these accounts and make sure each has a password that complies with policy. Accounts that are not needed should be disabled.",
+	"ad-admincount":                         "Review principals with adminCount=1. Remove accounts that are no longer members of protected groups from them, clear adminCount, and restore ACL inheritance so that stale AdminSDHolder protections do not hide misconfigurations.",
+	"ad-password-never-expires":             "Remove \"password never expires\" from user accounts, or move service accounts to gMSAs. Where the flag is kept for a documented reason, require long random passwords and a rotation process.",
+	"ad-highvalue-objects":                  "Validate that every high-value object is deliberately part of Tier 0. Reduce membership and control paths to these objects, and monitor changes to them.",
+	"ad-users-description-possible-creds":   "Remove credentials from description fields and reset every password that was exposed. Description attributes are readable by all authenticated users.",
+	"ad-dcsync-rights":                      "Remove replication rights (DS-Replication-Get-Changes, DS-Replication-Get-Changes-All) from every principal except domain controllers and approved directory synchronization accounts. Monitor for replication requests that do not originate from domain controllers.",
+	"ad-rbcd-allowedtoact":                  "Review msDS-AllowedToActOnBehalfOfOtherIdentity on these computers and remove unexpected entries. Also consider setting ms-DS-MachineAccountQuota to 0, so that unprivileged users cannot create computer accounts to abuse RBCD.",
+	"ad-genericall-users":                   "Remove GenericAll rights over user objects from non-administrative principals. Full control allows password resets, shadow credentials and targeted kerberoasting of the affected users.",
+	"ad-genericwrite-users":                 "Remove GenericWrite rights over user objects from non-administrative principals. Write access allows setting SPNs (targeted kerberoasting), logon scripts or shadow credentials on the affected users.",
+	"ad-owned-objects":                      "Transfer ownership of high-value objects to Domain Admins (or the appropriate Tier 0 group). Object owners can rewrite the object's permissions, regardless of its ACL.",
+	"entra-guest-users":                     "Review guest accounts and remove those without a current business need. Restrict guest directory permissions and require regular access reviews for external identities.",
+	"entra-privileged-roles":                "Reduce standing membership of privileged Entra ID roles. Use PIM for just-in-time activation, require phishing-resistant MFA, and keep only break-glass accounts permanently assigned.",
+	"entra-admin-role-membership":           "Reduce standing membership of privileged Entra ID roles. Use PIM for just-in-time activation, require phishing-resistant MFA, and keep only break-glass accounts permanently assigned.",
+	"entra-service-principals":              "Inventory service principals and remove unused ones. Rotate or remove stale credentials, prefer certificates or managed identities over client secrets, and limit who can add credentials to applications.",
+	"entra-oauth-grants":                    "Review delegated consent grants and revoke excessive or unused ones. Restrict user consent to verified publishers and low-risk permissions, and route other requests through the admin consent workflow.",
+	"entra-app-role-assignments":            "Review application permission assignments, especially high-privilege Microsoft Graph roles. Remove assignments that are no longer needed and monitor for new grants.",
+}
+
+// RemediationText returns the finding write-up for q: its own Remediation when
+// set, otherwise the built-in guidance for its ID.
+func (q Query) RemediationText() string {
+	if q.Remediation != "" {
+		return q.Remediation
+	}
+	return remediation[q.ID]
+}
+
+// LoadWriteups reads custom write-ups from dir: one Markdown file per finding,
+// named <query-id>.md. It returns the write-ups keyed by query ID.
+func LoadWriteups(dir string) (map[string]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]string{}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.EqualFold(filepath.Ext(name), ".md") {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		if text := strings.TrimSpace(string(b)); text != "" {
+			out[strings.TrimSuffix(name, filepath.Ext(name))] = text
+		}
+	}
+	return out, nil
+}
+
+// ApplyWriteups sets Remediation on queries that have a custom write-up and
+// returns the write-up IDs that matched no query.
+func ApplyWriteups(in []Query, writeups map[string]string) ([]Query, []string) {
+	out := append([]Query(nil), in...)
+	used := map[string]bool{}
+	for i, q := range out {
+		if text, ok := writeups[q.ID]; ok {
+			out[i].Remediation = text
+			used[q.ID] = true
+		}
+	}
+	var unknown []string
+	for id := range writeups {
+		if !used[id] {
+			unknown = append(unknown, id)
+		}
+	}
+	sort.Strings(unknown)
+	return out, unknown
+}
@@ -0,0 +1,151 @@
+package queries
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SortKey orders result rows by one column.
+type SortKey struct {
+	Column string
+	Desc   bool
+}
+
+// ParseSort parses "column[:asc|desc][,column...]".
+func ParseSort(spec string) ([]SortKey, error) {
+	var keys []SortKey
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		col, dir, _ := strings.Cut(part, ":")
+		k := SortKey{Column: strings.ToLower(strings.TrimSpace(col))}
+		switch strings.ToLower(strings.TrimSpace(dir)) {
+		case "", "asc":
+		case "desc":
+			k.Desc = true
+		default:
+			return nil, fmt.Errorf("%q: direction must be asc or desc", part)
+		}
+		if k.Column == "" {
+			return nil, fmt.Errorf("%q: missing column", part)
+		}
+		keys = append(keys, k)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("empty sort spec")
+	}
+	return keys, nil
+}
+
+// ParseSortOverrides parses --sort values of the form "<query-id>=<spec>".
+func ParseSortOverrides(specs []string) (map[string][]SortKey, error) {
+	m := make(map[string][]SortKey, len(specs))
+	for _, s := range specs {
+		id, spec, ok := strings.Cut(s, "=")
+		id = strings.TrimSpace(id)
+		if !ok || id == "" {
+			return nil, fmt.Errorf("%q: expected <query-id>=<column>[:desc]", s)
+		}
+		keys, err := ParseSort(spec)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", id, err)
+		}
+		m[id] = keys
+	}
+	return m, nil
+}
+
+// ApplySort sets Sort on the queries named in overrides and returns the
+// override IDs that matched no query.
+func ApplySort(in []Query, overrides map[string][]SortKey) ([]Query, []string) {
+	out := append([]Query(nil), in...)
+	used := map[string]bool{}
+	for i, q := range out {
+		if keys, ok := overrides[q.ID]; ok {
+			out[i].Sort = keys
+			used[q.ID] = true
+		}
+	}
+	var unknown []string
+	for id := range overrides {
+		if !used[id] {
+			unknown = append(unknown, id)
+		}
+	}
+	sort.Strings(unknown)
+	return out, unknown
+}
+
+// SortRows stably re-orders rows by keys. Numbers compare numerically, other
+// values case-insensitively as text; nulls sort last. Keys naming a column
+// the result does not have are ignored.
+func SortRows(columns []string, rows [][]any, keys []SortKey) {
+	type col struct {
+		idx  int
+		desc bool
+	}
+	var by []col
+	for _, k := range keys {
+		for i, c := range columns {
+			if strings.EqualFold(c, k.Column) {
+				by = append(by, col{i, k.Desc})
+				break
+			}
+		}
+	}
+	if len(by) == 0 {
+		return
+	}
+	sort.SliceStable(rows, func(a, b int) bool {
+		for _, c := range by {
+			x, y := cellAt(rows[a], c.idx), cellAt(rows[b], c.idx)
+			if x == nil || y == nil {
+				if (x == nil) != (y == nil) {
+					return y == nil // nulls last regardless of direction
+				}
+				continue
+			}
+			if n := compareValues(x, y); n != 0 {
+				return (n < 0) != c.desc
+			}
+		}
+		return false
+	})
+}
+
+func cellAt(row []any, i int) any {
+	if i < len(row) {
+		return row[i]
+	}
+	return nil
+}
+
+func compareValues(x, y any) int {
+	if fx, ok := number(x); ok {
+		if fy, ok := number(y); ok {
+			switch {
+			case fx < fy:
+				return -1
+			case fx > fy:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(strings.ToLower(fmt.Sprint(x)), strings.ToLower(fmt.Sprint(y)))
+}
+
+func number(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
@@ -0,0 +1,61 @@
+package queries
+
+import (
+	"regexp"
+	"strings"
+)
+
+// TierZero is how a database marks Tier Zero objects.
+type TierZero int
+
+const (
+	// HighValue is the legacy BloodHound convention: highvalue = true.
+	HighValue TierZero = iota
+	// SystemTags is BloodHound CE's: system_tags contains "admin_tier_0".
+	SystemTags
+)
+
+// tierZeroTag is the system_tags entry BloodHound CE gives Tier Zero objects.
+const tierZeroTag = "admin_tier_0"
+
+// systemTagsRewrites turn highvalue assignments and tests into their
+// system_tags equivalents, most specific first. system_tags is a
+// space-separated string, and null on untagged nodes.
+var systemTagsRewrites = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	{regexp.MustCompile(`(?i)\bSET\s+(\w+)\.highvalue\s*=\s*true\b`),
+		"SET $1.system_tags = CASE WHEN coalesce($1.system_tags, '') CONTAINS '" + tierZeroTag + "' THEN $1.system_tags ELSE trim(coalesce($1.system_tags, '') + ' " + tierZeroTag + "') END"},
+	{regexp.MustCompile(`(?i)\b(\w+)\.highvalue\s*=\s*true\b`), "coalesce($1.system_tags, '') CONTAINS '" + tierZeroTag + "'"},
+	{regexp.MustCompile(`(?i)\b(\w+)\.highvalue\s*=\s*false\b`), "NOT coalesce($1.system_tags, '') CONTAINS '" + tierZeroTag + "'"},
+	{regexp.MustCompile(`(?i)\b(\w+)\.highvalue\b`), "(coalesce($1.system_tags, '') CONTAINS '" + tierZeroTag + "')"},
+}
+
+// Rewrite adapts cypher's highvalue checks to the convention.
+func (t TierZero) Rewrite(cypher string) string {
+	if t != SystemTags {
+		return cypher
+	}
+	for _, r := range systemTagsRewrites {
+		cypher = r.re.ReplaceAllString(cypher, r.repl)
+	}
+	return cypher
+}
+
+// Apply adapts q to the convention: its Cypher, and its highvalue property
+// requirements.
+func (t TierZero) Apply(q Query) Query {
+	q.Cypher = t.Rewrite(q.Cypher)
+	if t == SystemTags && len(q.RequiresProps) > 0 {
+		props := make([]string, len(q.RequiresProps))
+		for i, p := range q.RequiresProps {
+			if label, prop, ok := strings.Cut(p, "."); ok && strings.EqualFold(prop, "highvalue") {
+				p = label + ".system_tags"
+			}
+			props[i] = p
+		}
+		q.RequiresProps = props
+	}
+	return q
+}
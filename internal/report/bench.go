@@ -0,0 +1,100 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/bakw00ds/goBloodyEll/internal/queries"
+)
+
+// BenchSample collects one query's repeated executions from a --bench run.
+type BenchSample struct {
+	Query queries.Query
+	// Durations holds the successful runs' wall-clock times.
+	Durations []time.Duration
+	Errors    int
+	LastError string
+	Rows      int
+}
+
+// BenchStat summarizes a BenchSample.
+type BenchStat struct {
+	ID       string  `json:"id"`
+	Category string  `json:"category"`
+	Runs     int     `json:"runs"`
+	Errors   int     `json:"errors"`
+	Rows     int     `json:"rows"`
+	MinMS    float64 `json:"minMs"`
+	MedianMS float64 `json:"medianMs"`
+	P95MS    float64 `json:"p95Ms"`
+	MaxMS    float64 `json:"maxMs"`
+	Error    string  `json:"error,omitempty"`
+}
+
+// BenchStats computes min/median/p95/max per query; p95 uses the
+// nearest-rank method.
+func BenchStats(samples []BenchSample) []BenchStat {
+	out := make([]BenchStat, 0, len(samples))
+	for _, s := range samples {
+		st := BenchStat{ID: s.Query.ID, Category: s.Query.Category, Runs: len(s.Durations) + s.Errors, Errors: s.Errors, Rows: s.Rows, Error: s.LastError}
+		if d := slices.Clone(s.Durations); len(d) > 0 {
+			slices.Sort(d)
+			ms := func(x time.Duration) float64 { return float64(x.Microseconds()) / 1000 }
+			st.MinMS, st.MaxMS = ms(d[0]), ms(d[len(d)-1])
+			if n := len(d); n%2 == 1 {
+				st.MedianMS = ms(d[n/2])
+			} else {
+				st.MedianMS = (ms(d[n/2-1]) + ms(d[n/2])) / 2
+			}
+			rank := (95*len(d) + 99) / 100 // ceil(0.95 n)
+			st.P95MS = ms(d[rank-1])
+		}
+		out = append(out, st)
+	}
+	return out
+}
+
+// WriteBench writes per-query timing statistics as text (aligned table),
+// json or csv to outPath, or stdout when outPath is empty.
+func WriteBench(samples []BenchSample, formatName, outPath string, opts Opts) (err error) {
+	w, err := openOutput(outPath, opts)
+	if err != nil {
+		return err
+	}
+	defer closeInto(w, &err)
+
+	stats := BenchStats(samples)
+	f := func(ms float64) string { return strconv.FormatFloat(ms, 'f', 1, 64) }
+	switch formatName {
+	case "", "text":
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', tabwriter.AlignRight)
+		fmt.Fprintln(tw, "QUERY\tRUNS\tERRORS\tROWS\tMIN ms\tMEDIAN ms\tP95 ms\tMAX ms\t")
+		for _, s := range stats {
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%s\t%s\t%s\t%s\t\n", s.ID, s.Runs, s.Errors, s.Rows, f(s.MinMS), f(s.MedianMS), f(s.P95MS), f(s.MaxMS))
+		}
+		return tw.Flush()
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			Generated time.Time   `json:"generated"`
+			Bench     []BenchStat `json:"bench"`
+		}{time.Now().UTC(), stats})
+	case "csv":
+		cw := csv.NewWriter(w)
+		cw.Comma = opts.Locale.Comma()
+		_ = cw.Write([]string{"query_id", "category", "runs", "errors", "rows", "min_ms", "median_ms", "p95_ms", "max_ms", "error"})
+		for _, s := range stats {
+			_ = cw.Write([]string{s.ID, s.Category, strconv.Itoa(s.Runs), strconv.Itoa(s.Errors), strconv.Itoa(s.Rows), f(s.MinMS), f(s.MedianMS), f(s.P95MS), f(s.MaxMS), s.Error})
+		}
+		cw.Flush()
+		return cw.Error()
+	default:
+		return fmt.Errorf("--bench supports text, json or csv output, not %s", formatName)
+	}
+}
@@ -0,0 +1,20 @@
+package report
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBenchStats(t *testing.T) {
+	var d []time.Duration
+	for i := 20; i >= 1; i-- {
+		d = append(d, time.Duration(i)*time.Millisecond)
+	}
+	st := BenchStats([]BenchSample{{Durations: d, Errors: 1}})[0]
+	if st.Runs != 21 || st.MinMS != 1 || st.MaxMS != 20 || st.MedianMS != 10.5 || st.P95MS != 19 {
+		t.Fatalf("got %+v", st)
+	}
+	if st := BenchStats([]BenchSample{{Errors: 2}})[0]; st.Runs != 2 || st.MedianMS != 0 {
+		t.Fatalf("all-failed sample: %+v", st)
+	}
+}
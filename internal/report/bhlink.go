@@ -0,0 +1,39 @@
+package report
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/bakw00ds/goBloodyEll/internal/queries"
+)
+
+// bhLinker builds BloodHound CE UI links for principal columns that have a
+// sibling "<column>_objectid" column (see queries.WithObjectIDs).
+type bhLinker struct {
+	base string
+}
+
+func newBHLinker(base string) *bhLinker {
+	base = strings.TrimRight(strings.TrimSpace(base), "/")
+	if base == "" {
+		return nil
+	}
+	return &bhLinker{base: base}
+}
+
+// link returns the UI URL for the object named in column key of row, or "".
+func (l *bhLinker) link(colIndex map[string]int, key string, row []any) string {
+	if l == nil || strings.HasSuffix(key, queries.ObjectIDSuffix) {
+		return ""
+	}
+	i, ok := colIndex[key+queries.ObjectIDSuffix]
+	if !ok || i >= len(row) || row[i] == nil {
+		return ""
+	}
+	id := strings.TrimSpace(fmt.Sprint(row[i]))
+	if id == "" {
+		return ""
+	}
+	return l.base + "/ui/explore?searchType=node&primarySearch=" + url.QueryEscape(id)
+}
@@ -0,0 +1,147 @@
+package report
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type bundleManifest struct {
+	Run     RunMeta         `json:"run"`
+	Created time.Time       `json:"created"`
+	Files   []bundleFile    `json:"files"`
+	Queries []bundleQueryMD `json:"queries"`
+}
+
+type bundleFile struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+type bundleQueryMD struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Rows   int    `json:"rows"`
+}
+
+// WriteBundle packs the XLSX, text, JSON and core CSV outputs of a run into one
+// zip. Everything sits under a timestamped top-level folder next to a
+// manifest.json listing each file's size and SHA-256 for evidence handover.
+func WriteBundle(outs []Output, path string, opts Opts, meta RunMeta) (err error) {
+	dst, err := create(path, opts)
+	if err != nil {
+		return err
+	}
+	defer closeInto(dst, &err)
+
+	created := time.Now().UTC()
+	root := "gobloodyell-" + created.Format("20060102T150405Z") + "/"
+	zw := zip.NewWriter(dst)
+	man := bundleManifest{Run: meta, Created: created}
+
+	add := func(name string, write func(io.Writer) error) error {
+		hdr := &zip.FileHeader{Name: root + name, Method: zip.Deflate, Modified: created}
+		zf, err := zw.CreateHeader(hdr)
+		if err != nil {
+			return err
+		}
+		h := sha256.New()
+		cw := &countingWriter{w: io.MultiWriter(zf, h)}
+		if err := write(cw); err != nil {
+			return err
+		}
+		man.Files = append(man.Files, bundleFile{Name: name, Size: cw.n, SHA256: hex.EncodeToString(h.Sum(nil))})
+		return nil
+	}
+
+	if err := add("report.xlsx", func(w io.Writer) error {
+		f, err := buildXLSX(outs, opts)
+		if err != nil {
+			return err
+		}
+		_, err = f.WriteTo(w, excelize.Options{Password: opts.XLSXPassword})
+		return err
+	}); err != nil {
+		return err
+	}
+	if err := add("report.txt", func(w io.Writer) error {
+		if err := writeTextToWriter(w, outs, opts); err != nil {
+			return err
+		}
+		if opts.Glossary {
+			return writeGlossaryText(w, outs)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if err := add("results.json", func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		doc := ToDocument(outs)
+		doc.Run = meta.toModel()
+		return enc.Encode(doc)
+	}); err != nil {
+		return err
+	}
+	byID := map[string]Output{}
+	for _, o := range outs {
+		byID[o.Query.ID] = o
+	}
+	for _, c := range coreExports {
+		o, ok := byID[c.id]
+		if !ok {
+			continue
+		}
+		if err := add("core/"+c.file, func(w io.Writer) error { return writeSingleCSVTo(w, o, opts) }); err != nil {
+			return err
+		}
+	}
+
+	for _, o := range outs {
+		man.Queries = append(man.Queries, bundleQueryMD{ID: o.Query.ID, Status: outputStatus(o), Rows: len(o.Result.Rows)})
+	}
+	mw, err := zw.CreateHeader(&zip.FileHeader{Name: root + "manifest.json", Method: zip.Deflate, Modified: created})
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(mw)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(man); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// outputStatus classifies an output the same way the summary sheet does.
+func outputStatus(o Output) string {
+	switch {
+	case o.Skipped:
+		return "skipped"
+	case o.NotRun:
+		return "not_run"
+	case o.Error != "":
+		return "error"
+	case len(o.Result.Rows) == 0:
+		return "empty"
+	default:
+		return "ok"
+	}
+}
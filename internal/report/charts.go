@@ -0,0 +1,82 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const chartsSheet = "Charts"
+
+// writeChartsSheet adds a tab with bar charts of findings (non-INFO queries
+// that returned rows) per category and per severity. The counts are written
+// as small tables on the sheet and the charts reference them.
+func writeChartsSheet(f *excelize.File, outs []Output, styles xlsxStyles) error {
+	if _, err := f.NewSheet(chartsSheet); err != nil {
+		return err
+	}
+	byCat := map[string]int{}
+	bySev := map[string]int{}
+	for _, o := range outs {
+		if outputStatus(o) != "ok" || strings.EqualFold(o.Query.Category, "INFO") {
+			continue
+		}
+		byCat[o.Query.Category]++
+		bySev[strings.ToLower(o.EffectiveSeverity())]++
+	}
+	cats := make([]string, 0, len(byCat))
+	for c := range byCat {
+		cats = append(cats, c)
+	}
+	sort.Strings(cats)
+	sevs := []string{"critical", "high", "medium", "low", "info"}
+
+	catEnd := writeCountTable(f, styles, 1, "category", cats, byCat)
+	sevEnd := writeCountTable(f, styles, 4, "severity", sevs, bySev)
+	_ = f.SetColWidth(chartsSheet, "A", "A", 14)
+	_ = f.SetColWidth(chartsSheet, "D", "D", 14)
+
+	if len(cats) > 0 {
+		if err := addBarChart(f, "G2", "Findings per category", "A", 2, catEnd, "5B9BD5"); err != nil {
+			return err
+		}
+	}
+	if err := addBarChart(f, "G20", "Findings per severity", "D", 2, sevEnd, "C00000"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// writeCountTable writes a two-column label/count table starting at column
+// col and returns the last data row.
+func writeCountTable(f *excelize.File, styles xlsxStyles, col int, label string, keys []string, counts map[string]int) int {
+	_ = f.SetCellValue(chartsSheet, cell(col, 1), label)
+	_ = f.SetCellValue(chartsSheet, cell(col+1, 1), "findings")
+	_ = f.SetCellStyle(chartsSheet, cell(col, 1), cell(col+1, 1), styles.header)
+	r := 2
+	for _, k := range keys {
+		_ = f.SetCellValue(chartsSheet, cell(col, r), k)
+		_ = f.SetCellValue(chartsSheet, cell(col+1, r), counts[k])
+		r++
+	}
+	return r - 1
+}
+
+func addBarChart(f *excelize.File, at, title, labelCol string, first, last int, color string) error {
+	valCol := string(rune(labelCol[0] + 1))
+	return f.AddChart(chartsSheet, at, &excelize.Chart{
+		Type: excelize.Col,
+		Series: []excelize.ChartSeries{{
+			Name:       title,
+			Categories: fmt.Sprintf("'%s'!$%s$%d:$%s$%d", chartsSheet, labelCol, first, labelCol, last),
+			Values:     fmt.Sprintf("'%s'!$%s$%d:$%s$%d", chartsSheet, valCol, first, valCol, last),
+			Fill:       excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{color}},
+		}},
+		Title:     []excelize.RichTextRun{{Text: title}},
+		Legend:    excelize.ChartLegend{Position: "none"},
+		Dimension: excelize.ChartDimension{Width: 560, Height: 300},
+		PlotArea:  excelize.ChartPlotArea{ShowVal: true},
+	})
+}
@@ -0,0 +1,94 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Theme maps severities/statuses to ANSI SGR codes for console output.
+type Theme struct {
+	Name     string
+	Severity map[string]string // critical|high|medium|low|info
+	Error    string
+	Skipped  string
+	Dim      string
+}
+
+var themes = map[string]Theme{
+	"default": {
+		Name: "default",
+		Severity: map[string]string{
+			"critical": "1;35",
+			"high":     "1;31",
+			"medium":   "1;33",
+			"low":      "1;36",
+			"info":     "1;34",
+		},
+		Error:   "31",
+		Skipped: "33",
+		Dim:     "2",
+	},
+	// bright uses the high-intensity palette, which reads better on dark/transparent terminals.
+	"bright": {
+		Name: "bright",
+		Severity: map[string]string{
+			"critical": "1;95",
+			"high":     "1;91",
+			"medium":   "1;93",
+			"low":      "1;96",
+			"info":     "1;94",
+		},
+		Error:   "91",
+		Skipped: "93",
+		Dim:     "90",
+	},
+}
+
+// LookupTheme returns a named console theme.
+func LookupTheme(name string) (Theme, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		name = "default"
+	}
+	t, ok := themes[name]
+	if !ok {
+		return Theme{}, fmt.Errorf("unknown color theme %q (expected: default|bright)", name)
+	}
+	return t, nil
+}
+
+// paint wraps s in the given SGR code; a nil theme or empty code leaves s untouched.
+func (t *Theme) paint(code, s string) string {
+	if t == nil || code == "" {
+		return s
+	}
+	return "\x1b[" + code + "m" + s + "\x1b[0m"
+}
+
+func (t *Theme) severity(sev, s string) string {
+	if t == nil {
+		return s
+	}
+	return t.paint(t.Severity[strings.ToLower(sev)], s)
+}
+
+func (t *Theme) dim(s string) string {
+	if t == nil {
+		return s
+	}
+	return t.paint(t.Dim, s)
+}
+
+func (t *Theme) errorText(s string) string {
+	if t == nil {
+		return s
+	}
+	return t.paint(t.Error, s)
+}
+
+func (t *Theme) skippedText(s string) string {
+	if t == nil {
+		return s
+	}
+	return t.paint(t.Skipped, s)
+}
@@ -0,0 +1,71 @@
+package report
+
+import (
+	"strings"
+
+	"github.com/bakw00ds/goBloodyEll/pkg/model"
+)
+
+// ParseColumns parses a --columns list into lower-cased column keys, in the
+// order given and without duplicates. Besides result column keys (user,
+// computer, ...) the combined CSV's query_id, query_title, category, status
+// and row_key columns may be named.
+func ParseColumns(s string) []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, c := range strings.Split(s, ",") {
+		c = strings.ToLower(strings.TrimSpace(c))
+		if c == "" || seen[c] {
+			continue
+		}
+		seen[c] = true
+		out = append(out, c)
+	}
+	return out
+}
+
+// selectKeys returns the entries of cols present in have, in cols order.
+func selectKeys(cols []string, have map[string]int) []string {
+	out := make([]string, 0, len(cols))
+	for _, c := range cols {
+		if _, ok := have[c]; ok {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// projectModel restricts a JSON output to the selected columns. Row keys are
+// left untouched: they identify the full row.
+func projectModel(m model.Output, cols []string) model.Output {
+	idx := make(map[string]int, len(m.Result.Columns))
+	for i, c := range m.Result.Columns {
+		idx[c] = i
+	}
+	keep := selectKeys(cols, idx)
+	rows := make([][]any, len(m.Result.Rows))
+	for r, row := range m.Result.Rows {
+		nr := make([]any, len(keep))
+		for i, c := range keep {
+			if j := idx[c]; j < len(row) {
+				nr[i] = row[j]
+			}
+		}
+		rows[r] = nr
+	}
+	m.Result.Columns, m.Result.Rows = keep, rows
+
+	hidx := make(map[string]int, len(m.Query.ColumnKeys))
+	for i, k := range m.Query.ColumnKeys {
+		if i < len(m.Query.Headers) {
+			hidx[k] = i
+		}
+	}
+	keys := selectKeys(cols, hidx)
+	headers := make([]string, len(keys))
+	for i, k := range keys {
+		headers[i] = m.Query.Headers[hidx[k]]
+	}
+	m.Query.ColumnKeys, m.Query.Headers = keys, headers
+	return m
+}
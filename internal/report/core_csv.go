@@ -3,63 +3,68 @@ package report
 import (
 	"encoding/csv"
 	"fmt"
-	"os"
-	"path/filepath"
+	"io"
 	"strings"
 
 	"github.com/bakw00ds/goBloodyEll/internal/format"
+	"github.com/bakw00ds/goBloodyEll/internal/sink"
 )
 
+// coreExports maps the inventory query IDs to their CSV file names.
+var coreExports = []struct {
+	id   string
+	file string
+}{
+	{"ad-all-users-samaccountname", "users.csv"},
+	{"ad-all-computers-fqdn", "computers.csv"},
+	{"ad-domain-admins", "domain_admins.csv"},
+	{"ad-domain-controllers", "domain_controllers.csv"},
+}
+
 // WriteCoreCSVs writes four focused CSV exports alongside the main report.
 // It expects the corresponding queries to exist in outs (by ID).
-func WriteCoreCSVs(outDir string, outs []Output) error {
+func WriteCoreCSVs(outDir string, outs []Output, opts Opts) error {
 	outDir = strings.TrimSpace(outDir)
 	if outDir == "" {
 		return nil
 	}
-	if err := os.MkdirAll(outDir, 0o755); err != nil {
+	if err := sink.MkdirAll(outDir); err != nil {
 		return err
 	}
 
-	core := []struct {
-		id   string
-		file string
-	}{
-		{"ad-all-users-samaccountname", "users.csv"},
-		{"ad-all-computers-fqdn", "computers.csv"},
-		{"ad-domain-admins", "domain_admins.csv"},
-		{"ad-domain-controllers", "domain_controllers.csv"},
-	}
-
 	byID := map[string]Output{}
 	for _, o := range outs {
 		byID[o.Query.ID] = o
 	}
 
-	for _, c := range core {
+	for _, c := range coreExports {
 		o, ok := byID[c.id]
 		if !ok {
 			continue
 		}
-		path := filepath.Join(outDir, c.file)
-		if err := writeSingleCSV(path, o); err != nil {
+		path := sink.Join(outDir, c.file)
+		if err := writeSingleCSV(path, o, opts); err != nil {
 			return fmt.Errorf("write %s: %w", c.file, err)
 		}
 	}
 	return nil
 }
 
-func writeSingleCSV(path string, o Output) error {
-	f, err := os.Create(path)
+func writeSingleCSV(path string, o Output, opts Opts) (err error) {
+	f, err := create(path, opts)
 	if err != nil {
 		return err
 	}
-	defer f.Close()
+	defer closeInto(f, &err)
+	return writeSingleCSVTo(f, o, opts)
+}
 
-	w := csv.NewWriter(f)
+func writeSingleCSVTo(dst io.Writer, o Output, opts Opts) error {
+	w := csv.NewWriter(dst)
+	w.Comma = opts.Locale.Comma()
 	defer w.Flush()
 
-	fmtter := format.New()
+	fmtter := format.NewLocale(opts.Locale)
 
 	// If we have query headers, use those. Otherwise use result columns.
 	headers := o.Query.Headers
@@ -75,7 +80,7 @@ func writeSingleCSV(path string, o Output) error {
 		return w.Error()
 	}
 	if o.Error != "" {
-		_ = w.Write([]string{"ERROR", o.Error})
+		_ = w.Write([]string{o.errorLabel(), o.Error})
 		return w.Error()
 	}
 
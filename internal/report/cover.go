@@ -0,0 +1,88 @@
+package report
+
+import (
+	"fmt"
+	_ "image/jpeg" // logo decoding for AddPicture
+	_ "image/png"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+	"gopkg.in/yaml.v3"
+)
+
+const coverSheet = "Cover"
+
+// Cover is the engagement metadata shown on the optional XLSX cover tab.
+type Cover struct {
+	Title        string `yaml:"title"`
+	Organization string `yaml:"organization"`
+	Assessor     string `yaml:"assessor"`
+	Start        string `yaml:"start"` // assessment period, free text (e.g. 2024-05-01)
+	End          string `yaml:"end"`
+	Logo         string `yaml:"logo"` // PNG or JPEG path
+	Version      string `yaml:"-"`    // tool version, filled in by the CLI
+}
+
+// LoadCover reads cover metadata from a YAML file.
+func LoadCover(path string) (*Cover, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Cover
+	if err := yaml.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return &c, nil
+}
+
+func writeCoverSheet(f *excelize.File, c *Cover, generated time.Time) error {
+	if _, err := f.NewSheet(coverSheet); err != nil {
+		return err
+	}
+	r := 2
+	if c.Logo != "" {
+		if err := f.AddPicture(coverSheet, "B2", c.Logo, &excelize.GraphicOptions{AutoFit: false, ScaleX: 1, ScaleY: 1, Positioning: "oneCell"}); err != nil {
+			return fmt.Errorf("cover logo: %w", err)
+		}
+		r = 10
+	}
+
+	title := firstNonBlank(c.Title, "Active Directory / Entra ID Assessment")
+	titleStyle, _ := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true, Size: 20, Color: "1F4E78"}})
+	labelStyle, _ := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	_ = f.SetCellValue(coverSheet, cell(2, r), title)
+	_ = f.SetCellStyle(coverSheet, cell(2, r), cell(2, r), titleStyle)
+	_ = f.SetRowHeight(coverSheet, r, 30)
+	r += 2
+
+	period := strings.TrimSpace(c.Start)
+	if e := strings.TrimSpace(c.End); e != "" {
+		period = strings.TrimSpace(period + " – " + e)
+	}
+	fields := [][2]string{
+		{"Organization", c.Organization},
+		{"Assessor", c.Assessor},
+		{"Assessment period", period},
+		{"Report generated", generated.Format("2006-01-02 15:04 MST")},
+		{"Tool", strings.TrimSpace("goBloodyEll " + c.Version)},
+	}
+	for _, fv := range fields {
+		if strings.TrimSpace(fv[1]) == "" {
+			continue
+		}
+		_ = f.SetCellValue(coverSheet, cell(2, r), fv[0])
+		_ = f.SetCellStyle(coverSheet, cell(2, r), cell(2, r), labelStyle)
+		_ = f.SetCellValue(coverSheet, cell(3, r), fv[1])
+		r++
+	}
+	_ = f.SetColWidth(coverSheet, "A", "A", 3)
+	_ = f.SetColWidth(coverSheet, "B", "B", 22)
+	_ = f.SetColWidth(coverSheet, "C", "C", 60)
+	_ = f.SetSheetView(coverSheet, 0, &excelize.ViewOptions{ShowGridLines: boolPtr(false)})
+	return nil
+}
+
+func boolPtr(b bool) *bool { return &b }
@@ -0,0 +1,145 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/bakw00ds/goBloodyEll/internal/queries"
+	"github.com/bakw00ds/goBloodyEll/internal/schema"
+)
+
+const (
+	domainsSheet = "Domains"
+	domainColumn = "domain"
+)
+
+// AddDomainColumn appends a "domain" column to a multi-domain result. Each
+// row's domain comes from the first value carrying a known domain: a
+// USER@DOMAIN name, a host.domain FQDN, or an objectid whose domain SID
+// matches. Results that already have a domain column are left as they are.
+func AddDomainColumn(o Output, domains []schema.Domain) Output {
+	if len(o.Result.Rows) == 0 {
+		return o
+	}
+	if _, ok := o.Result.ColumnIndex()[domainColumn]; ok {
+		return o
+	}
+	rows := make([][]any, len(o.Result.Rows))
+	for i, row := range o.Result.Rows {
+		var d any
+		if name := rowDomain(o.Result.Columns, row, domains); name != "" {
+			d = name
+		}
+		rows[i] = append(append(make([]any, 0, len(row)+1), row...), d)
+	}
+	o.Result.Columns = append(append([]string(nil), o.Result.Columns...), domainColumn)
+	o.Result.Rows = rows
+	o.Query.Headers = append(append([]string(nil), o.Query.Headers...), "Domain")
+	o.Query = o.Query.WithResolvedKeys()
+	return o
+}
+
+func rowDomain(cols []string, row []any, domains []schema.Domain) string {
+	for i, v := range row {
+		s, ok := v.(string)
+		if !ok || s == "" {
+			continue
+		}
+		us := strings.ToUpper(s)
+		isOID := i < len(cols) && strings.HasSuffix(cols[i], queries.ObjectIDSuffix)
+		// Prefer the longest match so child domains win over their parents.
+		best := ""
+		for _, d := range domains {
+			dn := strings.ToUpper(d.Name)
+			if dn == "" || len(d.Name) <= len(best) {
+				continue
+			}
+			if strings.HasSuffix(us, "@"+dn) || strings.HasSuffix(us, "."+dn) ||
+				(isOID && d.SID != "" && strings.HasPrefix(us, strings.ToUpper(d.SID)+"-")) {
+				best = d.Name
+			}
+		}
+		if best != "" {
+			return best
+		}
+	}
+	return ""
+}
+
+// domainStat counts one domain's finding rows per severity.
+type domainStat struct {
+	name     string
+	findings int
+	rows     int
+	severity map[string]int
+}
+
+func domainStats(outs []Output) []domainStat {
+	byName := map[string]*domainStat{}
+	for _, o := range outs {
+		if strings.EqualFold(o.Query.Category, "INFO") {
+			continue
+		}
+		idx, ok := o.Result.ColumnIndex()[domainColumn]
+		if !ok {
+			continue
+		}
+		seen := map[string]bool{}
+		for ri, row := range o.Result.Rows {
+			name := "(unknown)"
+			if idx < len(row) && row[idx] != nil {
+				name = fmt.Sprint(row[idx])
+			}
+			st := byName[name]
+			if st == nil {
+				st = &domainStat{name: name, severity: map[string]int{}}
+				byName[name] = st
+			}
+			st.rows++
+			sev := o.EffectiveSeverity()
+			if ri < len(o.RowSeverity) && o.RowSeverity[ri] != "" {
+				sev = o.RowSeverity[ri]
+			}
+			st.severity[strings.ToLower(sev)]++
+			if !seen[name] {
+				seen[name] = true
+				st.findings++
+			}
+		}
+	}
+	out := make([]domainStat, 0, len(byName))
+	for _, st := range byName {
+		out = append(out, *st)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].name < out[j].name })
+	return out
+}
+
+// writeDomainsSheet breaks finding rows down per domain and severity.
+func writeDomainsSheet(f *excelize.File, outs []Output, styles xlsxStyles) error {
+	if _, err := f.NewSheet(domainsSheet); err != nil {
+		return err
+	}
+	sevs := []string{"critical", "high", "medium", "low", "info"}
+	headers := append([]string{"domain", "findings", "rows"}, sevs...)
+	for i, h := range headers {
+		_ = f.SetCellValue(domainsSheet, cell(i+1, 1), h)
+	}
+	r := 1
+	for _, st := range domainStats(outs) {
+		r++
+		_ = f.SetCellValue(domainsSheet, cell(1, r), st.name)
+		_ = f.SetCellValue(domainsSheet, cell(2, r), st.findings)
+		_ = f.SetCellValue(domainsSheet, cell(3, r), st.rows)
+		for i, s := range sevs {
+			_ = f.SetCellValue(domainsSheet, cell(4+i, r), st.severity[s])
+		}
+	}
+	styleTable(f, domainsSheet, styles, 1, r, len(headers))
+	_ = f.SetColWidth(domainsSheet, "A", "A", 30)
+	_ = f.SetColWidth(domainsSheet, "B", "H", 10)
+	return nil
+}
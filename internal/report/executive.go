@@ -0,0 +1,137 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	executiveSheet = "Executive Summary"
+	// KPIQueryID is the INFO query whose single row feeds the executive KPIs.
+	KPIQueryID = "info-environment-kpis"
+)
+
+// KPI is one executive summary metric.
+type KPI struct {
+	Name   string
+	Value  string
+	Detail string
+}
+
+// ExecutiveKPIs derives headline metrics from a run. Exact ratios come from
+// the KPIQueryID result; without it, counts fall back to the row counts of the
+// matching finding queries and ratios are reported as n/a.
+func ExecutiveKPIs(outs []Output) []KPI {
+	counts := map[string]int64{}
+	var haveRaw bool
+	rows := map[string]int{}
+	for _, o := range outs {
+		if outputStatus(o) == "skipped" || o.Error != "" {
+			continue
+		}
+		rows[o.Query.ID] = len(o.Result.Rows)
+		if o.Query.ID == KPIQueryID && len(o.Result.Rows) > 0 {
+			haveRaw = true
+			for i, c := range o.Result.Columns {
+				if i < len(o.Result.Rows[0]) {
+					counts[c] = toInt64(o.Result.Rows[0][i])
+				}
+			}
+		}
+	}
+
+	count := func(col, fallbackQuery string) (int64, bool) {
+		if haveRaw {
+			return counts[col], true
+		}
+		n, ok := rows[fallbackQuery]
+		return int64(n), ok
+	}
+	ratio := func(name, col, fallbackQuery, denomCol, what string) KPI {
+		n, ok := count(col, fallbackQuery)
+		if !ok {
+			return KPI{Name: name, Value: "n/a", Detail: "query not run"}
+		}
+		if !haveRaw || counts[denomCol] == 0 {
+			return KPI{Name: name, Value: "n/a", Detail: fmt.Sprintf("%d %s", n, what)}
+		}
+		d := counts[denomCol]
+		return KPI{Name: name, Value: fmt.Sprintf("%.1f%%", 100*float64(n)/float64(d)), Detail: fmt.Sprintf("%d of %d", n, d)}
+	}
+	abs := func(name, col, fallbackQuery string) KPI {
+		n, ok := count(col, fallbackQuery)
+		if !ok {
+			return KPI{Name: name, Value: "n/a", Detail: "query not run"}
+		}
+		return KPI{Name: name, Value: fmt.Sprint(n)}
+	}
+
+	laps := ratio("LAPS coverage (enabled computers)", "laps_computers", "", "enabled_computers", "computers with LAPS")
+	return []KPI{
+		ratio("Kerberoastable enabled users", "kerberoastable_users", "ad-kerberoastable", "enabled_users", "kerberoastable users"),
+		ratio("AS-REP roastable enabled users", "asrep_users", "ad-asrep-roastable", "enabled_users", "AS-REP roastable users"),
+		abs("Tier Zero principals", "tier_zero", "ad-highvalue-objects"),
+		laps,
+		abs("Unconstrained delegation (non-DC)", "unconstrained_non_dc", "ad-unconstrained-delegation-non-dc"),
+	}
+}
+
+func toInt64(v any) int64 {
+	switch x := v.(type) {
+	case int64:
+		return x
+	case int:
+		return int64(x)
+	case float64:
+		return int64(x)
+	}
+	return 0
+}
+
+// severityCounts counts findings (non-INFO queries with rows) per severity.
+func severityCounts(outs []Output) map[string]int {
+	m := map[string]int{}
+	for _, o := range outs {
+		if outputStatus(o) == "ok" && !strings.EqualFold(o.Query.Category, "INFO") {
+			m[strings.ToLower(o.EffectiveSeverity())]++
+		}
+	}
+	return m
+}
+
+func writeExecutiveSheet(f *excelize.File, outs []Output, styles xlsxStyles) error {
+	if _, err := f.NewSheet(executiveSheet); err != nil {
+		return err
+	}
+	for i, h := range []string{"metric", "value", "detail"} {
+		_ = f.SetCellValue(executiveSheet, cell(i+1, 1), h)
+	}
+	_ = f.SetCellStyle(executiveSheet, "A1", "C1", styles.header)
+	r := 2
+	for _, k := range ExecutiveKPIs(outs) {
+		_ = f.SetCellValue(executiveSheet, cell(1, r), k.Name)
+		_ = f.SetCellValue(executiveSheet, cell(2, r), k.Value)
+		_ = f.SetCellValue(executiveSheet, cell(3, r), k.Detail)
+		r++
+	}
+
+	r++
+	_ = f.SetCellValue(executiveSheet, cell(1, r), "severity")
+	_ = f.SetCellValue(executiveSheet, cell(2, r), "findings")
+	_ = f.SetCellStyle(executiveSheet, cell(1, r), cell(2, r), styles.header)
+	first := r + 1
+	sev := severityCounts(outs)
+	for _, s := range []string{"critical", "high", "medium", "low", "info"} {
+		r++
+		_ = f.SetCellValue(executiveSheet, cell(1, r), s)
+		_ = f.SetCellValue(executiveSheet, cell(2, r), sev[s])
+	}
+	highlightValues(f, executiveSheet, fmt.Sprintf("A%d:A%d", first, r), styles.severity)
+
+	_ = f.SetColWidth(executiveSheet, "A", "A", 38)
+	_ = f.SetColWidth(executiveSheet, "B", "B", 12)
+	_ = f.SetColWidth(executiveSheet, "C", "C", 30)
+	return nil
+}
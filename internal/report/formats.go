@@ -0,0 +1,65 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bakw00ds/goBloodyEll/internal/sink"
+)
+
+// formatFiles maps each --formats name to the file it is written to in --out-dir.
+var formatFiles = map[string]string{
+	"json":     "results.json",
+	"csv":      "results.csv",
+	"text":     "report.txt",
+	"html":     "report.html",
+	"xlsx":     "report.xlsx",
+	"plextrac": "plextrac.json",
+}
+
+// ParseFormats validates a comma-separated --formats list, dropping duplicates.
+func ParseFormats(s string) ([]string, error) {
+	var out []string
+	seen := map[string]bool{}
+	for _, f := range strings.Split(s, ",") {
+		f = strings.ToLower(strings.TrimSpace(f))
+		if f == "" || seen[f] {
+			continue
+		}
+		if _, ok := formatFiles[f]; !ok {
+			return nil, fmt.Errorf("unknown format %q (expected: json|csv|text|html|xlsx|plextrac)", f)
+		}
+		seen[f] = true
+		out = append(out, f)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no formats given")
+	}
+	return out, nil
+}
+
+// WriteFormats writes one run's outputs in every requested format into outDir
+// and returns the paths written.
+func WriteFormats(outs []Output, formats []string, outDir string, opts Opts) ([]string, error) {
+	if err := sink.MkdirAll(outDir); err != nil {
+		return nil, err
+	}
+	written := make([]string, 0, len(formats))
+	for _, f := range formats {
+		path := sink.Join(outDir, formatFiles[f])
+		var err error
+		switch f {
+		case "xlsx":
+			err = WriteXLSX(outs, path, opts)
+		case "text":
+			err = WriteTextFile(outs, path, opts)
+		default:
+			err = WriteStructured(outs, f, path, opts)
+		}
+		if err != nil {
+			return written, fmt.Errorf("%s: %w", f, err)
+		}
+		written = append(written, opts.OutputPath(path))
+	}
+	return written, nil
+}
@@ -0,0 +1,127 @@
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/bakw00ds/goBloodyEll/internal/schema"
+)
+
+const glossarySheet = "Glossary"
+
+// edgeGlossary explains BloodHound relationship types in plain language.
+var edgeGlossary = map[string]string{
+	"MemberOf":                "The principal is a (direct or nested) member of the group and inherits its rights.",
+	"AdminTo":                 "The principal has local administrator rights on the computer.",
+	"HasSession":              "A user had a logon session on the computer when data was collected; credentials may be in memory there.",
+	"CanRDP":                  "The principal may log on to the computer over Remote Desktop.",
+	"CanPSRemote":             "The principal may connect to the computer with PowerShell Remoting (WinRM).",
+	"ExecuteDCOM":             "The principal may execute code on the computer via DCOM.",
+	"GenericAll":              "Full control over the target object, including resetting passwords and changing permissions.",
+	"GenericWrite":            "May write most attributes of the target (e.g. SPNs, logon scripts, delegation settings).",
+	"WriteDacl":               "May modify the target's permissions and so grant itself any right.",
+	"WriteOwner":              "May take ownership of the target, after which permissions can be changed.",
+	"Owns":                    "Owner of the target object; owners can always modify its permissions.",
+	"AllExtendedRights":       "All extended rights on the target, such as password reset or replication rights.",
+	"ForceChangePassword":     "May reset the target user's password without knowing the current one.",
+	"AddMember":               "May add principals to the target group.",
+	"AllowedToAct":            "Resource-based constrained delegation: the principal may impersonate users to the target computer.",
+	"AllowedToDelegate":       "Constrained delegation: the principal may impersonate users to the listed services.",
+	"GetChanges":              "Directory replication right (DS-Replication-Get-Changes); half of DCSync.",
+	"GetChangesAll":           "Directory replication right for secret data (DS-Replication-Get-Changes-All); with GetChanges enables DCSync.",
+	"GetChangesInFilteredSet": "Replication of the filtered attribute set; relevant for RODC/DCSync variants.",
+	"Contains":                "Structural containment (domain/OU/container holds the object).",
+	"GPLink":                  "The GPO is linked to (applies to) the domain or OU.",
+	"AZRoleMember":            "Entra ID: the principal holds the directory role.",
+	"AppRoleAssignment":       "Entra ID: the principal is assigned an application role on the service principal.",
+	"Client":                  "Entra ID: the application that received an OAuth consent grant.",
+	"Resource":                "Entra ID: the API/resource an OAuth consent grant applies to.",
+}
+
+var methodology = []string{
+	"Data source: a Neo4j database populated by a BloodHound-compatible collector (SharpHound, RustHound, bloodhound.py, AzureHound).",
+	"Each sheet/section is the result of one read-only Cypher query, shown verbatim so results can be reproduced.",
+	"Results reflect the graph at collection time; sessions and group memberships may have changed since.",
+	"Queries are skipped when the database lacks a label or relationship they reference; skipped checks are not evidence of absence.",
+	"Entra ID queries are best-effort: labels and properties differ between collectors and versions.",
+	"A row limit may have been applied; sheets note when results were truncated.",
+}
+
+type glossaryEntry struct {
+	edge    string
+	meaning string
+	queries []string
+}
+
+// glossaryEntries collects the relationship types used by the reported queries.
+func glossaryEntries(outs []Output) []glossaryEntry {
+	byEdge := map[string]*glossaryEntry{}
+	for _, o := range outs {
+		for _, r := range schema.RelTypes(o.Query.Cypher) {
+			e, ok := byEdge[r]
+			if !ok {
+				meaning, known := edgeGlossary[r]
+				if !known {
+					meaning = "(no description available)"
+				}
+				e = &glossaryEntry{edge: r, meaning: meaning}
+				byEdge[r] = e
+			}
+			e.queries = append(e.queries, o.Query.ID)
+		}
+	}
+	out := make([]glossaryEntry, 0, len(byEdge))
+	for _, e := range byEdge {
+		out = append(out, *e)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].edge < out[j].edge })
+	return out
+}
+
+func writeGlossarySheet(f *excelize.File, outs []Output) error {
+	if _, err := f.NewSheet(glossarySheet); err != nil {
+		return err
+	}
+	r := 1
+	_ = f.SetCellValue(glossarySheet, cell(1, r), "Methodology")
+	r++
+	for _, m := range methodology {
+		_ = f.SetCellValue(glossarySheet, cell(1, r), m)
+		r++
+	}
+	r++
+	for i, h := range []string{"edge", "meaning", "used by"} {
+		_ = f.SetCellValue(glossarySheet, cell(i+1, r), h)
+	}
+	r++
+	for _, e := range glossaryEntries(outs) {
+		_ = f.SetCellValue(glossarySheet, cell(1, r), e.edge)
+		_ = f.SetCellValue(glossarySheet, cell(2, r), e.meaning)
+		_ = f.SetCellValue(glossarySheet, cell(3, r), strings.Join(e.queries, ", "))
+		r++
+	}
+	_ = f.SetColWidth(glossarySheet, "A", "A", 24)
+	_ = f.SetColWidth(glossarySheet, "B", "B", 90)
+	_ = f.SetColWidth(glossarySheet, "C", "C", 60)
+	return nil
+}
+
+func writeGlossaryText(w io.Writer, outs []Output) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "GLOSSARY & METHODOLOGY")
+	fmt.Fprintln(bw)
+	for _, m := range methodology {
+		fmt.Fprintf(bw, "- %s\n", m)
+	}
+	fmt.Fprintln(bw)
+	for _, e := range glossaryEntries(outs) {
+		fmt.Fprintf(bw, "%s: %s\n  used by: %s\n", e.edge, e.meaning, strings.Join(e.queries, ", "))
+	}
+	fmt.Fprintln(bw, strings.Repeat("=", 100))
+	return bw.Flush()
+}
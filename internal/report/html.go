@@ -0,0 +1,147 @@
+package report
+
+import (
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/bakw00ds/goBloodyEll/internal/format"
+)
+
+var htmlReport = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>goBloodyEll report</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin: .5em 0 1.5em; font-size: 13px; }
+th, td { border: 1px solid #ccc; padding: 3px 8px; text-align: left; vertical-align: top; }
+th { background: #f0f0f0; }
+code { display: block; white-space: pre-wrap; background: #f7f7f7; padding: .5em; font-size: 12px; }
+.sev { font-weight: bold; text-transform: uppercase; }
+.critical { color: #b00020; } .high { color: #d9480f; } .medium { color: #b08800; } .low { color: #1971c2; } .info { color: #666; }
+.muted { color: #888; }
+.writeup { white-space: pre-wrap; }
+.alert { background: #ffc7ce; color: #9c0006; font-weight: bold; padding: .5em; }
+</style>
+</head>
+<body>
+<h1>goBloodyEll report</h1>
+<p class="muted">Generated {{.Generated}}</p>
+{{- if .Interrupted}}
+<p class="alert">{{.Interrupted}}</p>
+{{- end}}
+{{- if .KPIs}}
+<h2>Executive summary</h2>
+<table>
+<tr><th>Metric</th><th>Value</th><th>Detail</th></tr>
+{{- range .KPIs}}
+<tr><td>{{.Name}}</td><td><b>{{.Value}}</b></td><td>{{.Detail}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+<h2>Summary</h2>
+<table>
+<tr><th>Query</th><th>Severity</th><th>Status</th><th>Rows</th></tr>
+{{- range .Sections}}
+<tr><td><a href="#{{.ID}}">{{.Title}}</a></td><td class="sev {{.Severity}}">{{.Severity}}</td><td>{{.Status}}</td><td>{{len .Rows}}</td></tr>
+{{- end}}
+</table>
+{{- range .Sections}}
+<section id="{{.ID}}">
+<h2><span class="sev {{.Severity}}">[{{.Severity}}]</span> {{.Title}}</h2>
+<p>{{.Description}}</p>
+{{- if .FindingTitle}}<p><b>Finding:</b> {{.FindingTitle}}</p>{{end}}
+{{- if .WriteUp}}<h3>Remediation</h3><p class="writeup">{{.WriteUp}}</p>{{end}}
+{{- if .Note}}<p class="muted">Note: {{.Note}}</p>{{end}}
+<code>{{.Cypher}}</code>
+{{- if .Message}}
+<p class="muted">{{.Message}}</p>
+{{- else}}
+<table>
+<tr>{{range .Headers}}<th>{{.}}</th>{{end}}</tr>
+{{- range .Rows}}
+<tr>{{range .}}<td>{{if .Link}}<a href="{{.Link}}">{{.Text}}</a>{{else}}{{.Text}}{{end}}</td>{{end}}</tr>
+{{- end}}
+</table>
+{{- end}}
+</section>
+{{- end}}
+</body>
+</html>
+`))
+
+type htmlSection struct {
+	ID, Title, Severity, Status string
+	Description, FindingTitle   string
+	WriteUp                     string
+	Note, Cypher, Message       string
+	Headers                     []string
+	Rows                        [][]htmlCell
+}
+
+type htmlCell struct {
+	Text, Link string
+}
+
+// writeHTML renders a self-contained HTML report: a linked summary table
+// followed by one section per query.
+func writeHTML(w io.Writer, outs []Output, opts Opts) error {
+	fmtter := format.NewLocale(opts.Locale)
+	bh := newBHLinker(opts.BHUIURL)
+	data := struct {
+		Generated   string
+		Interrupted string
+		KPIs        []KPI
+		Sections    []htmlSection
+	}{Generated: time.Now().Format(time.RFC1123)}
+	if opts.interrupted() {
+		data.Interrupted = InterruptedText
+	}
+	if opts.Executive {
+		data.KPIs = ExecutiveKPIs(outs)
+	}
+	for _, o := range outs {
+		s := htmlSection{
+			ID:           o.Query.ID,
+			Title:        o.Query.Title,
+			Severity:     strings.ToLower(o.EffectiveSeverity()),
+			Status:       outputStatus(o),
+			Description:  o.Query.Description,
+			FindingTitle: o.Query.FindingTitle,
+			WriteUp:      o.Query.RemediationText(),
+			Note:         o.Note,
+			Cypher:       o.Query.Cypher,
+		}
+		switch {
+		case o.Skipped:
+			s.Message = "Skipped: " + o.SkipWhy
+		case o.NotRun:
+			s.Message = "Not run: " + o.Error
+		case o.Error != "":
+			s.Message = "Error: " + o.Error
+		case o.emptyText() != "":
+			s.Message = o.emptyText()
+		case len(o.Result.Rows) == 0:
+			s.Message = "No results."
+		}
+		s.Headers = o.Query.Headers
+		if len(s.Headers) != len(o.Result.Columns) {
+			s.Headers = o.Result.Columns
+		}
+		colIndex := o.Result.ColumnIndex()
+		for _, row := range o.Result.Rows {
+			vals := make([]htmlCell, len(o.Result.Columns))
+			for i, c := range o.Result.Columns {
+				if i < len(row) {
+					vals[i] = htmlCell{Text: fmtter.Value(c, row[i]), Link: bh.link(colIndex, c, row)}
+				}
+			}
+			s.Rows = append(s.Rows, vals)
+		}
+		data.Sections = append(data.Sections, s)
+	}
+	return htmlReport.Execute(w, data)
+}
@@ -0,0 +1,139 @@
+package report
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bakw00ds/goBloodyEll/internal/neo4jrunner"
+	"github.com/bakw00ds/goBloodyEll/internal/queries"
+	"github.com/bakw00ds/goBloodyEll/pkg/model"
+)
+
+// ReadJSON loads the outputs of a goBloodyEll JSON export (--format json or
+// the results.json of a --bundle zip),
+// and its run metadata if it has any, so they can be rendered again without
+// a database.
+func ReadJSON(path string) ([]Output, *RunMeta, error) {
+	b, err := readExport(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ReadJSONFrom(bytes.NewReader(b), path)
+}
+
+// ReadJSONFrom is ReadJSON for an export read from r; name identifies it in
+// errors.
+func ReadJSONFrom(r io.Reader, name string) ([]Output, *RunMeta, error) {
+	var doc model.Document
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, nil, fmt.Errorf("parse %s: %w", name, err)
+	}
+	if doc.SchemaVersion == "" {
+		return nil, nil, fmt.Errorf("%s: not a goBloodyEll JSON export (no schemaVersion)", name)
+	}
+	outs := make([]Output, 0, len(doc.Results))
+	for _, m := range doc.Results {
+		outs = append(outs, FromModel(m))
+	}
+	return outs, runFromModel(doc.Run), nil
+}
+
+// readExport returns the JSON document at path, taking results.json out of
+// a bundle zip.
+func readExport(path string) ([]byte, error) {
+	if !strings.EqualFold(filepath.Ext(path), ".zip") {
+		return os.ReadFile(path)
+	}
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	for _, f := range zr.File {
+		if f.Name != "results.json" && !strings.HasSuffix(f.Name, "/results.json") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return io.ReadAll(rc)
+	}
+	return nil, fmt.Errorf("%s: no results.json in the zip", path)
+}
+
+// FromModel converts an exported output back into the internal form; it is
+// the inverse of ToModel for everything the writers use.
+func FromModel(m model.Output) Output {
+	q := queries.Query{
+		ID:           m.Query.ID,
+		Title:        m.Query.Title,
+		Category:     m.Query.Category,
+		Severity:     m.Query.Severity,
+		SheetName:    m.Query.SheetName,
+		Headers:      m.Query.Headers,
+		ColumnKeys:   m.Query.ColumnKeys,
+		Description:  m.Query.Description,
+		FindingTitle: m.Query.FindingTitle,
+		Remediation:  m.Query.Remediation,
+		Cypher:       m.Query.Cypher,
+		Params:       m.Query.Params,
+	}
+	rows := make([][]any, len(m.Result.Rows))
+	for i, row := range m.Result.Rows {
+		rows[i] = make([]any, len(row))
+		for j, v := range row {
+			rows[i][j] = fromJSON(v)
+		}
+	}
+	o := Output{
+		Query:          q,
+		Result:         neo4jrunner.ResultSet{Columns: m.Result.Columns, Rows: rows, Truncated: m.Result.Truncated},
+		Error:          m.Error,
+		Skipped:        m.Status == model.StatusSkipped,
+		SkipWhy:        m.SkipReason,
+		NotRun:         m.Status == model.StatusNotRun,
+		RowSeverity:    m.RowSeverity,
+		Note:           m.Note,
+		Duration:       m.Duration(),
+		ReturnedRows:   m.ReturnedRows,
+		BelowThreshold: m.BelowThreshold,
+	}
+	if m.Severity != q.Severity {
+		o.Severity = m.Severity
+	}
+	return o
+}
+
+// fromJSON turns decoded JSON numbers back into int64/float64, recursively.
+func fromJSON(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	case []any:
+		for i := range v {
+			v[i] = fromJSON(v[i])
+		}
+		return v
+	case map[string]any:
+		for k := range v {
+			v[k] = fromJSON(v[k])
+		}
+		return v
+	default:
+		return v
+	}
+}
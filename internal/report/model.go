@@ -0,0 +1,69 @@
+package report
+
+import (
+	"github.com/bakw00ds/goBloodyEll/internal/queries"
+	"github.com/bakw00ds/goBloodyEll/pkg/model"
+)
+
+// ToModel converts an output into the public, versioned JSON model.
+func ToModel(o Output) model.Output {
+	m := model.Output{
+		Query:  modelQuery(o.Query),
+		Status: outputStatus(o),
+		Result: model.Result{
+			Columns:   o.Result.Columns,
+			Rows:      o.Result.Rows,
+			Truncated: o.Result.Truncated,
+		},
+		Severity:       o.EffectiveSeverity(),
+		RowSeverity:    o.RowSeverity,
+		Error:          o.Error,
+		SkipReason:     o.SkipWhy,
+		Note:           o.Note,
+		DurationMS:     o.Duration.Milliseconds(),
+		ReturnedRows:   o.ReturnedRows,
+		BelowThreshold: o.BelowThreshold,
+	}
+	if o.Passed() {
+		m.Assurance = NoFindingsText
+	}
+	if m.Result.Columns == nil {
+		m.Result.Columns = []string{}
+	}
+	if m.Result.Rows == nil {
+		m.Result.Rows = [][]any{}
+	}
+	if len(o.Result.Rows) > 0 {
+		m.RowKeys = make([]string, len(o.Result.Rows))
+		for i, row := range o.Result.Rows {
+			m.RowKeys[i] = RowKey(o.Query.ID, o.Result.Columns, row)
+		}
+	}
+	return m
+}
+
+// ToDocument converts a run's outputs into the top-level JSON document.
+func ToDocument(outs []Output) model.Document {
+	doc := model.Document{SchemaVersion: model.SchemaVersion, Results: make([]model.Output, 0, len(outs))}
+	for _, o := range outs {
+		doc.Results = append(doc.Results, ToModel(o))
+	}
+	return doc
+}
+
+func modelQuery(q queries.Query) model.Query {
+	return model.Query{
+		ID:           q.ID,
+		Title:        q.Title,
+		Category:     q.Category,
+		Severity:     q.Severity,
+		SheetName:    q.SheetName,
+		Headers:      q.Headers,
+		ColumnKeys:   q.ColumnKeys,
+		Description:  q.Description,
+		FindingTitle: q.FindingTitle,
+		Remediation:  q.RemediationText(),
+		Cypher:       q.Cypher,
+		Params:       q.Params,
+	}
+}
@@ -0,0 +1,83 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/bakw00ds/goBloodyEll/internal/queries"
+)
+
+// Notify posts a compact run summary to a Slack or Microsoft Teams incoming webhook.
+// Both accept a plain {"text": ...} payload; Teams is detected by host to pick line breaks.
+func Notify(ctx context.Context, webhook string, outs []Output, reportPaths []string) error {
+	u, err := url.Parse(strings.TrimSpace(webhook))
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid webhook url")
+	}
+	teams := strings.Contains(u.Host, "office.com") || strings.Contains(u.Host, "logic.azure.com")
+	text := summaryText(outs, reportPaths)
+	if teams {
+		// Teams renders markdown but collapses single newlines.
+		text = strings.ReplaceAll(text, "\n", "\n\n")
+	}
+	_, err = postJSON(ctx, webhook, "", map[string]string{"text": text})
+	return err
+}
+
+func summaryText(outs []Output, reportPaths []string) string {
+	bySev := map[string]int{}
+	var errc, skipped, notRun int
+	hits := make([]Output, 0)
+	for _, o := range outs {
+		switch {
+		case o.Skipped:
+			skipped++
+		case o.NotRun:
+			notRun++
+		case o.Error != "":
+			errc++
+		case len(o.Result.Rows) > 0 && !strings.EqualFold(o.Query.Category, "INFO"):
+			bySev[strings.ToLower(o.EffectiveSeverity())]++
+			hits = append(hits, o)
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		ri, rj := queries.SeverityRank(hits[i].EffectiveSeverity()), queries.SeverityRank(hits[j].EffectiveSeverity())
+		if ri != rj {
+			return ri < rj
+		}
+		return len(hits[i].Result.Rows) > len(hits[j].Result.Rows)
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "*goBloodyEll run complete* (%d queries, %d with findings, %d skipped, %d errors", len(outs), len(hits), skipped, errc)
+	if notRun > 0 {
+		fmt.Fprintf(&b, ", %d not run", notRun)
+	}
+	b.WriteString(")\n")
+	parts := make([]string, 0, 5)
+	for _, sev := range []string{"critical", "high", "medium", "low", "info"} {
+		if n := bySev[sev]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%s: %d", sev, n))
+		}
+	}
+	if len(parts) > 0 {
+		fmt.Fprintf(&b, "Findings by severity: %s\n", strings.Join(parts, ", "))
+	}
+	if len(hits) > 0 {
+		b.WriteString("Top findings:\n")
+		for i, o := range hits {
+			if i == 5 {
+				break
+			}
+			fmt.Fprintf(&b, "• [%s] %s — %d row(s)\n", strings.ToUpper(o.EffectiveSeverity()), firstNonBlank(o.Query.FindingTitle, o.Query.Title), len(o.Result.Rows))
+		}
+	}
+	for _, p := range reportPaths {
+		fmt.Fprintf(&b, "Report: %s\n", p)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
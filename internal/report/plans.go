@@ -0,0 +1,115 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/bakw00ds/goBloodyEll/internal/neo4jrunner"
+)
+
+// QueryPlan is one query's plan from an --explain/--profile run.
+type QueryPlan struct {
+	ID         string                     `json:"id"`
+	Category   string                     `json:"category"`
+	Status     string                     `json:"status"`
+	Error      string                     `json:"error,omitempty"`
+	DurationMS int64                      `json:"durationMs"`
+	Profiled   bool                       `json:"profiled"`
+	DBHits     int64                      `json:"dbHits"`
+	Rows       int64                      `json:"rows"`
+	Scans      []string                   `json:"scans,omitempty"`
+	Operators  []neo4jrunner.PlanOperator `json:"operators,omitempty"`
+}
+
+// Plans collects the plans of outputs produced with neo4jrunner.PlanExec.
+func Plans(outs []Output) []QueryPlan {
+	plans := make([]QueryPlan, 0, len(outs))
+	for _, o := range outs {
+		p := QueryPlan{ID: o.Query.ID, Category: o.Query.Category, Status: outputStatus(o), Error: o.Error, DurationMS: o.Duration.Milliseconds()}
+		if o.Skipped {
+			p.Error = o.SkipWhy
+		}
+		if pl := o.Result.Plan; pl != nil {
+			p.Status = "ok"
+			p.Profiled, p.DBHits, p.Rows, p.Scans, p.Operators = pl.Profiled, pl.DBHits, pl.Rows, pl.Scans, pl.Operators
+		}
+		plans = append(plans, p)
+	}
+	return plans
+}
+
+// WritePlans writes the query plans as text (an overview table followed by
+// each plan tree), json, or csv (one row per operator) to outPath, or stdout
+// when outPath is empty.
+func WritePlans(outs []Output, formatName, outPath string, opts Opts) (err error) {
+	w, err := openOutput(outPath, opts)
+	if err != nil {
+		return err
+	}
+	defer closeInto(w, &err)
+
+	plans := Plans(outs)
+	switch formatName {
+	case "", "text":
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "QUERY\tSTATUS\tDB HITS\tROWS\tDURATION\tSCANS")
+		for _, p := range plans {
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%s\t%d\n", p.ID, p.Status, p.DBHits, p.Rows, (time.Duration(p.DurationMS) * time.Millisecond).String(), len(p.Scans))
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+		for _, p := range plans {
+			fmt.Fprintf(w, "\n%s\n%s\n", p.ID, strings.Repeat("=", len(p.ID)))
+			if p.Error != "" {
+				fmt.Fprintf(w, "%s: %s\n", strings.ToUpper(p.Status), p.Error)
+				continue
+			}
+			for _, op := range p.Operators {
+				fmt.Fprintf(w, "%s+%s", strings.Repeat("  ", op.Depth), op.Operator)
+				if op.Details != "" {
+					fmt.Fprintf(w, " %s", op.Details)
+				}
+				fmt.Fprintf(w, "  (est. rows %.0f", op.EstimatedRows)
+				if p.Profiled {
+					fmt.Fprintf(w, ", rows %d, db hits %d", op.Rows, op.DBHits)
+				}
+				fmt.Fprintln(w, ")")
+			}
+			for _, s := range p.Scans {
+				fmt.Fprintf(w, "scan: %s — consider an index if this query is slow\n", s)
+			}
+		}
+		return nil
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			Generated time.Time   `json:"generated"`
+			Plans     []QueryPlan `json:"plans"`
+		}{time.Now().UTC(), plans})
+	case "csv":
+		cw := csv.NewWriter(w)
+		cw.Comma = opts.Locale.Comma()
+		_ = cw.Write([]string{"query_id", "status", "depth", "operator", "details", "estimated_rows", "rows", "db_hits", "error"})
+		for _, p := range plans {
+			if len(p.Operators) == 0 {
+				_ = cw.Write([]string{p.ID, p.Status, "", "", "", "", "", "", p.Error})
+				continue
+			}
+			for _, op := range p.Operators {
+				_ = cw.Write([]string{p.ID, p.Status, strconv.Itoa(op.Depth), op.Operator, op.Details,
+					strconv.FormatFloat(op.EstimatedRows, 'f', -1, 64), strconv.FormatInt(op.Rows, 10), strconv.FormatInt(op.DBHits, 10), ""})
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	default:
+		return fmt.Errorf("--explain/--profile support text, json or csv output, not %s", formatName)
+	}
+}
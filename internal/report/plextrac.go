@@ -0,0 +1,138 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bakw00ds/goBloodyEll/internal/format"
+)
+
+// plexTracFinding mirrors the PlexTrac findings JSON import layout.
+type plexTracFinding struct {
+	Title           string                   `json:"title"`
+	Severity        string                   `json:"severity"`
+	Status          string                   `json:"status"`
+	Description     string                   `json:"description"`
+	Recommendations string                   `json:"recommendations"`
+	References      string                   `json:"references"`
+	Tags            []string                 `json:"tags"`
+	AffectedAssets  map[string]plexTracAsset `json:"affected_assets"`
+	Fields          map[string]plexTracField `json:"fields"`
+}
+
+type plexTracAsset struct {
+	Asset string `json:"asset"`
+	Type  string `json:"type,omitempty"`
+}
+
+type plexTracField struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+// assetKeys are result columns that identify the affected object, in preference order.
+var assetKeys = []string{"computer", "fqdn", "hostname", "user", "samaccountname", "upn", "principal", "guest", "name", "service_principal", "role", "group"}
+
+// writePlexTrac emits one finding per non-INFO query that returned rows.
+func writePlexTrac(w io.Writer, outs []Output) error {
+	fmtter := format.New()
+	findings := make([]plexTracFinding, 0)
+	for _, o := range outs {
+		if o.Skipped || o.Error != "" || len(o.Result.Rows) == 0 {
+			continue
+		}
+		if strings.EqualFold(o.Query.Category, "INFO") {
+			continue
+		}
+		title := firstNonBlank(o.Query.FindingTitle, o.Query.Title)
+
+		assets := map[string]plexTracAsset{}
+		names, typ := affectedObjects(o, fmtter)
+		for _, name := range names {
+			assets[name] = plexTracAsset{Asset: name, Type: typ}
+		}
+
+		findings = append(findings, plexTracFinding{
+			Title:           title,
+			Severity:        plexTracSeverity(o.EffectiveSeverity()),
+			Status:          "Open",
+			Recommendations: o.Query.RemediationText(),
+			Description: fmt.Sprintf("%s\n\n%d affected object(s) identified.\n\nQuery (%s): %s",
+				o.Query.Description, len(o.Result.Rows), o.Query.ID, fmtter.OneLine(o.Query.Cypher)),
+			Tags:           []string{"gobloodyell", strings.ToLower(o.Query.Category)},
+			AffectedAssets: assets,
+			Fields: map[string]plexTracField{
+				"query_id": {Label: "goBloodyEll query", Value: o.Query.ID},
+				"rows":     {Label: "Result rows", Value: fmt.Sprintf("%d", len(o.Result.Rows))},
+			},
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(findings)
+}
+
+// affectedObjects returns the distinct values of the column that best identifies
+// the affected object for o, plus a coarse asset type for that column.
+func affectedObjects(o Output, fmtter *format.Formatter) ([]string, string) {
+	col := -1
+	idx := o.Result.ColumnIndex()
+	for _, k := range assetKeys {
+		if i, ok := idx[k]; ok {
+			col = i
+			break
+		}
+	}
+	if col < 0 {
+		if len(o.Result.Columns) == 0 {
+			return nil, ""
+		}
+		col = 0
+	}
+	key := o.Result.Columns[col]
+	seen := map[string]struct{}{}
+	names := make([]string, 0, len(o.Result.Rows))
+	for _, row := range o.Result.Rows {
+		if col >= len(row) {
+			continue
+		}
+		name := fmtter.Value(key, row[col])
+		if name == "" {
+			continue
+		}
+		if _, dup := seen[name]; dup {
+			continue
+		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+	}
+	return names, assetType(key)
+}
+
+func plexTracSeverity(sev string) string {
+	switch strings.ToLower(strings.TrimSpace(sev)) {
+	case "critical":
+		return "Critical"
+	case "high":
+		return "High"
+	case "medium":
+		return "Medium"
+	case "low":
+		return "Low"
+	default:
+		return "Informational"
+	}
+}
+
+func assetType(col string) string {
+	switch col {
+	case "computer", "fqdn", "hostname":
+		return "Workstation"
+	case "user", "samaccountname", "upn", "guest":
+		return "User"
+	default:
+		return ""
+	}
+}
@@ -2,179 +2,456 @@ package report
 
 import (
 	"bufio"
-	"encoding/csv"
 	"encoding/json"
 	"fmt"
-	"os"
-	"sort"
+	"io"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf16"
 
 	"github.com/xuri/excelize/v2"
+	"golang.org/x/text/width"
 
+	"github.com/bakw00ds/goBloodyEll/internal/crypt"
 	"github.com/bakw00ds/goBloodyEll/internal/format"
 	"github.com/bakw00ds/goBloodyEll/internal/neo4jrunner"
 	"github.com/bakw00ds/goBloodyEll/internal/queries"
+	"github.com/bakw00ds/goBloodyEll/internal/schema"
+	"github.com/bakw00ds/goBloodyEll/internal/sink"
 )
 
+// Output is the internal result of one query. JSON exports go through
+// ToModel/ToDocument (pkg/model), whose layout is versioned; the tags here are
+// not a compatibility promise.
 type Output struct {
 	Query   queries.Query         `json:"query"`
 	Result  neo4jrunner.ResultSet `json:"result"`
 	Error   string                `json:"error,omitempty"`
 	Skipped bool                  `json:"skipped,omitempty"`
 	SkipWhy string                `json:"skipWhy,omitempty"`
+	// NotRun marks a query that never started (--fail-fast, timeout or
+	// interrupt); Error holds the reason.
+	NotRun bool `json:"notRun,omitempty"`
+	// Severity overrides Query.Severity after per-row rules were applied.
+	Severity string `json:"severity,omitempty"`
+	// RowSeverity holds the per-row severity when rules were applied.
+	RowSeverity []string `json:"rowSeverity,omitempty"`
+	// Note carries caveats about the result, e.g. collection-quality warnings.
+	Note string `json:"note,omitempty"`
+	// Duration is the wall-clock time spent executing the query.
+	Duration time.Duration `json:"-"`
+	// ReturnedRows is how many rows the server returned, before client-side
+	// filtering such as --dedupe or --min-rows.
+	ReturnedRows int `json:"-"`
+	// BelowThreshold marks a finding whose rows were dropped by --min-rows;
+	// it returned rows, so it did not pass.
+	BelowThreshold bool `json:"belowThreshold,omitempty"`
 }
 
-func WriteStructured(outs []Output, formatName, outPath string) error {
-	w := os.Stdout
-	var f *os.File
-	if strings.TrimSpace(outPath) != "" {
-		var err error
-		f, err = os.Create(outPath)
-		if err != nil {
-			return err
-		}
-		defer f.Close()
-		w = f
+// EffectiveSeverity is the rule-adjusted severity, falling back to the query's static one.
+func (o Output) EffectiveSeverity() string {
+	if o.Severity != "" {
+		return o.Severity
+	}
+	return o.Query.Severity
+}
+
+// NoFindingsText marks finding checks that ran cleanly and returned nothing.
+const NoFindingsText = "No results — control appears satisfied"
+
+// BelowThresholdText marks finding checks whose rows were dropped by --min-rows.
+const BelowThresholdText = "Below threshold — rows returned but fewer than --min-rows; not reported"
+
+// InterruptedText is the banner of outputs written after Ctrl-C/SIGTERM.
+const InterruptedText = "RUN INTERRUPTED — results are partial; unfinished queries are reported as not run"
+
+// Passed reports whether a finding (non-INFO) query ran without error and
+// returned no rows, i.e. the check it performs passed. A finding whose rows
+// --min-rows dropped did return rows and is not a pass.
+func (o Output) Passed() bool {
+	return !o.Skipped && o.Error == "" && len(o.Result.Rows) == 0 && !o.Result.Truncated &&
+		!o.BelowThreshold && !strings.EqualFold(o.Query.Category, "INFO")
+}
+
+// emptyText is the line writers print in place of rows for a finding that
+// returned none worth reporting: NoFindingsText when it passed,
+// BelowThresholdText when --min-rows dropped its rows, "" otherwise.
+func (o Output) emptyText() string {
+	switch {
+	case o.Passed():
+		return NoFindingsText
+	case o.BelowThreshold && !o.Skipped && o.Error == "":
+		return BelowThresholdText
+	}
+	return ""
+}
+
+// errorLabel heads an output's Error in text-like formats, telling queries
+// that failed from ones that never ran.
+func (o Output) errorLabel() string {
+	if o.NotRun {
+		return "NOT RUN"
+	}
+	return "ERROR"
+}
+
+// Opts carries writer settings that are shared across output formats.
+type Opts struct {
+	// Locale controls CSV number/date serialization and delimiter.
+	Locale format.Locale
+	// Color enables ANSI console output using the theme; nil means plain text.
+	Color *Theme
+	// SkipEmpty omits empty/skipped/error sheets from the XLSX report.
+	SkipEmpty bool
+	// XLSXPassword encrypts the workbook (ECMA-376 agile encryption) when set.
+	XLSXPassword string
+	// Glossary adds the glossary/methodology sheet (XLSX) or section (text).
+	Glossary bool
+	// Charts adds a "Charts" tab with findings per category and severity.
+	Charts bool
+	// Executive adds the executive summary (KPIs) sheet / HTML section.
+	Executive bool
+	// Cover, when set, adds an engagement cover tab in front of the workbook.
+	Cover *Cover
+	// MaxCellLen truncates longer values with an ellipsis in text, console
+	// and XLSX output (0 = no limit; XLSX is always capped at Excel's
+	// 32,767-character cell limit). JSON keeps full values.
+	MaxCellLen int
+	// Domains lists the graph's AD domains; with more than one, the XLSX gets
+	// a per-domain breakdown sheet (see AddDomainColumn).
+	Domains []schema.Domain
+	// Run, when set, adds a Run Info sheet to the XLSX and a run object to
+	// JSON output.
+	Run *RunMeta
+	// Columns, when set, selects and orders the columns of the CSV, JSON and
+	// console outputs (see ParseColumns).
+	Columns []string
+	// BHUIURL is the BloodHound CE base URL; principals with an objectid
+	// column are hyperlinked to their UI object page (XLSX/HTML).
+	BHUIURL string
+	// EncryptTo encrypts every written file to these recipients with Encrypt;
+	// encrypted files get the scheme's suffix (see OutputPath).
+	EncryptTo []string
+	Encrypt   crypt.Scheme
+}
+
+// Encrypted reports whether the outputs are encrypted (--encrypt-to or
+// --xlsx-password), in which case no plaintext result rows may be written
+// to disk along the way.
+func (o Opts) Encrypted() bool { return o.Encrypt != crypt.SchemeNone || o.XLSXPassword != "" }
+
+// interrupted reports whether the outputs come from an interrupted run.
+func (o Opts) interrupted() bool { return o.Run != nil && o.Run.Interrupted }
+
+// OutputPath is the name actually written for path, including the encryption suffix.
+func (o Opts) OutputPath(path string) string {
+	if path == "" {
+		return ""
+	}
+	return path + o.Encrypt.Ext()
+}
+
+// create opens an output destination, encrypting it when opts asks for it.
+func create(path string, opts Opts) (io.WriteCloser, error) {
+	f, err := sink.Create(opts.OutputPath(path))
+	if err != nil {
+		return nil, err
+	}
+	w, err := crypt.Wrap(f, opts.EncryptTo, opts.Encrypt)
+	if err != nil {
+		sink.Abort(f)
+		f.Close()
+		return nil, err
+	}
+	if w == f {
+		return f, nil
+	}
+	return &output{WriteCloser: w, dst: f}, nil
+}
+
+// output is an encrypting writer over a sink destination; Abort reaches
+// through the encryption layer so a failed write is not uploaded.
+type output struct {
+	io.WriteCloser
+	dst io.WriteCloser
+}
+
+func (o *output) Abort() { sink.Abort(o.dst) }
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+func WriteStructured(outs []Output, formatName, outPath string, opts Opts) (err error) {
+	w, err := openOutput(outPath, opts)
+	if err != nil {
+		return err
 	}
+	defer closeInto(w, &err)
 
 	switch formatName {
 	case "json":
+		doc := ToDocument(outs)
+		if opts.Run != nil {
+			doc.Run = opts.Run.toModel()
+		}
+		if len(opts.Columns) > 0 {
+			for i, m := range doc.Results {
+				doc.Results[i] = projectModel(m, opts.Columns)
+			}
+		}
 		enc := json.NewEncoder(w)
 		enc.SetIndent("", "  ")
-		return enc.Encode(outs)
+		return enc.Encode(doc)
 	case "csv":
-		return writeCSV(w, outs)
+		return writeCSV(w, outs, opts)
 	case "text":
-		return writeTextToWriter(w, outs)
+		return writeTextToWriter(w, outs, opts)
+	case "plextrac":
+		return writePlexTrac(w, outs)
+	case "html":
+		return writeHTML(w, outs, opts)
 	default:
 		return fmt.Errorf("unknown structured format: %s", formatName)
 	}
 }
 
-func WriteConsole(outs []Output) {
+func WriteConsole(outs []Output, opts Opts) {
 	f := format.New()
+	th := opts.Color
+	sep := th.dim(strings.Repeat("=", 100))
+	if opts.interrupted() {
+		fmt.Println(th.errorText(InterruptedText))
+		fmt.Println(sep)
+	}
 	for _, o := range outs {
-		fmt.Println(o.Query.SheetName)
+		fmt.Println(th.severity(o.EffectiveSeverity(), o.Query.SheetName))
 		fmt.Println(o.Query.Description)
 		if !strings.EqualFold(o.Query.Category, "INFO") && strings.TrimSpace(o.Query.FindingTitle) != "" {
 			fmt.Println("finding title:", o.Query.FindingTitle)
 		}
+		if o.Note != "" {
+			fmt.Println(th.skippedText("note: " + o.Note))
+		}
 		fmt.Println("neo4j query:", f.OneLine(o.Query.Cypher))
 		fmt.Println()
 		if o.Skipped {
-			fmt.Println("SKIPPED:", o.SkipWhy)
-			fmt.Println(strings.Repeat("=", 100))
+			fmt.Println(th.skippedText("SKIPPED: " + o.SkipWhy))
+			fmt.Println(sep)
 			continue
 		}
 		if o.Error != "" {
-			fmt.Println("ERROR:", o.Error)
-			fmt.Println(strings.Repeat("=", 100))
+			fmt.Println(th.errorText(o.errorLabel() + ": " + o.Error))
+			fmt.Println(sep)
 			continue
 		}
 		cols := o.Result.Columns
 		colIndex := o.Result.ColumnIndex()
+		keys := o.Query.ColumnKeys
+		if len(opts.Columns) > 0 {
+			keys = selectKeys(opts.Columns, colIndex)
+		}
 		for _, row := range o.Result.Rows {
-			vals := make([]string, 0, len(o.Query.ColumnKeys))
-			for _, key := range o.Query.ColumnKeys {
+			vals := make([]string, 0, len(keys))
+			for _, key := range keys {
 				idx, ok := colIndex[key]
 				if !ok || idx >= len(row) {
 					vals = append(vals, "")
 					continue
 				}
-				vals = append(vals, f.Value(key, row[idx]))
+				vals = append(vals, format.Truncate(f.Value(key, row[idx]), opts.MaxCellLen))
+			}
+			if len(vals) == 0 && len(opts.Columns) > 0 {
+				continue // none of the selected columns in this result
 			}
 			if len(vals) == 0 {
 				// fallback to printing all columns
 				vals = make([]string, 0, len(row))
 				for i, v := range row {
-					vals = append(vals, f.Value(cols[i], v))
+					vals = append(vals, format.Truncate(f.Value(cols[i], v), opts.MaxCellLen))
 				}
 			}
 			fmt.Println(strings.Join(vals, ", "))
 		}
-		fmt.Println(strings.Repeat("=", 100))
+		if t := o.emptyText(); t != "" {
+			fmt.Println(t)
+		}
+		fmt.Println(sep)
 	}
 }
 
-func WriteTextFile(outs []Output, path string) error {
-	f, err := os.Create(path)
+func WriteTextFile(outs []Output, path string, opts Opts) (err error) {
+	f, err := create(path, opts)
 	if err != nil {
 		return err
 	}
-	defer f.Close()
-	return writeTextToWriter(f, outs)
+	defer closeInto(f, &err)
+	if err := writeTextToWriter(f, outs, opts); err != nil {
+		return err
+	}
+	if opts.Glossary {
+		return writeGlossaryText(f, outs)
+	}
+	return nil
 }
 
-func writeTextToWriter(w *os.File, outs []Output) error {
+func writeTextToWriter(w io.Writer, outs []Output, opts Opts) error {
 	fmtter := format.New()
 	bw := bufio.NewWriterSize(w, 1<<20)
 	defer bw.Flush()
+	if opts.interrupted() {
+		writeTextBanner(bw)
+	}
 	for _, o := range outs {
-		fmt.Fprintf(bw, "%s\n%s\n", o.Query.SheetName, o.Query.Description)
-		if !strings.EqualFold(o.Query.Category, "INFO") && strings.TrimSpace(o.Query.FindingTitle) != "" {
-			fmt.Fprintf(bw, "finding title: %s\n", o.Query.FindingTitle)
-		}
-		fmt.Fprintf(bw, "neo4j query: %s\n\n", fmtter.OneLine(o.Query.Cypher))
-		if o.Skipped {
-			fmt.Fprintf(bw, "SKIPPED: %s\n", o.SkipWhy)
-			fmt.Fprintf(bw, "%s\n", strings.Repeat("=", 100))
-			continue
-		}
-		if o.Error != "" {
-			fmt.Fprintf(bw, "ERROR: %s\n", o.Error)
-			fmt.Fprintf(bw, "%s\n", strings.Repeat("=", 100))
+		writeTextHeader(bw, o, fmtter)
+		if o.Skipped || o.Error != "" {
+			writeTextTrailer(bw, o)
 			continue
 		}
 		colIndex := o.Result.ColumnIndex()
 		for _, row := range o.Result.Rows {
-			vals := make([]string, 0, len(o.Query.ColumnKeys))
-			for _, key := range o.Query.ColumnKeys {
-				idx, ok := colIndex[key]
-				if !ok || idx >= len(row) {
-					vals = append(vals, "")
-					continue
-				}
-				vals = append(vals, fmtter.Value(key, row[idx]))
-			}
-			fmt.Fprintln(bw, strings.Join(vals, ","))
+			writeTextRow(bw, o.Query.ColumnKeys, colIndex, row, fmtter, opts)
 		}
-		fmt.Fprintln(bw, strings.Repeat("=", 100))
+		writeTextTrailer(bw, o)
 	}
 	return nil
 }
 
-func WriteXLSX(outs []Output, path string, skipEmpty bool) error {
+func writeTextBanner(bw *bufio.Writer) {
+	fmt.Fprintf(bw, "%s\n%s\n", InterruptedText, strings.Repeat("=", 100))
+}
+
+func writeTextHeader(bw *bufio.Writer, o Output, fmtter *format.Formatter) {
+	fmt.Fprintf(bw, "%s\n%s\n", o.Query.SheetName, o.Query.Description)
+	if !strings.EqualFold(o.Query.Category, "INFO") && strings.TrimSpace(o.Query.FindingTitle) != "" {
+		fmt.Fprintf(bw, "finding title: %s\n", o.Query.FindingTitle)
+	}
+	if wu := o.Query.RemediationText(); wu != "" {
+		fmt.Fprintf(bw, "finding write-up: %s\n", wu)
+	}
+	if o.Note != "" {
+		fmt.Fprintf(bw, "note: %s\n", o.Note)
+	}
+	fmt.Fprintf(bw, "neo4j query: %s\n\n", fmtter.OneLine(o.Query.Cypher))
+}
+
+func writeTextRow(bw *bufio.Writer, keys []string, colIndex map[string]int, row []any, fmtter *format.Formatter, opts Opts) {
+	vals := make([]string, 0, len(keys))
+	for _, key := range keys {
+		idx, ok := colIndex[key]
+		if !ok || idx >= len(row) {
+			vals = append(vals, "")
+			continue
+		}
+		vals = append(vals, format.Truncate(fmtter.Value(key, row[idx]), opts.MaxCellLen))
+	}
+	fmt.Fprintln(bw, strings.Join(vals, ","))
+}
+
+// writeTextTrailer closes a query's section after its rows.
+func writeTextTrailer(bw *bufio.Writer, o Output) {
+	switch {
+	case o.Skipped:
+		fmt.Fprintf(bw, "SKIPPED: %s\n", o.SkipWhy)
+	case o.Error != "":
+		fmt.Fprintf(bw, "%s: %s\n", o.errorLabel(), o.Error)
+	case o.emptyText() != "":
+		fmt.Fprintln(bw, o.emptyText())
+	}
+	fmt.Fprintln(bw, strings.Repeat("=", 100))
+}
+
+func WriteXLSX(outs []Output, path string, opts Opts) error {
+	f, err := buildXLSX(outs, opts)
+	if err != nil {
+		return err
+	}
+	return saveXLSX(f, path, opts)
+}
+
+func buildXLSX(outs []Output, opts Opts) (*excelize.File, error) {
 	fmtter := format.New()
+	generated := time.Now()
 	f := excelize.NewFile()
 	defaultSheet := f.GetSheetName(0)
+	styles, err := newXLSXStyles(f)
+	if err != nil {
+		return nil, err
+	}
+	bh := newBHLinker(opts.BHUIURL)
+	cellLimit := xlsxCellLimit(opts.MaxCellLen)
 
-	// Summary tab is always first.
+	// Summary tab is always first, after the optional cover.
+	if opts.Cover != nil {
+		if err := writeCoverSheet(f, opts.Cover, generated); err != nil {
+			return nil, err
+		}
+	}
 	summarySheet := "Summary"
-	summaryIdx, err := f.NewSheet(summarySheet)
-	if err != nil {
-		return err
+	if _, err := f.NewSheet(summarySheet); err != nil {
+		return nil, err
 	}
-	f.SetActiveSheet(summaryIdx)
 	// delete default sheet now that we have a real one
 	for _, name := range []string{"Sheet1", defaultSheet} {
 		name = strings.TrimSpace(name)
-		if name != "" && name != summarySheet {
+		if name != "" && name != summarySheet && name != coverSheet {
 			_ = f.DeleteSheet(name)
 		}
 	}
-	// summary tab created
-	if err := writeSummarySheet(f, summarySheet, outs); err != nil {
-		return err
+	active := summarySheet
+	if opts.Cover != nil {
+		active = coverSheet
+	}
+	activeIdx, _ := f.GetSheetIndex(active)
+	f.SetActiveSheet(activeIdx)
+	// Resolve data sheet names up front so the summary can link to them;
+	// an empty name means the output gets no sheet.
+	sheetNames := make([]string, len(outs))
+	namer := newSheetNamer(summarySheet, glossarySheet, chartsSheet, coverSheet, executiveSheet, runInfoSheet, domainsSheet)
+	for i, o := range outs {
+		if opts.SkipEmpty && (o.Skipped || o.Error != "" || len(o.Result.Rows) == 0) {
+			continue
+		}
+		if sheetNames[i], err = namer.name(o.Query.SheetName); err != nil {
+			return nil, fmt.Errorf("%s: %w", o.Query.ID, err)
+		}
+	}
+	if err := writeSummarySheet(f, summarySheet, outs, sheetNames, opts.interrupted(), styles); err != nil {
+		return nil, err
+	}
+	if opts.Executive {
+		if err := writeExecutiveSheet(f, outs, styles); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Charts {
+		if err := writeChartsSheet(f, outs, styles); err != nil {
+			return nil, err
+		}
+	}
+	if len(opts.Domains) > 1 {
+		if err := writeDomainsSheet(f, outs, styles); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Glossary {
+		if err := writeGlossarySheet(f, outs); err != nil {
+			return nil, err
+		}
 	}
 
-	for _, o := range outs {
-		if skipEmpty && (o.Skipped || o.Error != "" || len(o.Result.Rows) == 0) {
+	for oi, o := range outs {
+		sheet := sheetNames[oi]
+		if sheet == "" {
 			continue
 		}
-		sheet := safeSheetName(o.Query.SheetName)
 		_, err := f.NewSheet(sheet)
 		if err != nil {
-			return err
+			return nil, err
+		}
+		if len(o.Result.Rows) > 0 {
+			severityTab(f, sheet, o.EffectiveSeverity())
 		}
 
 		r := 1
@@ -186,6 +463,17 @@ func WriteXLSX(outs []Output, path string, skipEmpty bool) error {
 			_ = f.SetCellValue(sheet, cell(c+1, r), o.Query.FindingTitle)
 			r++
 		}
+		if wu := o.Query.RemediationText(); wu != "" {
+			_ = f.SetCellValue(sheet, cell(c, r), "finding write-up:")
+			_ = f.SetCellValue(sheet, cell(c+1, r), wu)
+			_ = f.SetCellStyle(sheet, cell(c+1, r), cell(c+1, r), styles.wrap)
+			r++
+		}
+		if o.Note != "" {
+			_ = f.SetCellValue(sheet, cell(c, r), "note:")
+			_ = f.SetCellValue(sheet, cell(c+1, r), o.Note)
+			r++
+		}
 		_ = f.SetCellValue(sheet, cell(c, r), "neo4j query:")
 		_ = f.SetCellValue(sheet, cell(c+1, r), o.Query.Cypher)
 		r += 2
@@ -193,6 +481,15 @@ func WriteXLSX(outs []Output, path string, skipEmpty bool) error {
 		for i, h := range o.Query.Headers {
 			_ = f.SetCellValue(sheet, cell(c+i, r), h)
 		}
+		// Rule-adjusted severity gets its own trailing column.
+		sevCol := 0
+		ncols := len(o.Query.Headers)
+		if len(o.RowSeverity) > 0 {
+			sevCol = c + len(o.Query.Headers)
+			ncols++
+			_ = f.SetCellValue(sheet, cell(sevCol, r), "severity")
+		}
+		headerRow := r
 		r++
 
 		// Track widths for a simple "auto-fit" (Excelize doesn't do real autofit).
@@ -202,26 +499,48 @@ func WriteXLSX(outs []Output, path string, skipEmpty bool) error {
 		}
 
 		if o.Skipped {
+			styleTable(f, sheet, styles, headerRow, headerRow, ncols)
 			_ = f.SetCellValue(sheet, cell(c, r), "SKIPPED")
 			_ = f.SetCellValue(sheet, cell(c+1, r), o.SkipWhy)
+			writeSheetFooter(f, sheet, r+2, o, generated)
 			continue
 		}
 		if o.Error != "" {
-			_ = f.SetCellValue(sheet, cell(c, r), "ERROR")
+			styleTable(f, sheet, styles, headerRow, headerRow, ncols)
+			_ = f.SetCellValue(sheet, cell(c, r), o.errorLabel())
 			_ = f.SetCellValue(sheet, cell(c+1, r), o.Error)
+			writeSheetFooter(f, sheet, r+2, o, generated)
 			continue
 		}
 
 		colIndex := o.Result.ColumnIndex()
 		rowCountForFit := 0
-		for _, row := range o.Result.Rows {
+		links := 0
+		for ri, row := range o.Result.Rows {
+			if sevCol > 0 && ri < len(o.RowSeverity) {
+				_ = f.SetCellValue(sheet, cell(sevCol, r), o.RowSeverity[ri])
+			}
 			for i, key := range o.Query.ColumnKeys {
 				idx, ok := colIndex[key]
 				if !ok || idx >= len(row) {
 					continue
 				}
-				val := fmtter.Value(key, row[idx])
-				_ = f.SetCellValue(sheet, cell(c+i, r), val)
+				val := format.Truncate(fmtter.Value(key, row[idx]), cellLimit)
+				nv := fmtter.Cell(key, row[idx])
+				if sv, ok := nv.(string); ok {
+					nv = format.Truncate(sv, cellLimit)
+				}
+				_ = f.SetCellValue(sheet, cell(c+i, r), nv)
+				if _, ok := nv.(time.Time); ok {
+					_ = f.SetCellStyle(sheet, cell(c+i, r), cell(c+i, r), styles.date)
+				}
+				if links < maxSheetLinks {
+					if u := bh.link(colIndex, key, row); u != "" {
+						_ = f.SetCellHyperLink(sheet, cell(c+i, r), u, "External")
+						_ = f.SetCellStyle(sheet, cell(c+i, r), cell(c+i, r), styles.link)
+						links++
+					}
+				}
 				// update width estimate (cap work)
 				if rowCountForFit < 300 {
 					w := displayWidth(val)
@@ -233,12 +552,78 @@ func WriteXLSX(outs []Output, path string, skipEmpty bool) error {
 			r++
 			rowCountForFit++
 		}
+		if o.Passed() {
+			_ = f.SetCellValue(sheet, cell(c, r), NoFindingsText)
+			_ = f.SetCellStyle(sheet, cell(c, r), cell(c, r), styles.pass)
+			r++
+		} else if t := o.emptyText(); t != "" {
+			_ = f.SetCellValue(sheet, cell(c, r), t)
+			r++
+		}
 
 		// Apply widths (simple heuristic).
 		applyColumnWidths(f, sheet, colWidths)
+		styleTable(f, sheet, styles, headerRow, r-1, ncols)
+		if sevCol > 0 && r-1 > headerRow {
+			highlightValues(f, sheet, cell(sevCol, headerRow+1)+":"+cell(sevCol, r-1), styles.severity)
+		}
+		writeSheetFooter(f, sheet, r+1, o, generated)
+	}
+	if opts.Run != nil {
+		if err := writeRunInfoSheet(f, *opts.Run, outs, styles); err != nil {
+			return nil, err
+		}
+	}
+
+	return f, nil
+}
+
+// excelMaxCellLen is the most characters Excel accepts in one cell.
+const excelMaxCellLen = 32767
+
+// xlsxCellLimit applies --max-cell-len within Excel's own cell limit.
+func xlsxCellLimit(max int) int {
+	if max <= 0 || max > excelMaxCellLen {
+		return excelMaxCellLen
+	}
+	return max
+}
+
+// saveXLSX writes the workbook to a local path or remote destination.
+func saveXLSX(f *excelize.File, path string, opts Opts) (err error) {
+	w, err := create(path, opts)
+	if err != nil {
+		return err
 	}
+	defer closeInto(w, &err)
+	_, err = f.WriteTo(w, excelize.Options{Password: opts.XLSXPassword})
+	return err
+}
 
-	return f.SaveAs(path)
+// closeInto closes c and reports its error through *err unless one is already set;
+// for remote destinations Close performs the upload, so its error matters. When
+// *err is already set the write failed and the upload is skipped.
+func closeInto(c io.Closer, err *error) {
+	if *err != nil {
+		if w, ok := c.(io.Writer); ok {
+			sink.Abort(w)
+		}
+	}
+	if cerr := c.Close(); *err == nil {
+		*err = cerr
+	}
+}
+
+// writeSheetFooter leaves a one-line trailer below the data so a printed or
+// screenshotted sheet still says how complete and how fresh it is.
+func writeSheetFooter(f *excelize.File, sheet string, row int, o Output, generated time.Time) {
+	truncated := "no"
+	if o.Result.Truncated {
+		truncated = "yes (row limit reached)"
+	}
+	footer := fmt.Sprintf("total rows: %d | generated: %s | query duration: %s | truncated: %s",
+		len(o.Result.Rows), generated.Format(time.RFC3339), o.Duration.Round(time.Millisecond), truncated)
+	_ = f.SetCellValue(sheet, cell(1, row), footer)
 }
 
 func safeSheetName(s string) string {
@@ -247,13 +632,52 @@ func safeSheetName(s string) string {
 		s = "Sheet"
 	}
 	repl := strings.NewReplacer(":", "-", "\\", "-", "/", "-", "?", "", "*", "", "[", "(", "]", ")")
-	s = repl.Replace(s)
-	if len(s) > 31 {
-		s = s[:31]
+	return truncateSheetName(repl.Replace(s), 31)
+}
+
+// truncateSheetName cuts s to at most max UTF-16 code units (Excel's unit for
+// the 31-character sheet name limit) without splitting a rune.
+func truncateSheetName(s string, max int) string {
+	n := 0
+	for i, r := range s {
+		n += utf16.RuneLen(r)
+		if n > max {
+			return s[:i]
+		}
 	}
 	return s
 }
 
+// sheetNamer hands out unique sheet names. Excel compares names
+// case-insensitively, so two queries whose names sanitize or truncate to the
+// same 31 characters would otherwise overwrite each other.
+type sheetNamer struct {
+	used map[string]bool
+}
+
+func newSheetNamer(reserved ...string) *sheetNamer {
+	n := &sheetNamer{used: map[string]bool{"history": true}} // reserved by Excel
+	for _, r := range reserved {
+		n.used[strings.ToLower(r)] = true
+	}
+	return n
+}
+
+// name returns safeSheetName(s), suffixed " (2)", " (3)", ... until unused.
+func (n *sheetNamer) name(s string) (string, error) {
+	base := safeSheetName(s)
+	name := base
+	for i := 2; n.used[strings.ToLower(name)]; i++ {
+		if i > 999 {
+			return "", fmt.Errorf("no unique sheet name for %q", s)
+		}
+		suffix := fmt.Sprintf(" (%d)", i)
+		name = truncateSheetName(base, 31-len(suffix)) + suffix
+	}
+	n.used[strings.ToLower(name)] = true
+	return name, nil
+}
+
 func cell(col, row int) string {
 	name, _ := excelize.ColumnNumberToName(col)
 	return fmt.Sprintf("%s%d", name, row)
@@ -280,8 +704,9 @@ func applyColumnWidths(f *excelize.File, sheet string, widths []int) {
 }
 
 func displayWidth(s string) int {
-	// rough "Excel-like" width in monospace chars.
-	// count runes but cap extreme long strings.
+	// rough "Excel-like" width in monospace chars: east-asian wide and
+	// fullwidth runes take two cells, combining marks none. Capped for
+	// extremely long strings.
 	if len(s) == 0 {
 		return 0
 	}
@@ -290,7 +715,7 @@ func displayWidth(s string) int {
 		if r == '\n' || r == '\r' || r == '\t' {
 			continue
 		}
-		w++
+		w += runeWidth(r)
 		if w > 200 {
 			break
 		}
@@ -298,56 +723,27 @@ func displayWidth(s string) int {
 	return w
 }
 
-func writeCSV(w *os.File, outs []Output) error {
-	// Determine union of keys (query_id/title/category + result columns)
-	keySet := map[string]struct{}{}
-	for _, o := range outs {
-		for _, c := range o.Result.Columns {
-			keySet[c] = struct{}{}
-		}
+func runeWidth(r rune) int {
+	if unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r) {
+		return 0
 	}
-	keys := make([]string, 0, len(keySet))
-	for k := range keySet {
-		keys = append(keys, k)
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
 	}
-	sort.Strings(keys)
-
-	header := append([]string{"query_id", "query_title", "category", "status"}, keys...)
-	cw := csv.NewWriter(w)
-	_ = cw.Write(header)
+	return 1
+}
 
-	fmtter := format.New()
+func writeCSV(w io.Writer, outs []Output, opts Opts) error {
+	u, err := NewUnionCSV(opts)
+	if err != nil {
+		return err
+	}
+	defer u.Close()
 	for _, o := range outs {
-		status := "ok"
-		if o.Skipped {
-			status = "skipped"
-		}
-		if o.Error != "" {
-			status = "error"
-		}
-
-		colIndex := o.Result.ColumnIndex()
-		if len(o.Result.Rows) == 0 {
-			rowOut := []string{o.Query.ID, o.Query.Title, o.Query.Category, status}
-			for range keys {
-				rowOut = append(rowOut, "")
-			}
-			_ = cw.Write(rowOut)
-			continue
-		}
-		for _, row := range o.Result.Rows {
-			rowOut := []string{o.Query.ID, o.Query.Title, o.Query.Category, status}
-			for _, k := range keys {
-				idx, ok := colIndex[k]
-				if !ok || idx >= len(row) {
-					rowOut = append(rowOut, "")
-					continue
-				}
-				rowOut = append(rowOut, fmtter.Value(k, row[idx]))
-			}
-			_ = cw.Write(rowOut)
+		if err := u.Add(o); err != nil {
+			return err
 		}
 	}
-	cw.Flush()
-	return cw.Error()
+	return u.WriteCSV(w)
 }
@@ -0,0 +1,30 @@
+package report
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/bakw00ds/goBloodyEll/internal/queries"
+)
+
+func TestBelowThresholdIsNotPassed(t *testing.T) {
+	q := queries.Query{ID: "ad-kerberoastable", Category: "AD", Severity: "high"}
+	clean := Output{Query: q}
+	below := Output{Query: q, ReturnedRows: 2, BelowThreshold: true}
+
+	if !clean.Passed() || below.Passed() {
+		t.Fatalf("Passed() clean=%v below=%v, want true/false", clean.Passed(), below.Passed())
+	}
+	if m := ToModel(below); m.Assurance != "" {
+		t.Errorf("below-threshold finding carries assurance %q", m.Assurance)
+	}
+	var buf bytes.Buffer
+	bw := bufio.NewWriter(&buf)
+	writeTextTrailer(bw, below)
+	bw.Flush()
+	if got := buf.String(); !strings.HasPrefix(got, BelowThresholdText) {
+		t.Errorf("text trailer = %q, want %q", got, BelowThresholdText)
+	}
+}
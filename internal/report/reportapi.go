@@ -0,0 +1,225 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/bakw00ds/goBloodyEll/internal/format"
+)
+
+// ReportAPIConfig configures pushing findings to a reporting platform at the end of a run.
+//
+// Kind "ghostwriter" talks to Ghostwriter's GraphQL endpoint (URL is the
+// /v1/graphql address). Kind "generic" POSTs one JSON document per finding to URL.
+type ReportAPIConfig struct {
+	Kind     string
+	URL      string
+	Token    string
+	ReportID int
+	// Mapping maps query IDs to a report template: for Ghostwriter a numeric
+	// finding-library ID, for generic endpoints an opaque template name.
+	Mapping map[string]string
+	// FindingTypeID is the Ghostwriter finding type of unmapped findings
+	// (default ghostwriterNetworkType).
+	FindingTypeID int
+}
+
+// ReportedFinding is the payload pushed for each finding (generic endpoints receive it as-is).
+type ReportedFinding struct {
+	QueryID        string   `json:"query_id"`
+	Template       string   `json:"template,omitempty"`
+	Title          string   `json:"title"`
+	Severity       string   `json:"severity"`
+	Description    string   `json:"description"`
+	AffectedAssets []string `json:"affected_assets"`
+	Evidence       string   `json:"evidence"`
+	Rows           int      `json:"rows"`
+}
+
+// LoadReportMapping reads a JSON object of query-id -> template.
+func LoadReportMapping(path string) (map[string]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]any{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = strings.TrimSpace(fmt.Sprint(v))
+	}
+	return out, nil
+}
+
+// PushFindings sends every non-INFO query with results to the configured endpoint.
+// It returns the number of findings pushed.
+func PushFindings(ctx context.Context, outs []Output, cfg ReportAPIConfig) (int, error) {
+	if strings.TrimSpace(cfg.URL) == "" {
+		return 0, fmt.Errorf("missing reporting API url")
+	}
+	var push func(context.Context, ReportedFinding) error
+	switch strings.ToLower(strings.TrimSpace(cfg.Kind)) {
+	case "", "generic":
+		push = func(ctx context.Context, f ReportedFinding) error {
+			_, err := postJSON(ctx, cfg.URL, cfg.Token, f)
+			return err
+		}
+	case "ghostwriter":
+		if cfg.ReportID <= 0 {
+			return 0, fmt.Errorf("ghostwriter requires a report id")
+		}
+		push = func(ctx context.Context, f ReportedFinding) error { return pushGhostwriter(ctx, cfg, f) }
+	default:
+		return 0, fmt.Errorf("unknown reporting API kind %q (expected: ghostwriter|generic)", cfg.Kind)
+	}
+
+	fmtter := format.New()
+	n := 0
+	for _, o := range outs {
+		if o.Skipped || o.Error != "" || len(o.Result.Rows) == 0 || strings.EqualFold(o.Query.Category, "INFO") {
+			continue
+		}
+		f := ReportedFinding{
+			QueryID:     o.Query.ID,
+			Template:    cfg.Mapping[o.Query.ID],
+			Title:       firstNonBlank(o.Query.FindingTitle, o.Query.Title),
+			Severity:    o.EffectiveSeverity(),
+			Description: o.Query.Description,
+			Evidence:    evidenceCSV(o, fmtter),
+			Rows:        len(o.Result.Rows),
+		}
+		f.AffectedAssets, _ = affectedObjects(o, fmtter)
+		if err := push(ctx, f); err != nil {
+			return n, fmt.Errorf("%s: %w", o.Query.ID, err)
+		}
+		n++
+	}
+	return n, nil
+}
+
+// ghostwriterSeverity maps our severities to Ghostwriter's default severity table IDs.
+var ghostwriterSeverity = map[string]int{"critical": 5, "high": 4, "medium": 3, "low": 2, "info": 1}
+
+// ghostwriterNetworkType is the "Network" row of Ghostwriter's default finding
+// type table; reportedFinding.findingTypeId is required on insert.
+const ghostwriterNetworkType = 1
+
+func pushGhostwriter(ctx context.Context, cfg ReportAPIConfig, f ReportedFinding) error {
+	fields := map[string]any{
+		"affectedEntities": strings.Join(f.AffectedAssets, "\n"),
+		"replicationSteps": "goBloodyEll query " + f.QueryID + "\n\n" + f.Evidence,
+	}
+
+	// Mapped findings are attached from the finding library, then enriched with evidence.
+	if libID, err := strconv.Atoi(f.Template); err == nil && libID > 0 {
+		var resp struct {
+			AttachFinding struct {
+				ID int `json:"id"`
+			} `json:"attachFinding"`
+		}
+		q := `mutation($findingId: Int!, $reportId: Int!) { attachFinding(findingId: $findingId, reportId: $reportId) { id } }`
+		if err := graphQL(ctx, cfg, q, map[string]any{"findingId": libID, "reportId": cfg.ReportID}, &resp); err != nil {
+			return err
+		}
+		q = `mutation($id: bigint!, $set: reportedFinding_set_input!) { update_reportedFinding_by_pk(pk_columns: {id: $id}, _set: $set) { id } }`
+		return graphQL(ctx, cfg, q, map[string]any{"id": resp.AttachFinding.ID, "set": fields}, nil)
+	}
+
+	fields["reportId"] = cfg.ReportID
+	fields["title"] = f.Title
+	fields["description"] = f.Description
+	fields["findingTypeId"] = ghostwriterNetworkType
+	if cfg.FindingTypeID > 0 {
+		fields["findingTypeId"] = cfg.FindingTypeID
+	}
+	if id, ok := ghostwriterSeverity[strings.ToLower(f.Severity)]; ok {
+		fields["severityId"] = id
+	}
+	q := `mutation($obj: reportedFinding_insert_input!) { insert_reportedFinding_one(object: $obj) { id } }`
+	return graphQL(ctx, cfg, q, map[string]any{"obj": fields}, nil)
+}
+
+func graphQL(ctx context.Context, cfg ReportAPIConfig, query string, vars map[string]any, into any) error {
+	body, err := postJSON(ctx, cfg.URL, cfg.Token, map[string]any{"query": query, "variables": vars})
+	if err != nil {
+		return err
+	}
+	var resp struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return fmt.Errorf("decode graphql response: %w", err)
+	}
+	if len(resp.Errors) > 0 {
+		return fmt.Errorf("graphql: %s", resp.Errors[0].Message)
+	}
+	if into != nil {
+		return json.Unmarshal(resp.Data, into)
+	}
+	return nil
+}
+
+func postJSON(ctx context.Context, url, token string, payload any) ([]byte, error) {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+// evidenceCSV renders the result table as CSV text for pasting into a report.
+func evidenceCSV(o Output, fmtter *format.Formatter) string {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write(o.Result.Columns)
+	for _, row := range o.Result.Rows {
+		vals := make([]string, len(o.Result.Columns))
+		for i, c := range o.Result.Columns {
+			if i < len(row) {
+				vals[i] = fmtter.Value(c, row[i])
+			}
+		}
+		_ = w.Write(vals)
+	}
+	w.Flush()
+	return buf.String()
+}
+
+func firstNonBlank(vals ...string) string {
+	for _, v := range vals {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
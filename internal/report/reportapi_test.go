@@ -0,0 +1,74 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bakw00ds/goBloodyEll/internal/neo4jrunner"
+	"github.com/bakw00ds/goBloodyEll/internal/queries"
+)
+
+func TestPushGhostwriterMutation(t *testing.T) {
+	type gqlRequest struct {
+		Query     string                     `json:"query"`
+		Variables map[string]json.RawMessage `json:"variables"`
+	}
+	var reqs []gqlRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		var req gqlRequest
+		if err := json.Unmarshal(b, &req); err != nil {
+			t.Errorf("request body: %v", err)
+		}
+		reqs = append(reqs, req)
+		io.WriteString(w, `{"data":{"attachFinding":{"id":77}}}`)
+	}))
+	defer srv.Close()
+
+	q := queries.Query{ID: "ad-kerberoastable", Title: "Kerberoastable users", Category: "AD", Severity: "high", Headers: []string{"User"}}.WithResolvedKeys()
+	outs := []Output{
+		{Query: q, Result: neo4jrunner.ResultSet{Columns: []string{"user"}, Rows: [][]any{{"SVC_SQL@CORP.LOCAL"}}}},
+		{Query: queries.Query{ID: "mapped", Title: "Mapped", Category: "AD", Severity: "low", Headers: []string{"User"}}.WithResolvedKeys(),
+			Result: neo4jrunner.ResultSet{Columns: []string{"user"}, Rows: [][]any{{"BOB@CORP.LOCAL"}}}},
+	}
+	cfg := ReportAPIConfig{Kind: "ghostwriter", URL: srv.URL, ReportID: 12, Mapping: map[string]string{"mapped": "5"}}
+	n, err := PushFindings(context.Background(), outs, cfg)
+	if err != nil || n != 2 {
+		t.Fatalf("PushFindings = %d, %v", n, err)
+	}
+	if len(reqs) != 3 {
+		t.Fatalf("got %d requests, want insert + attach + update", len(reqs))
+	}
+
+	var obj map[string]any
+	if err := json.Unmarshal(reqs[0].Variables["obj"], &obj); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{"reportId": 12.0, "findingTypeId": 1.0, "severityId": 4.0, "title": "Kerberoastable users", "affectedEntities": "SVC_SQL@CORP.LOCAL"}
+	for k, v := range want {
+		if obj[k] != v {
+			t.Errorf("insert %s = %v, want %v", k, obj[k], v)
+		}
+	}
+	if _, ok := obj["replicationSteps"].(string); !ok {
+		t.Errorf("insert lacks replicationSteps: %v", obj)
+	}
+	if _, ok := obj["replication_steps"]; ok {
+		t.Errorf("insert uses snake_case replication_steps")
+	}
+
+	var set map[string]any
+	if err := json.Unmarshal(reqs[2].Variables["set"], &set); err != nil {
+		t.Fatal(err)
+	}
+	if string(reqs[2].Variables["id"]) != "77" {
+		t.Errorf("update id = %s, want the attached finding 77", reqs[2].Variables["id"])
+	}
+	if _, ok := set["replicationSteps"].(string); !ok || set["affectedEntities"] != "BOB@CORP.LOCAL" {
+		t.Errorf("update set = %v", set)
+	}
+}
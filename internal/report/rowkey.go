@@ -0,0 +1,58 @@
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+
+	"github.com/bakw00ds/goBloodyEll/internal/format"
+	"github.com/bakw00ds/goBloodyEll/internal/queries"
+)
+
+// volatileColumns change between collections without the finding itself changing,
+// so they are left out of row keys.
+var volatileColumns = map[string]struct{}{
+	"pwdlastset":         {},
+	"lastlogon":          {},
+	"lastlogontimestamp": {},
+	"lastseen":           {},
+	"whencreated":        {},
+	"count":              {},
+	"occurrences":        {},
+	"degree":             {},
+	"nodes":              {},
+	"enabled":            {},
+	"description":        {},
+}
+
+// RowKey returns a stable identifier for a result row: a hash of the query ID
+// and the normalized (lower-cased, trimmed) identity columns, independent of
+// column order and display formatting.
+func RowKey(queryID string, cols []string, row []any) string {
+	fmtter := format.New()
+	pairs := make([]string, 0, len(cols))
+	for i, c := range cols {
+		lc := strings.ToLower(strings.TrimSpace(c))
+		if _, skip := volatileColumns[lc]; skip {
+			continue
+		}
+		// Optional --include-objectid columns must not change a row's identity.
+		if strings.HasSuffix(lc, queries.ObjectIDSuffix) {
+			continue
+		}
+		v := ""
+		if i < len(row) {
+			v = fmtter.Value(lc, row[i])
+		}
+		pairs = append(pairs, lc+"="+strings.ToLower(strings.TrimSpace(v)))
+	}
+	sort.Strings(pairs)
+	h := sha256.New()
+	h.Write([]byte(strings.ToLower(queryID)))
+	for _, p := range pairs {
+		h.Write([]byte{0})
+		h.Write([]byte(p))
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
@@ -0,0 +1,136 @@
+package report
+
+import (
+	"sort"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/bakw00ds/goBloodyEll/pkg/model"
+)
+
+const runInfoSheet = "Run Info"
+
+func (m RunMeta) toModel() *model.Run {
+	return &model.Run{
+		ID:                 m.ID,
+		ToolVersion:        m.Version,
+		CommandLine:        m.CommandLine,
+		ServerVersion:      m.ServerVersion,
+		ServerEdition:      m.ServerEdition,
+		Database:           m.Database,
+		Target:             m.Target,
+		Nodes:              m.Nodes,
+		Relationships:      m.Relationships,
+		Started:            m.Started,
+		Ended:              m.Ended,
+		Interrupted:        m.Interrupted,
+		LabelCounts:        m.LabelCounts,
+		RelationshipCounts: m.RelationshipCounts,
+	}
+}
+
+func runFromModel(r *model.Run) *RunMeta {
+	if r == nil {
+		return nil
+	}
+	return &RunMeta{
+		ID:                 r.ID,
+		Version:            r.ToolVersion,
+		CommandLine:        r.CommandLine,
+		ServerVersion:      r.ServerVersion,
+		ServerEdition:      r.ServerEdition,
+		Database:           r.Database,
+		Target:             r.Target,
+		Nodes:              r.Nodes,
+		Relationships:      r.Relationships,
+		Started:            r.Started,
+		Ended:              r.Ended,
+		Interrupted:        r.Interrupted,
+		LabelCounts:        r.LabelCounts,
+		RelationshipCounts: r.RelationshipCounts,
+	}
+}
+
+// writeRunInfoSheet records how the workbook was produced: tool and server
+// versions, target, graph size, timestamps and each query's duration.
+func writeRunInfoSheet(f *excelize.File, meta RunMeta, outs []Output, styles xlsxStyles) error {
+	if _, err := f.NewSheet(runInfoSheet); err != nil {
+		return err
+	}
+	sh := runInfoSheet
+	edition := meta.ServerEdition
+	if edition != "" {
+		edition = " (" + edition + ")"
+	}
+	kv := [][2]any{
+		{"run id", meta.ID},
+		{"tool version", meta.Version},
+		{"command line", meta.CommandLine},
+		{"neo4j server", meta.ServerVersion + edition},
+		{"target", meta.Target},
+		{"database", meta.Database},
+		{"nodes", meta.Nodes},
+		{"relationships", meta.Relationships},
+		{"started", meta.Started},
+		{"ended", meta.Ended},
+		{"duration", meta.Ended.Sub(meta.Started).Round(time.Second).String()},
+	}
+	if meta.Interrupted {
+		kv = append(kv, [2]any{"status", InterruptedText})
+	}
+	for i, p := range kv {
+		r := i + 1
+		_ = f.SetCellValue(sh, cell(1, r), p[0])
+		_ = f.SetCellValue(sh, cell(2, r), p[1])
+		if _, ok := p[1].(time.Time); ok {
+			_ = f.SetCellStyle(sh, cell(2, r), cell(2, r), styles.date)
+		}
+	}
+	_ = f.SetCellStyle(sh, cell(1, 1), cell(1, len(kv)), styles.header)
+
+	headerRow := len(kv) + 2
+	for i, h := range []string{"query id", "status", "rows", "duration (s)"} {
+		_ = f.SetCellValue(sh, cell(i+1, headerRow), h)
+	}
+	r := headerRow
+	for _, o := range outs {
+		r++
+		_ = f.SetCellValue(sh, cell(1, r), o.Query.ID)
+		_ = f.SetCellValue(sh, cell(2, r), outputStatus(o))
+		_ = f.SetCellValue(sh, cell(3, r), len(o.Result.Rows))
+		_ = f.SetCellValue(sh, cell(4, r), o.Duration.Seconds())
+	}
+	_ = f.SetCellStyle(sh, cell(1, headerRow), cell(4, headerRow), styles.header)
+	if r > headerRow {
+		highlightValues(f, sh, cell(2, headerRow+1)+":"+cell(2, r), styles.status)
+	}
+	r = writeCounts(f, sh, r+2, "label", "nodes", meta.LabelCounts, styles)
+	writeCounts(f, sh, r+2, "relationship type", "relationships", meta.RelationshipCounts, styles)
+	_ = f.SetColWidth(sh, "A", "A", 40)
+	_ = f.SetColWidth(sh, "B", "B", 60)
+	_ = f.SetColWidth(sh, "C", "D", 14)
+	return nil
+}
+
+// writeCounts writes counts as a two-column table sorted by name, starting
+// at row; it returns the last row written (row-2 when counts is empty).
+func writeCounts(f *excelize.File, sh string, row int, name, count string, counts map[string]int64, styles xlsxStyles) int {
+	if len(counts) == 0 {
+		return row - 2
+	}
+	_ = f.SetCellValue(sh, cell(1, row), name)
+	_ = f.SetCellValue(sh, cell(2, row), count)
+	_ = f.SetCellStyle(sh, cell(1, row), cell(2, row), styles.header)
+	names := make([]string, 0, len(counts))
+	for n := range counts {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		row++
+		_ = f.SetCellValue(sh, cell(1, row), n)
+		_ = f.SetCellValue(sh, cell(2, row), counts[n])
+	}
+	return row
+}
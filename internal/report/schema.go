@@ -0,0 +1,34 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/bakw00ds/goBloodyEll/internal/schema"
+)
+
+// WriteSchema writes the --schema document as json or yaml to outPath, or
+// stdout when outPath is empty.
+func WriteSchema(doc schema.Document, formatName, outPath string, opts Opts) (err error) {
+	if formatName != "json" && formatName != "yaml" {
+		return fmt.Errorf("--schema supports text, json or yaml output, not %s", formatName)
+	}
+	w, err := openOutput(outPath, opts)
+	if err != nil {
+		return err
+	}
+	defer closeInto(w, &err)
+	if formatName == "yaml" {
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(doc); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
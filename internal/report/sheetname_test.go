@@ -0,0 +1,42 @@
+package report
+
+import "testing"
+
+func TestSheetNamerCollisions(t *testing.T) {
+	n := newSheetNamer("Summary")
+	long := "Users with a very long descriptive sheet name"
+	want := []struct{ in, out string }{
+		{long, "Users with a very long descript"},
+		{long + " B", "Users with a very long desc (2)"},
+		{"summary", "summary (2)"},
+		{"Kerberoastable", "Kerberoastable"},
+		{"KERBEROASTABLE", "KERBEROASTABLE (2)"},
+		{"Kerberoastable", "Kerberoastable (3)"},
+	}
+	for _, w := range want {
+		got, err := n.name(w.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != w.out {
+			t.Fatalf("name(%q) = %q, want %q", w.in, got, w.out)
+		}
+		if len(got) > 31 {
+			t.Fatalf("name(%q) = %q exceeds 31 chars", w.in, got)
+		}
+	}
+}
+
+func TestUnicodeSheetNamesAndWidths(t *testing.T) {
+	// 30 ASCII chars followed by a 3-byte rune: byte slicing at 31 would split it.
+	name := safeSheetName("Kerberoastable users in corp x" + "日本")
+	if name != "Kerberoastable users in corp x日" {
+		t.Fatalf("got %q", name)
+	}
+	if w := displayWidth("日本語"); w != 6 {
+		t.Fatalf("displayWidth(CJK) = %d, want 6", w)
+	}
+	if w := displayWidth("café"); w != 4 {
+		t.Fatalf("displayWidth(café) = %d, want 4", w)
+	}
+}
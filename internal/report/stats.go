@@ -0,0 +1,104 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/bakw00ds/goBloodyEll/internal/crypt"
+)
+
+// openOutput opens outPath, or stdout when it is empty, encrypting either
+// when opts asks for it.
+func openOutput(outPath string, opts Opts) (io.WriteCloser, error) {
+	if strings.TrimSpace(outPath) != "" {
+		return create(outPath, opts)
+	}
+	if opts.Encrypt != crypt.SchemeNone {
+		return crypt.Wrap(nopCloser{os.Stdout}, opts.EncryptTo, opts.Encrypt)
+	}
+	return nopCloser{os.Stdout}, nil
+}
+
+// Stat is one query's row count from a --stats run.
+type Stat struct {
+	ID         string `json:"id"`
+	Category   string `json:"category"`
+	Severity   string `json:"severity"`
+	Status     string `json:"status"`
+	Count      int64  `json:"count"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"durationMs"`
+}
+
+// Stats reduces outputs of count-wrapped queries (queries.CountCypher) to
+// per-query counts.
+func Stats(outs []Output) []Stat {
+	stats := make([]Stat, 0, len(outs))
+	for _, o := range outs {
+		s := Stat{ID: o.Query.ID, Category: o.Query.Category, Severity: o.EffectiveSeverity(), Error: o.Error, DurationMS: o.Duration.Milliseconds()}
+		if len(o.Result.Rows) > 0 && len(o.Result.Rows[0]) > 0 {
+			s.Count = toInt64(o.Result.Rows[0][0])
+		}
+		switch {
+		case o.Skipped:
+			s.Status = "skipped"
+			s.Error = o.SkipWhy
+		case o.NotRun:
+			s.Status = "not_run"
+		case o.Error != "":
+			s.Status = "error"
+		case s.Count == 0:
+			s.Status = "empty"
+		default:
+			s.Status = "ok"
+		}
+		stats = append(stats, s)
+	}
+	return stats
+}
+
+// WriteStats writes per-query counts as text (aligned table), json or csv to
+// outPath, or stdout when outPath is empty.
+func WriteStats(outs []Output, formatName, outPath string, opts Opts) (err error) {
+	w, err := openOutput(outPath, opts)
+	if err != nil {
+		return err
+	}
+	defer closeInto(w, &err)
+
+	stats := Stats(outs)
+	switch formatName {
+	case "", "text":
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "QUERY\tSEVERITY\tSTATUS\tCOUNT\tDURATION")
+		for _, s := range stats {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\n", s.ID, s.Severity, s.Status, s.Count, (time.Duration(s.DurationMS) * time.Millisecond).String())
+		}
+		return tw.Flush()
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			Generated time.Time `json:"generated"`
+			Stats     []Stat    `json:"stats"`
+		}{time.Now().UTC(), stats})
+	case "csv":
+		cw := csv.NewWriter(w)
+		cw.Comma = opts.Locale.Comma()
+		_ = cw.Write([]string{"query_id", "category", "severity", "status", "count", "duration_ms", "error"})
+		for _, s := range stats {
+			_ = cw.Write([]string{s.ID, s.Category, s.Severity, s.Status, strconv.FormatInt(s.Count, 10), strconv.FormatInt(s.DurationMS, 10), s.Error})
+		}
+		cw.Flush()
+		return cw.Error()
+	default:
+		return fmt.Errorf("--stats supports text, json or csv output, not %s", formatName)
+	}
+}
@@ -0,0 +1,229 @@
+package report
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/bakw00ds/goBloodyEll/internal/crypt"
+	"github.com/bakw00ds/goBloodyEll/internal/format"
+	"github.com/bakw00ds/goBloodyEll/internal/sink"
+)
+
+// StreamFormats are the --format values that can be written while rows are
+// still arriving (see --stream).
+var StreamFormats = []string{"csv", "ndjson", "text"}
+
+// StreamWriter writes query results row by row so memory stays flat for very
+// large results. For each query, Row is called for every row (o carries the
+// query and note, not the rows) and Finish once the query is done.
+// Interrupted marks the run as cancelled so Close records that the output is
+// partial.
+type StreamWriter interface {
+	Row(o Output, cols []string, row []any) error
+	Finish(o Output) error
+	Interrupted()
+	Close() error
+}
+
+// NewStreamWriter opens a streaming writer for formatName (csv, ndjson or
+// text) writing to outPath, or stdout when outPath is empty.
+func NewStreamWriter(formatName, outPath string, opts Opts) (StreamWriter, error) {
+	var dst io.WriteCloser = nopCloser{os.Stdout}
+	if strings.TrimSpace(outPath) != "" {
+		f, err := create(outPath, opts)
+		if err != nil {
+			return nil, err
+		}
+		dst = f
+	} else if opts.Encrypt != crypt.SchemeNone {
+		f, err := crypt.Wrap(nopCloser{os.Stdout}, opts.EncryptTo, opts.Encrypt)
+		if err != nil {
+			return nil, err
+		}
+		dst = f
+	}
+	bw := bufio.NewWriterSize(dst, 1<<20)
+	switch formatName {
+	case "csv":
+		u, err := NewUnionCSV(opts)
+		if err != nil {
+			sink.Abort(dst)
+			_ = dst.Close()
+			return nil, err
+		}
+		return &csvStream{streamBase: streamBase{dst: dst, bw: bw}, u: u}, nil
+	case "ndjson":
+		return &ndjsonStream{streamBase: streamBase{dst: dst, bw: bw}, enc: json.NewEncoder(bw), fmtter: format.NewLocale(opts.Locale)}, nil
+	case "text":
+		return &textStream{streamBase: streamBase{dst: dst, bw: bw}, fmtter: format.New(), opts: opts}, nil
+	default:
+		sink.Abort(dst)
+		_ = dst.Close()
+		return nil, fmt.Errorf("--stream supports %s output, not %q", strings.Join(StreamFormats, "|"), formatName)
+	}
+}
+
+// streamBase tracks the query currently being written.
+type streamBase struct {
+	dst     io.WriteCloser
+	bw      *bufio.Writer
+	current string // ID of the query whose rows are being written
+	// interrupted is set by Interrupted.
+	interrupted bool
+}
+
+func (s *streamBase) Interrupted() { s.interrupted = true }
+
+// started reports whether rows of o's query were already written, marking
+// it current otherwise.
+func (s *streamBase) started(o Output) bool {
+	if s.current == o.Query.ID {
+		return true
+	}
+	s.current = o.Query.ID
+	return false
+}
+
+// close flushes and closes the destination; when err (from the caller) or the
+// flush fails, a remote destination is discarded rather than uploaded.
+func (s *streamBase) close(err error) error {
+	if ferr := s.bw.Flush(); err == nil {
+		err = ferr
+	}
+	closeInto(s.dst, &err)
+	return err
+}
+
+// csvStream spools rows through UnionCSV and writes the combined CSV on Close,
+// once the union of all columns is known.
+type csvStream struct {
+	streamBase
+	u *UnionCSV
+}
+
+func (s *csvStream) Row(o Output, cols []string, row []any) error {
+	if !s.started(o) {
+		if err := s.u.begin(o.Query.ID, o.Query.Title, o.Query.Category, "ok", cols); err != nil {
+			return err
+		}
+	}
+	return s.u.row(o.Query.ID, cols, row)
+}
+
+func (s *csvStream) Finish(o Output) error {
+	if !s.started(o) {
+		if err := s.u.begin(o.Query.ID, o.Query.Title, o.Query.Category, unionStatus(o), nil); err != nil {
+			return err
+		}
+	}
+	s.current = ""
+	return nil
+}
+
+func (s *csvStream) Close() error {
+	err := s.u.WriteCSV(s.bw)
+	if cerr := s.u.Close(); err == nil {
+		err = cerr
+	}
+	return s.close(err)
+}
+
+// ndjsonStream writes one JSON object per row, plus a status object for
+// queries that returned no rows.
+type ndjsonStream struct {
+	streamBase
+	enc    *json.Encoder
+	fmtter *format.Formatter
+}
+
+type ndjsonRecord struct {
+	QueryID  string            `json:"query_id"`
+	Category string            `json:"category"`
+	Severity string            `json:"severity"`
+	Status   string            `json:"status"`
+	Reason   string            `json:"reason,omitempty"`
+	RowKey   string            `json:"row_key,omitempty"`
+	Row      map[string]string `json:"row,omitempty"`
+}
+
+func (s *ndjsonStream) Row(o Output, cols []string, row []any) error {
+	s.started(o)
+	m := make(map[string]string, len(cols))
+	for i, c := range cols {
+		if i < len(row) {
+			m[c] = s.fmtter.Value(c, row[i])
+		}
+	}
+	return s.enc.Encode(ndjsonRecord{QueryID: o.Query.ID, Category: o.Query.Category, Severity: o.EffectiveSeverity(), Status: "ok", RowKey: RowKey(o.Query.ID, cols, row), Row: m})
+}
+
+func (s *ndjsonStream) Finish(o Output) error {
+	if s.started(o) && o.Error == "" {
+		s.current = ""
+		return nil
+	}
+	s.current = ""
+	rec := ndjsonRecord{QueryID: o.Query.ID, Category: o.Query.Category, Severity: o.EffectiveSeverity(), Status: outputStatus(o), Reason: o.Error}
+	if o.Skipped {
+		rec.Reason = o.SkipWhy
+	} else if o.Passed() {
+		rec.Reason = NoFindingsText
+	}
+	return s.enc.Encode(rec)
+}
+
+// Close ends an interrupted run with a status record carrying
+// InterruptedText and no query_id.
+func (s *ndjsonStream) Close() error {
+	if s.interrupted {
+		if err := s.enc.Encode(ndjsonRecord{Status: "interrupted", Reason: InterruptedText}); err != nil {
+			return s.close(err)
+		}
+	}
+	return s.close(nil)
+}
+
+// textStream writes the text report layout incrementally.
+type textStream struct {
+	streamBase
+	fmtter   *format.Formatter
+	opts     Opts
+	colIndex map[string]int
+}
+
+func (s *textStream) Row(o Output, cols []string, row []any) error {
+	if !s.started(o) {
+		writeTextHeader(s.bw, o, s.fmtter)
+		s.colIndex = make(map[string]int, len(cols))
+		for i, c := range cols {
+			s.colIndex[c] = i
+		}
+	}
+	writeTextRow(s.bw, o.Query.ColumnKeys, s.colIndex, row, s.fmtter, s.opts)
+	return nil
+}
+
+func (s *textStream) Finish(o Output) error {
+	started := s.started(o)
+	s.current = ""
+	if !started {
+		writeTextHeader(s.bw, o, s.fmtter)
+	} else if o.Error == "" {
+		// Rows were written, so the check neither passed nor failed to run.
+		fmt.Fprintln(s.bw, strings.Repeat("=", 100))
+		return nil
+	}
+	writeTextTrailer(s.bw, o)
+	return nil
+}
+
+func (s *textStream) Close() error {
+	if s.interrupted {
+		writeTextBanner(s.bw)
+	}
+	return s.close(nil)
+}
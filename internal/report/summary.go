@@ -3,21 +3,26 @@ package report
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/xuri/excelize/v2"
 
 	"github.com/bakw00ds/goBloodyEll/internal/format"
 )
 
-func writeSummarySheet(f *excelize.File, sheet string, outs []Output) error {
+// writeSummarySheet writes the table of contents. sheetNames[i] is the data
+// sheet of outs[i] ("" if none); those cells link to the sheet. An
+// interrupted run gets a banner below the totals.
+func writeSummarySheet(f *excelize.File, sheet string, outs []Output, sheetNames []string, interrupted bool, styles xlsxStyles) error {
 	fmtter := format.New()
 	// header
-	headers := []string{"order", "category", "sheet", "id", "status", "rows", "cypher"}
+	headers := []string{"order", "category", "sheet", "id", "severity", "status", "rows", "duration (s)", "cypher"}
 	for i, h := range headers {
 		_ = f.SetCellValue(sheet, cell(i+1, 1), h)
 	}
+	_ = f.SetCellStyle(sheet, "A1", cell(len(headers), 1), styles.header)
 
-	ok, errc, skipped, empty := 0, 0, 0, 0
+	ok, errc, skipped, notRun, empty := 0, 0, 0, 0, 0
 	row := 2
 	for i, o := range outs {
 		status := "ok"
@@ -25,6 +30,9 @@ func writeSummarySheet(f *excelize.File, sheet string, outs []Output) error {
 		if o.Skipped {
 			status = "skipped"
 			skipped++
+		} else if o.NotRun {
+			status = "not run"
+			notRun++
 		} else if o.Error != "" {
 			status = "error"
 			errc++
@@ -38,12 +46,23 @@ func writeSummarySheet(f *excelize.File, sheet string, outs []Output) error {
 		_ = f.SetCellValue(sheet, cell(1, row), i+1)
 		_ = f.SetCellValue(sheet, cell(2, row), o.Query.Category)
 		_ = f.SetCellValue(sheet, cell(3, row), o.Query.SheetName)
+		if i < len(sheetNames) && sheetNames[i] != "" {
+			target := "'" + strings.ReplaceAll(sheetNames[i], "'", "''") + "'!A1"
+			_ = f.SetCellHyperLink(sheet, cell(3, row), target, "Location", excelize.HyperlinkOpts{Tooltip: &sheetNames[i]})
+			_ = f.SetCellStyle(sheet, cell(3, row), cell(3, row), styles.link)
+		}
 		_ = f.SetCellValue(sheet, cell(4, row), o.Query.ID)
-		_ = f.SetCellValue(sheet, cell(5, row), status)
-		_ = f.SetCellValue(sheet, cell(6, row), rows)
-		_ = f.SetCellValue(sheet, cell(7, row), fmtter.OneLine(o.Query.Cypher))
+		_ = f.SetCellValue(sheet, cell(5, row), strings.ToLower(o.EffectiveSeverity()))
+		_ = f.SetCellValue(sheet, cell(6, row), status)
+		_ = f.SetCellValue(sheet, cell(7, row), rows)
+		_ = f.SetCellValue(sheet, cell(8, row), o.Duration.Round(time.Millisecond).Seconds())
+		_ = f.SetCellValue(sheet, cell(9, row), fmtter.OneLine(o.Query.Cypher))
 		row++
 	}
+	if len(outs) > 0 {
+		highlightValues(f, sheet, fmt.Sprintf("E2:E%d", row-1), styles.severity)
+		highlightValues(f, sheet, fmt.Sprintf("F2:F%d", row-1), styles.status)
+	}
 
 	// totals
 	row++
@@ -52,15 +71,23 @@ func writeSummarySheet(f *excelize.File, sheet string, outs []Output) error {
 	_ = f.SetCellValue(sheet, cell(3, row), fmt.Sprintf("empty=%d", empty))
 	_ = f.SetCellValue(sheet, cell(4, row), fmt.Sprintf("skipped=%d", skipped))
 	_ = f.SetCellValue(sheet, cell(5, row), fmt.Sprintf("error=%d", errc))
-	_ = f.SetCellValue(sheet, cell(6, row), fmt.Sprintf("total=%d", len(outs)))
+	_ = f.SetCellValue(sheet, cell(6, row), fmt.Sprintf("not run=%d", notRun))
+	_ = f.SetCellValue(sheet, cell(7, row), fmt.Sprintf("total=%d", len(outs)))
+	if interrupted {
+		row += 2
+		_ = f.SetCellValue(sheet, cell(1, row), InterruptedText)
+		_ = f.MergeCell(sheet, cell(1, row), cell(9, row))
+		_ = f.SetCellStyle(sheet, cell(1, row), cell(9, row), styles.alert)
+	}
 
 	// width hints
 	_ = f.SetColWidth(sheet, "A", "A", 8)
 	_ = f.SetColWidth(sheet, "B", "B", 10)
 	_ = f.SetColWidth(sheet, "C", "C", 30)
 	_ = f.SetColWidth(sheet, "D", "D", 30)
-	_ = f.SetColWidth(sheet, "E", "F", 10)
-	_ = f.SetColWidth(sheet, "G", "G", 80)
+	_ = f.SetColWidth(sheet, "E", "G", 10)
+	_ = f.SetColWidth(sheet, "H", "H", 12)
+	_ = f.SetColWidth(sheet, "I", "I", 80)
 
 	// freeze header row
 	_ = f.SetPanes(sheet, &excelize.Panes{
@@ -71,7 +98,7 @@ func writeSummarySheet(f *excelize.File, sheet string, outs []Output) error {
 		TopLeftCell: "A2",
 		ActivePane:  "bottomLeft",
 		Selection: []excelize.Selection{{
-			SQRef:      "A2:G1048576",
+			SQRef:      "A2:I1048576",
 			ActiveCell: "A2",
 			Pane:       "bottomLeft",
 		}},
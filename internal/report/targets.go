@@ -0,0 +1,133 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bakw00ds/goBloodyEll/internal/queries"
+)
+
+// TargetColumn leads every row of a combined multi-target report.
+const TargetColumn = "target"
+
+// TargetRun is one target's outputs in a multi-target run.
+type TargetRun struct {
+	Name string
+	Outs []Output
+}
+
+// CombineTargets merges the per-target outputs of one query pack into one
+// output per query, in the order queries first appear. Each row gets a
+// leading "target" column; rows of targets whose columns differ are aligned
+// by column name. A query is only an error (or skipped/not run) when it was
+// for every target; otherwise the other targets' errors go into its Note.
+func CombineTargets(runs []TargetRun) []Output {
+	var order []string
+	byID := map[string][]int{} // query ID -> index into runs, parallel to outs
+	outsByID := map[string][]Output{}
+	for ri, r := range runs {
+		for _, o := range r.Outs {
+			if _, ok := outsByID[o.Query.ID]; !ok {
+				order = append(order, o.Query.ID)
+			}
+			byID[o.Query.ID] = append(byID[o.Query.ID], ri)
+			outsByID[o.Query.ID] = append(outsByID[o.Query.ID], o)
+		}
+	}
+
+	combined := make([]Output, 0, len(order))
+	for _, id := range order {
+		parts := outsByID[id]
+		// Skipped and NotRun hold only if they hold for every target.
+		c := Output{Query: parts[0].Query, Skipped: true, NotRun: true}
+		c.Query.Headers = append([]string{"Target"}, c.Query.Headers...)
+		c.Query = c.Query.WithResolvedKeys()
+		c.Result.Columns = []string{TargetColumn}
+		colIdx := map[string]int{}
+		var errs, skips []string
+		withRowSev := false
+		for _, o := range parts {
+			withRowSev = withRowSev || len(o.RowSeverity) > 0
+		}
+		failed := 0
+		for k, o := range parts {
+			target := runs[byID[id][k]].Name
+			c.Skipped = c.Skipped && o.Skipped
+			c.NotRun = c.NotRun && o.NotRun
+			switch {
+			case o.Skipped:
+				skips = append(skips, fmt.Sprintf("%s: %s", target, o.SkipWhy))
+				continue
+			case o.Error != "":
+				failed++
+				errs = append(errs, fmt.Sprintf("%s: %s", target, o.Error))
+				continue
+			}
+			if queries.SeverityRank(o.EffectiveSeverity()) < queries.SeverityRank(c.EffectiveSeverity()) {
+				c.Severity = o.EffectiveSeverity()
+			}
+			for _, col := range o.Result.Columns {
+				if _, ok := colIdx[col]; !ok {
+					colIdx[col] = len(c.Result.Columns)
+					c.Result.Columns = append(c.Result.Columns, col)
+				}
+			}
+			for i, row := range o.Result.Rows {
+				out := make([]any, len(c.Result.Columns))
+				out[0] = target
+				for j, v := range row {
+					if j < len(o.Result.Columns) {
+						out[colIdx[o.Result.Columns[j]]] = v
+					}
+				}
+				c.Result.Rows = append(c.Result.Rows, out)
+				if withRowSev {
+					sev := o.EffectiveSeverity()
+					if i < len(o.RowSeverity) {
+						sev = o.RowSeverity[i]
+					}
+					c.RowSeverity = append(c.RowSeverity, sev)
+				}
+			}
+			c.Result.Truncated = c.Result.Truncated || o.Result.Truncated
+			c.ReturnedRows += o.ReturnedRows
+			c.Duration += o.Duration
+		}
+		// Rows appended before a later target added columns are short.
+		for i, row := range c.Result.Rows {
+			if len(row) < len(c.Result.Columns) {
+				c.Result.Rows[i] = append(row, make([]any, len(c.Result.Columns)-len(row))...)
+			}
+		}
+		switch {
+		case c.Skipped:
+			c.NotRun = false
+			c.SkipWhy = strings.Join(skips, "; ")
+		case failed+len(skips) == len(parts):
+			c.Error = strings.Join(errs, "; ")
+		default:
+			c.NotRun = false
+			var notes []string
+			for _, o := range parts {
+				if o.Note != "" {
+					notes = append(notes, o.Note)
+				}
+			}
+			c.Note = strings.Join(append(dedupeStrings(notes), errs...), "; ")
+		}
+		combined = append(combined, c)
+	}
+	return combined
+}
+
+func dedupeStrings(in []string) []string {
+	seen := map[string]bool{}
+	out := in[:0]
+	for _, s := range in {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
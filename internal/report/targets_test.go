@@ -0,0 +1,40 @@
+package report
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bakw00ds/goBloodyEll/internal/neo4jrunner"
+	"github.com/bakw00ds/goBloodyEll/internal/queries"
+)
+
+func TestCombineTargets(t *testing.T) {
+	q := queries.Query{ID: "a", Category: "AD", Severity: "medium", Headers: []string{"User"}}.WithResolvedKeys()
+	runs := []TargetRun{
+		{Name: "acme", Outs: []Output{
+			{Query: q, Result: neo4jrunner.ResultSet{Columns: []string{"user"}, Rows: [][]any{{"bob"}}}},
+			{Query: queries.Query{ID: "b"}, Error: "boom"},
+		}},
+		{Name: "lab", Outs: []Output{
+			{Query: q, Severity: "high", Result: neo4jrunner.ResultSet{Columns: []string{"user", "x"}, Rows: [][]any{{"amy", 1}}}},
+			{Query: queries.Query{ID: "b"}, Error: "bang"},
+		}},
+	}
+	got := CombineTargets(runs)
+	if len(got) != 2 {
+		t.Fatalf("want 2 outputs, got %d", len(got))
+	}
+	a := got[0]
+	if want := []string{"target", "user"}; !reflect.DeepEqual(a.Query.ColumnKeys, want) {
+		t.Fatalf("column keys: got %v want %v", a.Query.ColumnKeys, want)
+	}
+	if want := [][]any{{"acme", "bob", nil}, {"lab", "amy", 1}}; !reflect.DeepEqual(a.Result.Rows, want) {
+		t.Fatalf("rows: got %v want %v", a.Result.Rows, want)
+	}
+	if a.EffectiveSeverity() != "high" {
+		t.Fatalf("severity: got %q, want the highest across targets", a.EffectiveSeverity())
+	}
+	if got[1].Error != "acme: boom; lab: bang" {
+		t.Fatalf("error: got %q", got[1].Error)
+	}
+}
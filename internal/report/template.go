@@ -0,0 +1,109 @@
+package report
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/bakw00ds/goBloodyEll/internal/format"
+	"github.com/bakw00ds/goBloodyEll/internal/queries"
+	"github.com/bakw00ds/goBloodyEll/pkg/model"
+)
+
+// TemplateData is the value passed to --report-template templates. Results use
+// the public model types so templates keep working across releases.
+type TemplateData struct {
+	Run           RunMeta
+	Generated     time.Time
+	SchemaVersion string
+	Results       []model.Output
+	// Findings are the non-INFO results that returned rows, most severe first.
+	Findings []model.Output
+	// Counts holds the number of results per status (ok, empty, skipped, error).
+	Counts map[string]int
+}
+
+// Template entry points, tried in order. The .html variant is rendered with
+// html/template (contextual escaping); the other with text/template.
+var templateEntries = []string{"report.html.tmpl", "report.tmpl"}
+
+// WriteTemplate renders a user-supplied report layout from dir to outPath
+// (stdout if empty). Every *.tmpl file in dir is parsed, so layouts can split
+// into partials via {{template "name" .}}; rendering starts at report.tmpl or
+// report.html.tmpl.
+func WriteTemplate(outs []Output, dir, outPath string, opts Opts, meta RunMeta) (err error) {
+	entry := ""
+	for _, e := range templateEntries {
+		if _, err := os.Stat(filepath.Join(dir, e)); err == nil {
+			entry = e
+			break
+		}
+	}
+	if entry == "" {
+		return fmt.Errorf("%s: no %s found", dir, strings.Join(templateEntries, " or "))
+	}
+
+	fmtter := format.NewLocale(opts.Locale)
+	funcs := map[string]any{
+		"cell":  fmtter.Value,
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+		"join":  strings.Join,
+		"add":   func(a, b int) int { return a + b },
+		"date":  func(layout string, t time.Time) string { return t.Format(layout) },
+	}
+	glob := filepath.Join(dir, "*.tmpl")
+	var exec func(io.Writer, string, any) error
+	if strings.HasSuffix(entry, ".html.tmpl") {
+		t, err := htmltemplate.New(entry).Funcs(funcs).ParseGlob(glob)
+		if err != nil {
+			return err
+		}
+		exec = t.ExecuteTemplate
+	} else {
+		t, err := template.New(entry).Funcs(funcs).ParseGlob(glob)
+		if err != nil {
+			return err
+		}
+		exec = t.ExecuteTemplate
+	}
+
+	var w io.Writer = os.Stdout
+	if strings.TrimSpace(outPath) != "" {
+		var f io.WriteCloser
+		f, err = create(outPath, opts)
+		if err != nil {
+			return err
+		}
+		defer closeInto(f, &err)
+		w = f
+	}
+	return exec(w, entry, templateData(outs, meta))
+}
+
+func templateData(outs []Output, meta RunMeta) TemplateData {
+	d := TemplateData{
+		Run:           meta,
+		Generated:     time.Now(),
+		SchemaVersion: model.SchemaVersion,
+		Counts:        map[string]int{},
+	}
+	for _, o := range outs {
+		m := ToModel(o)
+		d.Results = append(d.Results, m)
+		d.Counts[m.Status]++
+		if m.Status == model.StatusOK && !strings.EqualFold(o.Query.Category, "INFO") {
+			d.Findings = append(d.Findings, m)
+		}
+	}
+	sort.SliceStable(d.Findings, func(i, j int) bool {
+		return queries.SeverityRank(d.Findings[i].Severity) < queries.SeverityRank(d.Findings[j].Severity)
+	})
+	return d
+}
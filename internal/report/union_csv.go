@@ -0,0 +1,237 @@
+package report
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/bakw00ds/goBloodyEll/internal/crypt"
+	"github.com/bakw00ds/goBloodyEll/internal/format"
+)
+
+// UnionCSV builds the combined CSV (one header with the union of all result
+// columns) in two passes without keeping result rows in memory:
+//
+//  1. Add formats each output's rows and spools them to a temp file, recording
+//     the columns it saw (the per-query manifest).
+//  2. WriteCSV emits the union header and re-reads the spool, mapping every row
+//     onto the union columns.
+//
+// Callers may drop an Output's rows as soon as Add returns. When the outputs
+// are encrypted the spool is encrypted too, to a throwaway key held in
+// memory, so no plaintext rows reach the disk.
+//
+// Spool record layout (first field is the record type):
+//
+//	H, query_id, query_title, category, status, ncols, col1..colN
+//	R, row_key, val1..valN
+type UnionCSV struct {
+	opts   Opts
+	fmtter *format.Formatter
+	spool  *os.File
+	key    *crypt.Ephemeral // set when the spool is encrypted
+	enc    io.WriteCloser   // encrypts into spool when key is set
+	buf    *bufio.Writer
+	sw     *csv.Writer
+	keys   map[string]struct{}
+}
+
+// unionMetaColumns lead every combined CSV record.
+var unionMetaColumns = []string{"query_id", "query_title", "category", "status", "row_key"}
+
+// NewUnionCSV creates a combined CSV writer backed by a temp spool file,
+// encrypted when opts encrypts the outputs.
+func NewUnionCSV(opts Opts) (*UnionCSV, error) {
+	u := &UnionCSV{opts: opts, fmtter: format.NewLocale(opts.Locale), keys: map[string]struct{}{}}
+	f, err := os.CreateTemp("", "gobloodyell-union-*.csv")
+	if err != nil {
+		return nil, err
+	}
+	u.spool = f
+	var dst io.Writer = f
+	if opts.Encrypted() {
+		if u.key, err = crypt.NewEphemeral(); err == nil {
+			u.enc, err = u.key.Encrypt(f)
+		}
+		if err != nil {
+			u.Close()
+			return nil, fmt.Errorf("encrypt spool: %w", err)
+		}
+		dst = u.enc
+	}
+	u.buf = bufio.NewWriterSize(dst, 1<<20)
+	u.sw = csv.NewWriter(u.buf)
+	return u, nil
+}
+
+// Add spools a single query output.
+func (u *UnionCSV) Add(o Output) error {
+	if err := u.begin(o.Query.ID, o.Query.Title, o.Query.Category, unionStatus(o), o.Result.Columns); err != nil {
+		return err
+	}
+	for _, row := range o.Result.Rows {
+		if err := u.row(o.Query.ID, o.Result.Columns, row); err != nil {
+			return err
+		}
+	}
+	return u.sw.Error()
+}
+
+// unionStatus is the status column value; empty results count as "ok".
+func unionStatus(o Output) string {
+	switch {
+	case o.NotRun:
+		return "not_run"
+	case o.Error != "":
+		return "error"
+	case o.Skipped:
+		return "skipped"
+	}
+	return "ok"
+}
+
+// begin spools the header record of one query.
+func (u *UnionCSV) begin(id, title, category, status string, cols []string) error {
+	for _, c := range cols {
+		u.keys[c] = struct{}{}
+	}
+	return u.sw.Write(append([]string{"H", id, title, category, status, strconv.Itoa(len(cols))}, cols...))
+}
+
+// row spools one result row of the query last passed to begin.
+func (u *UnionCSV) row(queryID string, cols []string, row []any) error {
+	rec := make([]string, 0, len(cols)+2)
+	rec = append(rec, "R", RowKey(queryID, cols, row))
+	for i, c := range cols {
+		v := ""
+		if i < len(row) {
+			v = u.fmtter.Value(c, row[i])
+		}
+		rec = append(rec, v)
+	}
+	return u.sw.Write(rec)
+}
+
+// WriteCSV performs the second pass, writing the combined CSV to w.
+func (u *UnionCSV) WriteCSV(w io.Writer) error {
+	u.sw.Flush()
+	if err := u.sw.Error(); err != nil {
+		return err
+	}
+	if err := u.buf.Flush(); err != nil {
+		return err
+	}
+	if u.enc != nil {
+		if err := u.enc.Close(); err != nil {
+			return err
+		}
+	}
+	if _, err := u.spool.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	var spool io.Reader = u.spool
+	if u.key != nil {
+		var err error
+		if spool, err = u.key.Decrypt(u.spool); err != nil {
+			return fmt.Errorf("decrypt spool: %w", err)
+		}
+	}
+
+	keys := make([]string, 0, len(u.keys))
+	for k := range u.keys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pos := make(map[string]int, len(keys))
+	for i, k := range keys {
+		pos[k] = i
+	}
+
+	cw := csv.NewWriter(w)
+	cw.Comma = u.opts.Locale.Comma()
+	header := append(append([]string(nil), unionMetaColumns...), keys...)
+	// pick maps each written column to its index in the full union record.
+	var pick []int
+	if len(u.opts.Columns) > 0 {
+		full := make(map[string]int, len(header))
+		for i, h := range header {
+			full[h] = i
+		}
+		header = selectKeys(u.opts.Columns, full)
+		pick = make([]int, len(header))
+		for i, h := range header {
+			pick[i] = full[h]
+		}
+	}
+	write := func(rec []string) {
+		if pick != nil {
+			sel := make([]string, len(pick))
+			for i, j := range pick {
+				sel[i] = rec[j]
+			}
+			rec = sel
+		}
+		_ = cw.Write(rec)
+	}
+	_ = cw.Write(header)
+
+	sr := csv.NewReader(bufio.NewReaderSize(spool, 1<<20))
+	sr.FieldsPerRecord = -1
+	sr.ReuseRecord = true
+
+	var (
+		prefix  []string
+		cols    []string
+		pending bool // header seen but no row written yet
+	)
+	emitEmpty := func() {
+		if pending {
+			write(append(append([]string(nil), prefix...), make([]string, len(keys))...))
+		}
+	}
+	for {
+		rec, err := sr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("read spool: %w", err)
+		}
+		switch rec[0] {
+		case "H":
+			emitEmpty()
+			prefix = append(append([]string(nil), rec[1:5]...), "")
+			n, _ := strconv.Atoi(rec[5])
+			cols = append([]string(nil), rec[6:6+n]...)
+			pending = true
+		case "R":
+			out := make([]string, len(prefix)+len(keys))
+			copy(out, prefix)
+			out[len(prefix)-1] = rec[1]
+			for i, c := range cols {
+				if i+2 < len(rec) {
+					out[len(prefix)+pos[c]] = rec[i+2]
+				}
+			}
+			write(out)
+			pending = false
+		}
+	}
+	emitEmpty()
+	cw.Flush()
+	return cw.Error()
+}
+
+// Close removes the spool file.
+func (u *UnionCSV) Close() error {
+	name := u.spool.Name()
+	err := u.spool.Close()
+	if rmErr := os.Remove(name); err == nil {
+		err = rmErr
+	}
+	return err
+}
@@ -0,0 +1,103 @@
+package report
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/bakw00ds/goBloodyEll/internal/neo4jrunner"
+	"github.com/bakw00ds/goBloodyEll/internal/queries"
+)
+
+func TestUnionCSV(t *testing.T) {
+	u, err := NewUnionCSV(Opts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer u.Close()
+	outs := []Output{
+		{Query: queries.Query{ID: "a", Category: "AD"}, Result: neo4jrunner.ResultSet{Columns: []string{"user"}, Rows: [][]any{{"bob"}, {"x,y"}}}},
+		{Query: queries.Query{ID: "b", Category: "AD"}, Skipped: true},
+		{Query: queries.Query{ID: "c", Category: "INFO"}, Result: neo4jrunner.ResultSet{Columns: []string{"computer", "user"}, Rows: [][]any{{"pc1", "amy"}}}},
+		{Query: queries.Query{ID: "d", Category: "AD"}, Error: neo4jrunner.ErrNotRun.Error(), NotRun: true},
+	}
+	for _, o := range outs {
+		if err := u.Add(o); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var buf bytes.Buffer
+	if err := u.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	k := func(id string, cols []string, row ...any) string { return RowKey(id, cols, row) }
+	want := strings.Join([]string{
+		"query_id,query_title,category,status,row_key,computer,user",
+		"a,,AD,ok," + k("a", []string{"user"}, "bob") + ",,bob",
+		"a,,AD,ok," + k("a", []string{"user"}, "x,y") + `,,"x,y"`,
+		"b,,AD,skipped,,,",
+		"c,,INFO,ok," + k("c", []string{"computer", "user"}, "pc1", "amy") + ",pc1,amy",
+		"d,,AD,not_run,,,",
+		"",
+	}, "\n")
+	if buf.String() != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestUnionCSVEncryptedSpool(t *testing.T) {
+	u, err := NewUnionCSV(Opts{XLSXPassword: "pw"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer u.Close()
+	o := Output{Query: queries.Query{ID: "a", Category: "AD"}, Result: neo4jrunner.ResultSet{Columns: []string{"user"}, Rows: [][]any{{"bob"}}}}
+	if err := u.Add(o); err != nil {
+		t.Fatal(err)
+	}
+	u.sw.Flush()
+	if err := u.buf.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := os.ReadFile(u.spool.Name()); err != nil || bytes.Contains(b, []byte("bob")) {
+		t.Fatalf("spool holds plaintext rows (err=%v)", err)
+	}
+	var buf bytes.Buffer
+	if err := u.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if want := "a,,AD,ok," + RowKey("a", []string{"user"}, []any{"bob"}) + ",bob\n"; !strings.HasSuffix(buf.String(), want) {
+		t.Fatalf("got:\n%s\nwant suffix:\n%s", buf.String(), want)
+	}
+}
+
+func TestRowKeyStable(t *testing.T) {
+	a := RowKey("q", []string{"user", "computer", "pwdlastset"}, []any{"Bob@Corp.local", "PC1", int64(1)})
+	b := RowKey("q", []string{"computer", "user", "pwdlastset"}, []any{"pc1 ", "bob@corp.local", int64(2)})
+	if a != b {
+		t.Fatalf("row key should ignore column order, case and volatile columns: %s != %s", a, b)
+	}
+	if a == RowKey("other", []string{"user", "computer"}, []any{"bob@corp.local", "pc1"}) {
+		t.Fatalf("row key should include the query id")
+	}
+}
+
+func TestUnionCSVColumns(t *testing.T) {
+	u, err := NewUnionCSV(Opts{Columns: ParseColumns("user, query_id,missing")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer u.Close()
+	o := Output{Query: queries.Query{ID: "c"}, Result: neo4jrunner.ResultSet{Columns: []string{"computer", "user"}, Rows: [][]any{{"pc1", "amy"}}}}
+	if err := u.Add(o); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := u.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if want := "user,query_id\namy,c\n"; buf.String() != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
@@ -0,0 +1,134 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// maxSheetLinks stays below Excel's limit of 65,530 hyperlinks per worksheet.
+const maxSheetLinks = 65000
+
+// xlsxStyles holds the style IDs shared by every sheet of a workbook.
+type xlsxStyles struct {
+	header int
+	date   int
+	wrap   int
+	link   int
+	pass   int
+	alert  int
+	// Conditional (dxf) styles keyed by cell text.
+	status   map[string]int
+	severity map[string]int
+}
+
+// Fill colors for status and severity highlighting.
+var (
+	statusFills   = map[string]string{"ok": "C6EFCE", "empty": "EDEDED", "skipped": "FFEB9C", "error": "FFC7CE", "not run": "D9D9D9"}
+	severityFills = map[string]string{
+		"critical": "C00000",
+		"high":     "FF7C80",
+		"medium":   "FFD966",
+		"low":      "9BC2E6",
+		"info":     "D9D9D9",
+	}
+)
+
+func newXLSXStyles(f *excelize.File) (xlsxStyles, error) {
+	var s xlsxStyles
+	var err error
+	s.header, err = f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true, Color: "FFFFFF"},
+		Fill:      excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"1F4E78"}},
+		Alignment: &excelize.Alignment{Vertical: "center"},
+		Border: []excelize.Border{
+			{Type: "bottom", Color: "000000", Style: 1},
+		},
+	})
+	if err != nil {
+		return s, err
+	}
+	dateFmt := "yyyy-mm-dd hh:mm:ss"
+	if s.date, err = f.NewStyle(&excelize.Style{CustomNumFmt: &dateFmt}); err != nil {
+		return s, err
+	}
+	if s.wrap, err = f.NewStyle(&excelize.Style{Alignment: &excelize.Alignment{WrapText: true, Vertical: "top"}}); err != nil {
+		return s, err
+	}
+	if s.link, err = f.NewStyle(&excelize.Style{Font: &excelize.Font{Color: "0563C1", Underline: "single"}}); err != nil {
+		return s, err
+	}
+	if s.pass, err = f.NewStyle(&excelize.Style{Font: &excelize.Font{Color: "006100"}, Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{statusFills["ok"]}}}); err != nil {
+		return s, err
+	}
+	if s.alert, err = f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true, Color: "9C0006"}, Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{statusFills["error"]}}}); err != nil {
+		return s, err
+	}
+	if s.status, err = conditionalFills(f, statusFills); err != nil {
+		return s, err
+	}
+	s.severity, err = conditionalFills(f, severityFills)
+	return s, err
+}
+
+func conditionalFills(f *excelize.File, fills map[string]string) (map[string]int, error) {
+	out := make(map[string]int, len(fills))
+	for k, color := range fills {
+		font := "000000"
+		if k == "critical" {
+			font = "FFFFFF"
+		}
+		id, err := f.NewConditionalStyle(&excelize.Style{
+			Font: &excelize.Font{Color: font},
+			Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{color}},
+		})
+		if err != nil {
+			return nil, err
+		}
+		out[k] = id
+	}
+	return out, nil
+}
+
+// highlightValues adds one conditional format per styled value over rangeRef,
+// so the colors follow the cell text even after a reviewer edits it.
+func highlightValues(f *excelize.File, sheet, rangeRef string, styles map[string]int) {
+	opts := make([]excelize.ConditionalFormatOptions, 0, len(styles))
+	for v, id := range styles {
+		id := id
+		opts = append(opts, excelize.ConditionalFormatOptions{Type: "cell", Criteria: "==", Value: `"` + v + `"`, Format: &id})
+	}
+	_ = f.SetConditionalFormat(sheet, rangeRef, opts)
+}
+
+// severityTab colors a sheet tab by severity.
+func severityTab(f *excelize.File, sheet, severity string) {
+	if c, ok := severityFills[strings.ToLower(severity)]; ok {
+		_ = f.SetSheetProps(sheet, &excelize.SheetPropsOptions{TabColorRGB: &c})
+	}
+}
+
+// styleTable formats a result table whose header sits on headerRow: the header
+// is bolded and filled, the rows above the data are frozen, and an AutoFilter
+// covers the header plus lastRow data rows.
+func styleTable(f *excelize.File, sheet string, st xlsxStyles, headerRow, lastRow, ncols int) {
+	if ncols <= 0 {
+		return
+	}
+	_ = f.SetCellStyle(sheet, cell(1, headerRow), cell(ncols, headerRow), st.header)
+	_ = f.SetPanes(sheet, &excelize.Panes{
+		Freeze:      true,
+		YSplit:      headerRow,
+		TopLeftCell: cell(1, headerRow+1),
+		ActivePane:  "bottomLeft",
+		Selection: []excelize.Selection{{
+			SQRef:      fmt.Sprintf("%s:%s", cell(1, headerRow+1), cell(1, headerRow+1)),
+			ActiveCell: cell(1, headerRow+1),
+			Pane:       "bottomLeft",
+		}},
+	})
+	if lastRow > headerRow {
+		_ = f.AutoFilter(sheet, cell(1, headerRow)+":"+cell(ncols, lastRow), nil)
+	}
+}
@@ -0,0 +1,159 @@
+// Package rules recalculates finding severity per result row from a
+// user-supplied rules file, so context (e.g. a DMZ host or a disabled account)
+// can raise or lower the static per-query severity.
+//
+//	rules:
+//	  - query: ad-computers-unconstrained-delegation
+//	    when:
+//	      computer: {matches: "(?i)dmz"}
+//	    severity: critical
+//	  - query: ad-kerberoast*
+//	    when:
+//	      enabled: {equals: "false"}
+//	    severity: info
+//
+// Rules are evaluated in file order; the first rule whose query pattern and
+// all column conditions match decides the row's severity.
+package rules
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/bakw00ds/goBloodyEll/internal/format"
+	"github.com/bakw00ds/goBloodyEll/internal/queries"
+)
+
+type Set struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+type Rule struct {
+	Query    string               `yaml:"query"` // query ID or glob; empty matches all
+	When     map[string]Condition `yaml:"when"`  // result column -> condition
+	Severity string               `yaml:"severity"`
+}
+
+type Condition struct {
+	Equals   *string  `yaml:"equals"`
+	In       []string `yaml:"in"`
+	Contains string   `yaml:"contains"`
+	Matches  string   `yaml:"matches"`
+
+	re *regexp.Regexp
+}
+
+// Load reads and validates a rules file.
+func Load(p string) (*Set, error) {
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	var s Set
+	if err := yaml.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", p, err)
+	}
+	for i := range s.Rules {
+		r := &s.Rules[i]
+		r.Severity = strings.ToLower(strings.TrimSpace(r.Severity))
+		if queries.SeverityRank(r.Severity) > queries.SeverityRank("info") {
+			return nil, fmt.Errorf("rule %d: invalid severity %q (expected: critical|high|medium|low|info)", i+1, r.Severity)
+		}
+		if _, err := path.Match(r.Query, ""); err != nil {
+			return nil, fmt.Errorf("rule %d: invalid query pattern %q", i+1, r.Query)
+		}
+		for col, c := range r.When {
+			if c.Matches != "" {
+				re, err := regexp.Compile(c.Matches)
+				if err != nil {
+					return nil, fmt.Errorf("rule %d: column %s: %w", i+1, col, err)
+				}
+				c.re = re
+				r.When[col] = c
+			}
+		}
+	}
+	return &s, nil
+}
+
+// Evaluate returns the severity of each row plus the most severe of them
+// (base when there are no rows).
+func (s *Set) Evaluate(queryID, base string, cols []string, rows [][]any) ([]string, string) {
+	if s == nil || len(rows) == 0 {
+		return nil, base
+	}
+	fmtter := format.New()
+	idx := make(map[string]int, len(cols))
+	for i, c := range cols {
+		idx[strings.ToLower(c)] = i
+	}
+	applicable := make([]Rule, 0, len(s.Rules))
+	for _, r := range s.Rules {
+		if ok, _ := path.Match(r.Query, queryID); r.Query == "" || ok {
+			applicable = append(applicable, r)
+		}
+	}
+	if len(applicable) == 0 {
+		return nil, base
+	}
+
+	out := make([]string, len(rows))
+	top := ""
+	for i, row := range rows {
+		sev := base
+		for _, r := range applicable {
+			if r.matches(idx, row, fmtter) {
+				sev = r.Severity
+				break
+			}
+		}
+		out[i] = sev
+		if top == "" || queries.SeverityRank(sev) < queries.SeverityRank(top) {
+			top = sev
+		}
+	}
+	return out, top
+}
+
+func (r Rule) matches(idx map[string]int, row []any, fmtter *format.Formatter) bool {
+	for col, c := range r.When {
+		i, ok := idx[strings.ToLower(col)]
+		if !ok || i >= len(row) {
+			return false
+		}
+		if !c.test(fmtter.Value(col, row[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c Condition) test(v string) bool {
+	if c.Equals != nil && !strings.EqualFold(v, *c.Equals) {
+		return false
+	}
+	if len(c.In) > 0 {
+		found := false
+		for _, x := range c.In {
+			if strings.EqualFold(v, x) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if c.Contains != "" && !strings.Contains(strings.ToLower(v), strings.ToLower(c.Contains)) {
+		return false
+	}
+	if c.re != nil && !c.re.MatchString(v) {
+		return false
+	}
+	return true
+}
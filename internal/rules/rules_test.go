@@ -0,0 +1,43 @@
+package rules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "rules.yaml")
+	doc := `rules:
+  - query: ad-*-unconstrained-delegation
+    when:
+      computer: {matches: "(?i)dmz"}
+    severity: critical
+  - query: ad-kerberoastable
+    when:
+      enabled: {equals: "false"}
+    severity: info
+`
+	if err := os.WriteFile(p, []byte(doc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Load(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, top := s.Evaluate("ad-computers-unconstrained-delegation", "medium",
+		[]string{"computer", "os"}, [][]any{{"web01.corp", "2019"}, {"DMZ-APP.corp", "2016"}})
+	if rows[0] != "medium" || rows[1] != "critical" || top != "critical" {
+		t.Fatalf("got rows=%v top=%s", rows, top)
+	}
+
+	rows, top = s.Evaluate("ad-kerberoastable", "medium", []string{"user", "enabled"}, [][]any{{"svc", false}})
+	if rows[0] != "info" || top != "info" {
+		t.Fatalf("got rows=%v top=%s", rows, top)
+	}
+
+	if rows, top := s.Evaluate("ad-dcsync-rights", "critical", []string{"principal"}, [][]any{{"x"}}); rows != nil || top != "critical" {
+		t.Fatalf("unmatched query should keep base severity, got rows=%v top=%s", rows, top)
+	}
+}
@@ -0,0 +1,58 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/bakw00ds/goBloodyEll/internal/queries"
+)
+
+// Snapshot is what a run learns about the graph before its queries: the
+// labels and relationship types, the newest data, the Tier Zero convention
+// and probed property presence. --schema-cache and --schema-from keep it on
+// disk so later runs can skip the discovery queries.
+type Snapshot struct {
+	// Key identifies the database the snapshot was taken from (URI|db).
+	Key        string                     `json:"key"`
+	Taken      time.Time                  `json:"taken"`
+	Summary    Summary                    `json:"summary"`
+	Newest     time.Time                  `json:"newest_data,omitempty"`
+	SystemTags bool                       `json:"system_tags,omitempty"`
+	Props      map[string]map[string]bool `json:"properties,omitempty"`
+}
+
+// TierZero is the Tier Zero convention recorded in the snapshot.
+func (s Snapshot) TierZero() queries.TierZero {
+	if s.SystemTags {
+		return queries.SystemTags
+	}
+	return queries.HighValue
+}
+
+// LoadSnapshot reads a snapshot written by SaveSnapshot.
+func LoadSnapshot(path string) (Snapshot, error) {
+	var s Snapshot
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(b, &s); err != nil {
+		return s, fmt.Errorf("%s: %v", path, err)
+	}
+	return s, nil
+}
+
+// SaveSnapshot writes s to path, replacing it atomically.
+func SaveSnapshot(path string, s Snapshot) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(b, '\n'), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
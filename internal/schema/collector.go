@@ -0,0 +1,262 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// Collection summarizes what produced the data and which edge families look absent.
+type Collection struct {
+	Collectors []string
+	Warnings   []string
+	// Incomplete maps a lower-case relationship type to the reason it is considered missing.
+	Incomplete map[string]string
+}
+
+// edgeFamily groups relationship types that a given collection method produces.
+type edgeFamily struct {
+	name   string
+	rels   []string
+	reason string
+}
+
+var edgeFamilies = []edgeFamily{
+	{"sessions", []string{"HasSession"}, "no HasSession edges (session collection missing; DCOnly or stealth collection?)"},
+	{"local admin", []string{"AdminTo", "CanRDP", "CanPSRemote", "ExecuteDCOM"}, "no local group edges (AdminTo/CanRDP/...; LocalGroup collection missing)"},
+	{"acl", []string{"GenericAll", "GenericWrite", "WriteDacl", "WriteOwner", "Owns", "AllExtendedRights", "ForceChangePassword", "AddMember"}, "no ACL edges (ACL collection missing)"},
+	{"group membership", []string{"MemberOf"}, "no MemberOf edges (Group collection missing)"},
+}
+
+// CollectorMeta is the collector metadata recorded on one Meta or Domain node.
+type CollectorMeta struct {
+	Label   string `json:"label"`
+	Name    string `json:"name,omitempty"`
+	Version string `json:"version,omitempty"`
+	// Format is the collection file format version (meta.version: 4 and 5
+	// for legacy BloodHound, 6 for BloodHound CE), 0 when unknown.
+	Format int `json:"format,omitempty"`
+}
+
+// Property names collectors and importers use for the collector's name and
+// version, in order of preference.
+var (
+	metaNameKeys    = []string{"collector", "collectorname", "collected_by", "collectedby"}
+	metaVersionKeys = []string{"collectorversion", "collector_version", "sharphoundversion", "rusthoundversion"}
+)
+
+// knownCollectors maps a lower-cased name prefix to its display name; longer
+// prefixes come first so RustHound-CE is not reported as RustHound.
+var knownCollectors = []struct{ prefix, name string }{
+	{"rusthound-ce", "RustHound-CE"},
+	{"rusthound_ce", "RustHound-CE"},
+	{"rusthound", "RustHound"},
+	{"sharphound", "SharpHound"},
+	{"bloodhound-ce-python", "bloodhound.py"},
+	{"bloodhound-python", "bloodhound.py"},
+	{"bloodhound.py", "bloodhound.py"},
+	{"azurehound", "AzureHound"},
+}
+
+// reNameVersion splits a name that carries its version, e.g. "RustHound-CE v2.1.0".
+var reNameVersion = regexp.MustCompile(`^(.*?)[\s_-]+v?(\d+(?:\.\d+)+\S*)$`)
+
+// collectorMeta reads collector metadata from the Meta and Domain nodes; it
+// is best effort, so errors leave it empty.
+func collectorMeta(ctx context.Context, sess neo4j.SessionWithContext, s Summary) []CollectorMeta {
+	if !slices.Contains(s.Labels, "Meta") && !slices.Contains(s.Labels, "Domain") {
+		return nil
+	}
+	res, err := sess.Run(ctx, "MATCH (n) WHERE n:Meta OR n:Domain RETURN labels(n), properties(n) LIMIT 100", nil)
+	if err != nil {
+		return nil
+	}
+	var out []CollectorMeta
+	for res.Next(ctx) {
+		rec := res.Record()
+		var labels []string
+		ls, _ := rec.Values[0].([]any)
+		for _, l := range ls {
+			labels = append(labels, fmt.Sprint(l))
+		}
+		props, _ := rec.Values[1].(map[string]any)
+		if m, ok := parseCollectorMeta(labels, props); ok {
+			out = append(out, m)
+		}
+	}
+	if res.Err() != nil {
+		return nil
+	}
+	return out
+}
+
+// parseCollectorMeta extracts collector metadata from a Meta or Domain
+// node's labels and properties. Collectors record it differently: a name
+// may carry its version ("RustHound-CE v2.1.0"), and a version without a
+// name is SharpHound's collectorversion. The format version is only read
+// from Meta nodes, since "version" means nothing in particular on a Domain.
+func parseCollectorMeta(labels []string, props map[string]any) (CollectorMeta, bool) {
+	m := CollectorMeta{Label: "Domain"}
+	isMeta := slices.Contains(labels, "Meta")
+	if isMeta {
+		m.Label = "Meta"
+	}
+	get := func(keys []string) string {
+		for _, k := range keys {
+			for pk, v := range props {
+				if strings.EqualFold(pk, k) && v != nil {
+					if s := strings.TrimSpace(fmt.Sprint(v)); s != "" {
+						return s
+					}
+				}
+			}
+		}
+		return ""
+	}
+	m.Name, m.Version = get(metaNameKeys), get(metaVersionKeys)
+	if sm := reNameVersion.FindStringSubmatch(m.Name); sm != nil {
+		m.Name = sm[1]
+		if m.Version == "" {
+			m.Version = sm[2]
+		}
+	}
+	if m.Name == "" && m.Version != "" {
+		m.Name = "SharpHound"
+	}
+	for _, k := range knownCollectors {
+		if strings.HasPrefix(strings.ToLower(m.Name), k.prefix) {
+			m.Name = k.name
+			break
+		}
+	}
+	if isMeta {
+		switch v := props["version"].(type) {
+		case int64:
+			m.Format = int(v)
+		case float64:
+			m.Format = int(v)
+		case string:
+			m.Format, _ = strconv.Atoi(strings.TrimSpace(v))
+		}
+	}
+	return m, m.Name != "" || m.Format > 0
+}
+
+// namedCollectors lists the distinct "name version" entries of meta.
+func namedCollectors(meta []CollectorMeta) []string {
+	var out []string
+	for _, m := range meta {
+		if m.Name == "" {
+			continue
+		}
+		if n := strings.TrimSpace(m.Name + " " + m.Version); !slices.Contains(out, n) {
+			out = append(out, n)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// AssessCollection infers the collector(s) from the collector metadata when
+// the graph has any, otherwise from labels, and flags edge families that are
+// entirely absent from the database.
+func AssessCollection(s Summary) Collection {
+	labels := map[string]bool{}
+	for _, l := range s.Labels {
+		labels[strings.ToLower(l)] = true
+	}
+	rels := map[string]bool{}
+	for _, r := range s.Rels {
+		rels[strings.ToLower(r)] = true
+	}
+
+	c := Collection{Incomplete: map[string]string{}}
+	hasAD := labels["user"] || labels["computer"] || labels["domain"]
+	hasAzure := false
+	for l := range labels {
+		if strings.HasPrefix(l, "az") || strings.HasPrefix(l, "azure") {
+			hasAzure = true
+			break
+		}
+	}
+	format := 0
+	for _, m := range s.Meta {
+		format = max(format, m.Format)
+	}
+	named := namedCollectors(s.Meta)
+	switch {
+	case len(named) > 0:
+		c.Collectors = append(c.Collectors, named...)
+	case hasAD && (labels["base"] || format >= 6):
+		c.Collectors = append(c.Collectors, "SharpHound CE / RustHound-CE (BloodHound CE ingest)")
+	case hasAD:
+		c.Collectors = append(c.Collectors, "SharpHound / RustHound / bloodhound.py (legacy BloodHound ingest)")
+	}
+	if format > 0 && len(named) == 0 && hasAD {
+		c.Collectors[len(c.Collectors)-1] += fmt.Sprintf(", collection format v%d", format)
+	}
+	if hasAzure && !slices.ContainsFunc(named, func(n string) bool { return strings.HasPrefix(n, "AzureHound") }) {
+		c.Collectors = append(c.Collectors, "AzureHound")
+	}
+	if len(c.Collectors) == 0 {
+		c.Collectors = append(c.Collectors, "unknown")
+	}
+	if !hasAD {
+		return c
+	}
+
+	for _, fam := range edgeFamilies {
+		present := false
+		for _, r := range fam.rels {
+			if rels[strings.ToLower(r)] {
+				present = true
+				break
+			}
+		}
+		if present {
+			continue
+		}
+		c.Warnings = append(c.Warnings, fam.reason)
+		for _, r := range fam.rels {
+			c.Incomplete[strings.ToLower(r)] = fam.reason
+		}
+	}
+	return c
+}
+
+// NoteFor returns a "data likely incomplete" annotation when the Cypher relies
+// on an edge family that is missing from the collection.
+func (c Collection) NoteFor(cypher string) string {
+	if len(c.Incomplete) == 0 {
+		return ""
+	}
+	reasons := map[string]struct{}{}
+	for _, r := range RelTypes(cypher) {
+		if why, ok := c.Incomplete[strings.ToLower(r)]; ok {
+			reasons[why] = struct{}{}
+		}
+	}
+	if len(reasons) == 0 {
+		return ""
+	}
+	out := make([]string, 0, len(reasons))
+	for r := range reasons {
+		out = append(out, r)
+	}
+	sort.Strings(out)
+	return fmt.Sprintf("data likely incomplete: %s", strings.Join(out, "; "))
+}
+
+// Print writes the collector guess and warnings for --schema.
+func (c Collection) Print() {
+	fmt.Printf("Collector(s): %s\n", strings.Join(c.Collectors, ", "))
+	for _, w := range c.Warnings {
+		fmt.Printf("warning: %s\n", w)
+	}
+}
@@ -0,0 +1,98 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAssessCollectionDCOnly(t *testing.T) {
+	c := AssessCollection(Summary{
+		Labels: []string{"Base", "Computer", "Domain", "Group", "User"},
+		Rels:   []string{"MemberOf", "GenericAll", "Contains"},
+	})
+	if len(c.Collectors) != 1 || !strings.Contains(c.Collectors[0], "CE") {
+		t.Fatalf("unexpected collectors: %v", c.Collectors)
+	}
+	if len(c.Warnings) != 2 {
+		t.Fatalf("want sessions + local admin warnings, got %v", c.Warnings)
+	}
+	if n := c.NoteFor("MATCH (c:Computer)-[:HasSession]->(u:User) RETURN u"); !strings.Contains(n, "HasSession") {
+		t.Fatalf("missing session note: %q", n)
+	}
+	if n := c.NoteFor("MATCH (u:User)-[a:Owns|GenericAll]->(g:GPO) RETURN u"); n != "" {
+		t.Fatalf("ACL edges present, want no note, got %q", n)
+	}
+}
+
+func TestAssessCollectionMetadata(t *testing.T) {
+	ad := []string{"Computer", "Domain", "Group", "User"}
+	cases := []struct {
+		name   string
+		labels []string
+		nodes  []map[string]any // properties of Meta (with "meta") or Domain nodes
+		want   []string
+	}{
+		{
+			name:   "SharpHound CE meta node",
+			labels: append([]string{"Base", "Meta"}, ad...),
+			nodes:  []map[string]any{{"meta": true, "collectorversion": "2.4.1.0", "version": int64(6)}},
+			want:   []string{"SharpHound 2.4.1.0"},
+		},
+		{
+			name:   "RustHound-CE name carrying its version",
+			labels: append([]string{"Base"}, ad...),
+			nodes:  []map[string]any{{"name": "CORP.LOCAL", "collector": "rusthound-ce v2.1.0"}},
+			want:   []string{"RustHound-CE 2.1.0"},
+		},
+		{
+			name:   "legacy RustHound with separate version",
+			labels: ad,
+			nodes:  []map[string]any{{"collector": "RustHound", "collector_version": "1.1.69"}},
+			want:   []string{"RustHound 1.1.69"},
+		},
+		{
+			name:   "bloodhound.py",
+			labels: ad,
+			nodes:  []map[string]any{{"CollectedBy": "bloodhound-python", "collectorVersion": "1.7.2"}},
+			want:   []string{"bloodhound.py 1.7.2"},
+		},
+		{
+			name:   "two domains, two collectors, plus AzureHound data",
+			labels: append([]string{"AZUser"}, ad...),
+			nodes: []map[string]any{
+				{"collector": "SharpHound", "collectorversion": "1.1.0"},
+				{"collector": "SharpHound", "collectorversion": "1.1.0"},
+				{"collector": "RustHound 1.1.69"},
+			},
+			want: []string{"RustHound 1.1.69", "SharpHound 1.1.0", "AzureHound"},
+		},
+		{
+			name:   "legacy format version only",
+			labels: append([]string{"Meta"}, ad...),
+			nodes:  []map[string]any{{"meta": true, "version": int64(5)}},
+			want:   []string{"SharpHound / RustHound / bloodhound.py (legacy BloodHound ingest), collection format v5"},
+		},
+		{
+			name:   "no metadata",
+			labels: ad,
+			nodes:  []map[string]any{{"name": "CORP.LOCAL", "functionallevel": "2016"}},
+			want:   []string{"SharpHound / RustHound / bloodhound.py (legacy BloodHound ingest)"},
+		},
+	}
+	for _, c := range cases {
+		s := Summary{Labels: c.labels, Rels: []string{"MemberOf"}}
+		for _, props := range c.nodes {
+			labels := []string{"Domain"}
+			if props["meta"] == true {
+				labels = []string{"Meta"}
+			}
+			if m, ok := parseCollectorMeta(labels, props); ok {
+				s.Meta = append(s.Meta, m)
+			}
+		}
+		got := AssessCollection(s).Collectors
+		if strings.Join(got, "|") != strings.Join(c.want, "|") {
+			t.Errorf("%s: collectors = %q, want %q", c.name, got, c.want)
+		}
+	}
+}
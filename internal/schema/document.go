@@ -0,0 +1,75 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// PropertySample is how many nodes per label SampleProperties looks at.
+const PropertySample = 1000
+
+// Document is the machine-readable --schema output.
+type Document struct {
+	Server        Server      `json:"server" yaml:"server"`
+	APOC          string      `json:"apoc,omitempty" yaml:"apoc,omitempty"`
+	Labels        []LabelInfo `json:"labels" yaml:"labels"`
+	Relationships []RelInfo   `json:"relationships" yaml:"relationships"`
+	Collectors    []string    `json:"collectors,omitempty" yaml:"collectors,omitempty"`
+	Warnings      []string    `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+}
+
+// LabelInfo is a node label with its node count and the properties seen on
+// a sample of its nodes, with anonymized example values if requested (see
+// SampleValues).
+type LabelInfo struct {
+	Name       string              `json:"name" yaml:"name"`
+	Count      int64               `json:"count" yaml:"count"`
+	Properties []string            `json:"properties" yaml:"properties"`
+	Examples   map[string][]string `json:"examples,omitempty" yaml:"examples,omitempty"`
+}
+
+// RelInfo is a relationship type with its relationship count.
+type RelInfo struct {
+	Type  string `json:"type" yaml:"type"`
+	Count int64  `json:"count" yaml:"count"`
+}
+
+// NewDocument assembles the --schema document; props maps labels to their
+// sampled property names (see SampleProperties).
+func NewDocument(s Summary, server Server, props map[string][]string, c Collection) Document {
+	d := Document{Server: server, APOC: s.APOC, Collectors: c.Collectors, Warnings: c.Warnings,
+		Labels: make([]LabelInfo, 0, len(s.Labels)), Relationships: make([]RelInfo, 0, len(s.Rels))}
+	for _, l := range s.Labels {
+		d.Labels = append(d.Labels, LabelInfo{Name: l, Count: s.LabelCounts[l], Properties: append([]string{}, props[l]...)})
+	}
+	for _, r := range s.Rels {
+		d.Relationships = append(d.Relationships, RelInfo{Type: r, Count: s.RelCounts[r]})
+	}
+	return d
+}
+
+// WithExamples adds SampleValues output to the labels of d.
+func (d Document) WithExamples(values map[string]map[string][]string) Document {
+	for i, l := range d.Labels {
+		d.Labels[i].Examples = values[l.Name]
+	}
+	return d
+}
+
+// SampleProperties returns, per label, the sorted property names found on
+// up to n of its nodes.
+func SampleProperties(ctx context.Context, sess neo4j.SessionWithContext, labels []string, n int) (map[string][]string, error) {
+	out := make(map[string][]string, len(labels))
+	for _, l := range labels {
+		keys, err := list(ctx, sess, fmt.Sprintf("MATCH (n:%s) WITH n LIMIT %d UNWIND keys(n) AS k RETURN DISTINCT k", quoteName(l), n))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(keys)
+		out[l] = keys
+	}
+	return out, nil
+}
@@ -5,16 +5,68 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+
+	"github.com/bakw00ds/goBloodyEll/internal/queries"
 )
 
 type Summary struct {
-	Labels []string
-	Rels   []string
+	Labels []string `json:"labels"`
+	Rels   []string `json:"relationships"`
+	// LabelCounts and RelCounts are the nodes per label and relationships
+	// per type.
+	LabelCounts map[string]int64 `json:"label_counts,omitempty"`
+	RelCounts   map[string]int64 `json:"relationship_counts,omitempty"`
+	// APOC is the version of the APOC plugin, "" when it isn't installed.
+	APOC string `json:"apoc,omitempty"`
+	// Meta is the collector metadata found on Meta and Domain nodes.
+	Meta []CollectorMeta `json:"collector_meta,omitempty"`
+}
+
+// Discover lists the labels and relationship types in the database with
+// their counts, through apoc.meta.stats when APOC is installed, and reads
+// the collector metadata.
+func Discover(ctx context.Context, sess neo4j.SessionWithContext, d queries.Dialect) (Summary, error) {
+	s, err := discoverCounts(ctx, sess, d)
+	if err != nil {
+		return s, err
+	}
+	s.Meta = collectorMeta(ctx, sess, s)
+	return s, nil
 }
 
-func Discover(ctx context.Context, sess neo4j.SessionWithContext) (Summary, error) {
+func discoverCounts(ctx context.Context, sess neo4j.SessionWithContext, d queries.Dialect) (Summary, error) {
+	if d != queries.Memgraph {
+		if v := apocVersion(ctx, sess); v != "" {
+			if s, err := metaStats(ctx, sess); err == nil {
+				s.APOC = v
+				return s, nil
+			}
+			sum, err := discover(ctx, sess, d)
+			sum.APOC = v
+			return sum, err
+		}
+	}
+	return discover(ctx, sess, d)
+}
+
+func discover(ctx context.Context, sess neo4j.SessionWithContext, d queries.Dialect) (Summary, error) {
+	if d == queries.Memgraph {
+		// Memgraph has no db.labels()/db.relationshipTypes() or count store,
+		// so one scan each lists and counts.
+		lc, err := counts(ctx, sess, "MATCH (n) UNWIND labels(n) AS label RETURN label, count(*)")
+		if err != nil {
+			return Summary{}, err
+		}
+		rc, err := counts(ctx, sess, "MATCH ()-[r]->() RETURN type(r), count(*)")
+		if err != nil {
+			return Summary{}, err
+		}
+		return summaryFromCounts(lc, rc), nil
+	}
 	labels, err := list(ctx, sess, "CALL db.labels() YIELD label RETURN label")
 	if err != nil {
 		return Summary{}, err
@@ -23,15 +75,106 @@ func Discover(ctx context.Context, sess neo4j.SessionWithContext) (Summary, erro
 	if err != nil {
 		return Summary{}, err
 	}
-	sort.Strings(labels)
-	sort.Strings(rels)
-	return Summary{Labels: labels, Rels: rels}, nil
+	// Counts by a single label or type come from the count store.
+	lc := make(map[string]int64, len(labels))
+	for _, l := range labels {
+		if lc[l], err = CountLabel(ctx, sess, l); err != nil {
+			return Summary{}, err
+		}
+	}
+	rc := make(map[string]int64, len(rels))
+	for _, r := range rels {
+		if rc[r], err = count(ctx, sess, fmt.Sprintf("MATCH ()-[r:%s]->() RETURN count(r)", quoteName(r))); err != nil {
+			return Summary{}, err
+		}
+	}
+	return summaryFromCounts(lc, rc), nil
+}
+
+// summaryFromCounts builds a Summary listing the keys of lc and rc.
+func summaryFromCounts(lc, rc map[string]int64) Summary {
+	s := Summary{LabelCounts: lc, RelCounts: rc}
+	for l := range lc {
+		s.Labels = append(s.Labels, l)
+	}
+	for r := range rc {
+		s.Rels = append(s.Rels, r)
+	}
+	sort.Strings(s.Labels)
+	sort.Strings(s.Rels)
+	return s
+}
+
+// counts reads name/count pairs from cypher's first two columns.
+func counts(ctx context.Context, sess neo4j.SessionWithContext, cypher string) (map[string]int64, error) {
+	res, err := sess.Run(ctx, cypher, nil)
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]int64{}
+	for res.Next(ctx) {
+		rec := res.Record()
+		n, _ := rec.Values[1].(int64)
+		out[fmt.Sprint(rec.Values[0])] = n
+	}
+	return out, res.Err()
+}
+
+// apocVersion returns the installed APOC version, "" when apoc.version()
+// does not exist.
+func apocVersion(ctx context.Context, sess neo4j.SessionWithContext) string {
+	res, err := sess.Run(ctx, "RETURN apoc.version() AS version", nil)
+	if err != nil {
+		return ""
+	}
+	rec, err := res.Single(ctx)
+	if err != nil || rec.Values[0] == nil {
+		return ""
+	}
+	return fmt.Sprint(rec.Values[0])
+}
+
+// metaStats reads the labels and relationship types with their counts from
+// apoc.meta.stats, which serves them from the count store in one call.
+func metaStats(ctx context.Context, sess neo4j.SessionWithContext) (Summary, error) {
+	res, err := sess.Run(ctx, "CALL apoc.meta.stats() YIELD labels, relTypesCount RETURN labels, relTypesCount", nil)
+	if err != nil {
+		return Summary{}, err
+	}
+	rec, err := res.Single(ctx)
+	if err != nil {
+		return Summary{}, err
+	}
+	toCounts := func(v any) map[string]int64 {
+		m, _ := v.(map[string]any)
+		out := make(map[string]int64, len(m))
+		for k, n := range m {
+			out[k], _ = n.(int64)
+		}
+		return out
+	}
+	return summaryFromCounts(toCounts(rec.Values[0]), toCounts(rec.Values[1])), nil
 }
 
 func Print(summary Summary) {
 	fmt.Println("== Neo4j schema summary ==")
-	fmt.Printf("Node labels (%d): %s\n", len(summary.Labels), strings.Join(summary.Labels, ", "))
-	fmt.Printf("Relationship types (%d): %s\n", len(summary.Rels), strings.Join(summary.Rels, ", "))
+	fmt.Printf("Node labels (%d): %s\n", len(summary.Labels), withCounts(summary.Labels, summary.LabelCounts))
+	fmt.Printf("Relationship types (%d): %s\n", len(summary.Rels), withCounts(summary.Rels, summary.RelCounts))
+	if summary.APOC != "" {
+		fmt.Printf("APOC: %s\n", summary.APOC)
+	}
+}
+
+// withCounts joins names as "name (count)" where counts has one.
+func withCounts(names []string, counts map[string]int64) string {
+	parts := make([]string, len(names))
+	for i, n := range names {
+		parts[i] = n
+		if c, ok := counts[n]; ok {
+			parts[i] = fmt.Sprintf("%s (%d)", n, c)
+		}
+	}
+	return strings.Join(parts, ", ")
 }
 
 func list(ctx context.Context, sess neo4j.SessionWithContext, cypher string) ([]string, error) {
@@ -53,3 +196,204 @@ func list(ctx context.Context, sess neo4j.SessionWithContext, cypher string) ([]
 	}
 	return out, nil
 }
+
+// Server describes the Neo4j server and the size of the graph.
+type Server struct {
+	Version       string `json:"version,omitempty" yaml:"version,omitempty"`
+	Edition       string `json:"edition,omitempty" yaml:"edition,omitempty"`
+	Nodes         int64  `json:"nodes" yaml:"nodes"`
+	Relationships int64  `json:"relationships" yaml:"relationships"`
+}
+
+// DescribeServer reads the server version/edition and node/relationship
+// counts (served from the count store, so cheap on large graphs).
+func DescribeServer(ctx context.Context, sess neo4j.SessionWithContext, d queries.Dialect) (Server, error) {
+	var s Server
+	cypher := "CALL dbms.components() YIELD name, versions, edition WHERE name = 'Neo4j Kernel' RETURN versions[0] AS version, edition"
+	if d == queries.Memgraph {
+		cypher = "SHOW VERSION"
+		s.Edition = "memgraph"
+	}
+	res, err := sess.Run(ctx, cypher, nil)
+	if err != nil {
+		return s, err
+	}
+	if res.Next(ctx) {
+		rec := res.Record()
+		if v, ok := rec.Get("version"); ok && v != nil {
+			s.Version = fmt.Sprint(v)
+		}
+		if v, ok := rec.Get("edition"); ok && v != nil {
+			s.Edition = fmt.Sprint(v)
+		}
+	}
+	if err := res.Err(); err != nil {
+		return s, err
+	}
+	if s.Nodes, err = count(ctx, sess, "MATCH (n) RETURN count(n)"); err != nil {
+		return s, err
+	}
+	if s.Relationships, err = count(ctx, sess, "MATCH ()-[r]->() RETURN count(r)"); err != nil {
+		return s, err
+	}
+	return s, nil
+}
+
+func count(ctx context.Context, sess neo4j.SessionWithContext, cypher string) (int64, error) {
+	res, err := sess.Run(ctx, cypher, nil)
+	if err != nil {
+		return 0, err
+	}
+	rec, err := res.Single(ctx)
+	if err != nil {
+		return 0, err
+	}
+	n, _ := rec.Values[0].(int64)
+	return n, nil
+}
+
+// quoteName backtick-quotes a label, relationship type or property name
+// taken from the data, doubling any backtick inside it.
+func quoteName(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// CountLabel counts the nodes with label, served from the count store.
+// label must be a plain identifier.
+func CountLabel(ctx context.Context, sess neo4j.SessionWithContext, label string) (int64, error) {
+	return count(ctx, sess, fmt.Sprintf("MATCH (n:%s) RETURN count(n)", quoteName(label)))
+}
+
+// NewestData dates the collection: the latest lastseen (set by BloodHound
+// CE), whencreated or lastlogontimestamp on a User or Computer node. The
+// zero time means none of them is set. Values in the future (bad clocks)
+// are ignored.
+func NewestData(ctx context.Context, sess neo4j.SessionWithContext) (time.Time, error) {
+	var newest time.Time
+	limit := time.Now().Add(24 * time.Hour)
+	for _, l := range []string{"User", "Computer"} {
+		res, err := sess.Run(ctx, fmt.Sprintf("MATCH (n:%s) RETURN max(n.lastseen), max(toInteger(n.whencreated)), max(toInteger(n.lastlogontimestamp))", quoteName(l)), nil)
+		if err != nil {
+			return time.Time{}, err
+		}
+		rec, err := res.Single(ctx)
+		if err != nil {
+			return time.Time{}, err
+		}
+		for _, v := range rec.Values {
+			if t := asTime(v); t.After(newest) && t.Before(limit) {
+				newest = t
+			}
+		}
+	}
+	return newest, nil
+}
+
+// asTime converts a date property (epoch seconds, a temporal value or an
+// RFC 3339 string) to a time; the zero time when it isn't one.
+func asTime(v any) time.Time {
+	switch x := v.(type) {
+	case int64:
+		if x > 0 {
+			return time.Unix(x, 0).UTC()
+		}
+	case time.Time:
+		return x.UTC()
+	case dbtype.LocalDateTime:
+		return time.Time(x).UTC()
+	case string:
+		if t, err := time.Parse(time.RFC3339Nano, x); err == nil {
+			return t.UTC()
+		}
+	}
+	return time.Time{}
+}
+
+// ProbeProps checks, for each ref whose label is in p, whether any node
+// with the label has the property. Each probe stops at the first match, so
+// common properties cost next to nothing; an absent one costs a label scan.
+// Properties already recorded in p.Props are kept without probing again.
+func ProbeProps(ctx context.Context, sess neo4j.SessionWithContext, p Presence, refs []PropRef) (map[string]map[string]bool, error) {
+	out := map[string]map[string]bool{}
+	for label, props := range p.Props {
+		out[label] = map[string]bool{}
+		for prop, found := range props {
+			out[label][prop] = found
+		}
+	}
+	for _, r := range refs {
+		label := strings.ToLower(r.Label)
+		if _, ok := p.Labels[label]; !ok {
+			continue
+		}
+		if _, done := out[label][r.Prop]; done {
+			continue
+		}
+		res, err := sess.Run(ctx, fmt.Sprintf("MATCH (n:%s) WHERE n.%s IS NOT NULL RETURN 1 LIMIT 1", quoteName(r.Label), quoteName(r.Prop)), nil)
+		if err != nil {
+			return nil, err
+		}
+		found := res.Next(ctx)
+		if err := res.Err(); err != nil {
+			return nil, err
+		}
+		if out[label] == nil {
+			out[label] = map[string]bool{}
+		}
+		out[label][r.Prop] = found
+	}
+	return out, nil
+}
+
+// DetectTierZero reports how the graph marks Tier Zero objects: highvalue
+// (legacy BloodHound) unless no node has highvalue = true and some have
+// admin_tier_0 in system_tags (BloodHound CE).
+func DetectTierZero(ctx context.Context, sess neo4j.SessionWithContext) (queries.TierZero, error) {
+	for _, c := range []struct {
+		cypher string
+		tz     queries.TierZero
+	}{
+		{"MATCH (n) WHERE n.highvalue = true RETURN 1 LIMIT 1", queries.HighValue},
+		{"MATCH (n) WHERE n.system_tags CONTAINS 'admin_tier_0' RETURN 1 LIMIT 1", queries.SystemTags},
+	} {
+		res, err := sess.Run(ctx, c.cypher, nil)
+		if err != nil {
+			return queries.HighValue, err
+		}
+		found := res.Next(ctx)
+		if err := res.Err(); err != nil {
+			return queries.HighValue, err
+		}
+		if found {
+			return c.tz, nil
+		}
+	}
+	return queries.HighValue, nil
+}
+
+// Domain is an AD domain node.
+type Domain struct {
+	Name string
+	SID  string
+}
+
+// Domains lists the Domain nodes in the graph.
+func Domains(ctx context.Context, sess neo4j.SessionWithContext) ([]Domain, error) {
+	res, err := sess.Run(ctx, "MATCH (d:Domain) WHERE d.name IS NOT NULL RETURN d.name AS name, d.objectid AS sid ORDER BY name", nil)
+	if err != nil {
+		return nil, err
+	}
+	var out []Domain
+	for res.Next(ctx) {
+		rec := res.Record()
+		d := Domain{}
+		if v, ok := rec.Get("name"); ok && v != nil {
+			d.Name = fmt.Sprint(v)
+		}
+		if v, ok := rec.Get("sid"); ok && v != nil {
+			d.SID = fmt.Sprint(v)
+		}
+		out = append(out, d)
+	}
+	return out, res.Err()
+}
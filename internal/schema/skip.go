@@ -4,16 +4,18 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
-)
 
-var (
-	reLabel = regexp.MustCompile(`:([A-Za-z0-9_]+)`)   // :User
-	reRel   = regexp.MustCompile(`\[:([A-Za-z0-9_]+)`) // [:MemberOf
+	"github.com/bakw00ds/goBloodyEll/internal/queries"
 )
 
+var reRelList = regexp.MustCompile(`\[[A-Za-z0-9_]*:([A-Za-z0-9_|]+)`) // [r:GenericAll|Owns
+
 type Presence struct {
 	Labels map[string]struct{}
 	Rels   map[string]struct{}
+	// Props records, per lower-cased label, whether any node has each probed
+	// property (see ProbeProps); properties not probed are assumed present.
+	Props map[string]map[string]bool
 }
 
 func PresenceFromSummary(s Summary) Presence {
@@ -27,27 +29,103 @@ func PresenceFromSummary(s Summary) Presence {
 	return p
 }
 
-func CanRunCypher(cypher string, p Presence) (bool, string) {
-	// Best-effort: if we reference a label/rel that doesn't exist, skip.
-	labels := reLabel.FindAllStringSubmatch(cypher, -1)
-	for _, m := range labels {
-		l := strings.ToLower(m[1])
-		if l == "" {
-			continue
+// CanRun reports whether the graph has everything q declares it requires
+// (see Query.RequiresLabels), and if not, what is missing.
+func CanRun(q queries.Query, p Presence) (bool, string) {
+	for _, l := range q.RequiresLabels {
+		if _, ok := p.Labels[strings.ToLower(l)]; !ok {
+			return false, fmt.Sprintf("missing label: %s", l)
+		}
+	}
+	for _, r := range q.RequiresRels {
+		if _, ok := p.Rels[strings.ToLower(r)]; !ok {
+			return false, fmt.Sprintf("missing relationship type: %s", r)
+		}
+	}
+	for _, r := range RequiredProps(q) {
+		if present, ok := p.Props[strings.ToLower(r.Label)][r.Prop]; ok && !present {
+			return false, fmt.Sprintf("missing property: no %s node has %s", r.Label, r.Prop)
+		}
+	}
+	return true, ""
+}
+
+// RequiredProps parses q.RequiresProps; entries without a "Label." are
+// ignored.
+func RequiredProps(q queries.Query) []PropRef {
+	var out []PropRef
+	for _, lp := range q.RequiresProps {
+		if label, prop, ok := strings.Cut(lp, "."); ok && label != "" && prop != "" {
+			out = append(out, PropRef{Label: label, Prop: prop})
 		}
-		if _, ok := p.Labels[l]; !ok {
-			return false, fmt.Sprintf("missing label: %s", m[1])
+	}
+	return out
+}
+
+var (
+	reBinding    = regexp.MustCompile(`\(([A-Za-z_][A-Za-z0-9_]*):([A-Za-z0-9_]+)`) // (u:User
+	reStartsEnds = regexp.MustCompile(`(?i)\b(STARTS|ENDS)\s+WITH\b`)               // not a WITH clause
+	reWhere      = regexp.MustCompile(`(?is)\bWHERE\b(.*?)(?:\b(?:RETURN|WITH|MATCH|UNWIND|CALL|ORDER)\b|$)`)
+	reProp       = regexp.MustCompile(`\b([A-Za-z_][A-Za-z0-9_]*)\.([A-Za-z_][A-Za-z0-9_]*)\b`) // u.haslaps
+	reNullable   = regexp.MustCompile(`(?i)\b(?:OR|XOR|CASE)\b`)
+	reNullSafe   = regexp.MustCompile(`(?is)\bcoalesce\s*\([^)]*\)|\bexists\s*\([^)]*\)|\b[A-Za-z_][A-Za-z0-9_]*\.[A-Za-z_][A-Za-z0-9_]*\s+IS\s+NULL\b`)
+)
+
+// PropRef is a property of the nodes with Label.
+type PropRef struct {
+	Label, Prop string
+}
+
+// FilterProps returns the node properties cypher's WHERE clauses cannot match
+// without: properties of label-bound variables in a WHERE made of AND-ed
+// conditions, where a missing property makes the condition null. WHEREs with
+// OR, XOR or CASE, and properties tested with IS NULL, exists() or inside
+// coalesce(), don't count.
+func FilterProps(cypher string) []PropRef {
+	labelOf := map[string]string{}
+	for _, m := range reBinding.FindAllStringSubmatch(cypher, -1) {
+		if _, ok := labelOf[m[1]]; !ok {
+			labelOf[m[1]] = m[2]
 		}
 	}
-	rels := reRel.FindAllStringSubmatch(cypher, -1)
-	for _, m := range rels {
-		r := strings.ToLower(m[1])
-		if r == "" {
+	seen := map[PropRef]bool{}
+	var out []PropRef
+	for _, m := range reWhere.FindAllStringSubmatch(reStartsEnds.ReplaceAllString(cypher, "${1}_WITH"), -1) {
+		cond := m[1]
+		if reNullable.MatchString(cond) {
 			continue
 		}
-		if _, ok := p.Rels[r]; !ok {
-			return false, fmt.Sprintf("missing relationship type: %s", m[1])
+		for _, pm := range reProp.FindAllStringSubmatch(reNullSafe.ReplaceAllString(cond, " "), -1) {
+			label, ok := labelOf[pm[1]]
+			if !ok {
+				continue
+			}
+			r := PropRef{Label: label, Prop: pm[2]}
+			if !seen[r] {
+				seen[r] = true
+				out = append(out, r)
+			}
 		}
 	}
-	return true, ""
+	return out
+}
+
+// RelTypes returns the distinct relationship types referenced by the Cypher, in order of appearance.
+func RelTypes(cypher string) []string {
+	seen := map[string]struct{}{}
+	out := make([]string, 0)
+	for _, m := range reRelList.FindAllStringSubmatch(cypher, -1) {
+		for _, r := range strings.Split(m[1], "|") {
+			r = strings.TrimSpace(r)
+			if r == "" {
+				continue
+			}
+			if _, ok := seen[r]; ok {
+				continue
+			}
+			seen[r] = struct{}{}
+			out = append(out, r)
+		}
+	}
+	return out
 }
@@ -0,0 +1,37 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bakw00ds/goBloodyEll/internal/queries"
+)
+
+func TestFilterProps(t *testing.T) {
+	cypher := `MATCH (c:Computer)-[:MemberOf*1..]->(g:Group)
+WHERE g.objectid ENDS WITH '-516' AND c.haslaps = false
+WITH collect(c) AS dcs
+MATCH (u:User)
+WHERE u.lastlogon IS NULL AND u.enabled
+RETURN u.name AS user, coalesce(u.displayname, u.name) AS display`
+	want := []PropRef{{"Group", "objectid"}, {"Computer", "haslaps"}, {"User", "enabled"}}
+	if got := FilterProps(cypher); !reflect.DeepEqual(got, want) {
+		t.Errorf("FilterProps = %v, want %v", got, want)
+	}
+	if got := FilterProps("MATCH (u:User) WHERE u.a OR u.b RETURN u"); len(got) != 0 {
+		t.Errorf("FilterProps with OR = %v, want none", got)
+	}
+
+	p := PresenceFromSummary(Summary{Labels: []string{"Computer", "Group"}, Rels: []string{"MemberOf"}})
+	p.Props = map[string]map[string]bool{"computer": {"haslaps": false}}
+	for q, want := range map[*queries.Query]string{
+		{Cypher: "MATCH (c:Computer) WHERE c.haslaps = false RETURN c.name", RequiresLabels: []string{"Computer"}, RequiresProps: []string{"Computer.haslaps"}}: "missing property: no Computer node has haslaps",
+		{Cypher: "MATCH (u:User) RETURN u.name", RequiresLabels: []string{"User"}}:                                                                              "missing label: User",
+		{Cypher: "MATCH (c:Computer)-[:HasSession]->(u) RETURN c.name", RequiresRels: []string{"HasSession"}}:                                                   "missing relationship type: HasSession",
+		{Cypher: "MATCH (n) WHERE n.name ENDS WITH ':User' RETURN n.name"}:                                                                                      "",
+	} {
+		if _, why := CanRun(*q, p); why != want {
+			t.Errorf("CanRun(%q) = %q, want %q", q.Cypher, why, want)
+		}
+	}
+}
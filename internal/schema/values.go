@@ -0,0 +1,143 @@
+package schema
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// ValueExamples is how many distinct example values SampleValues keeps per
+// property.
+const ValueExamples = 3
+
+// Value anonymization modes for SampleValues.
+const (
+	// ValuesHash replaces strings with a short HMAC-SHA-256 under a random
+	// per-run key: equal values stay equal within one run, and the output
+	// can't be matched against hashes of guessed names.
+	ValuesHash = "hash"
+	// ValuesMask keeps the first and last character of each word and masks
+	// the rest, so case, length and separators (e.g. "@DOMAIN" suffixes or
+	// OS version formats) stay visible.
+	ValuesMask = "mask"
+)
+
+// SampleValues returns, per label and property, up to ValueExamples distinct
+// values found on up to n of its nodes, anonymized with mode. Booleans and
+// numbers are shown as they are, since their type is often why a filter
+// matches nothing.
+func SampleValues(ctx context.Context, sess neo4j.SessionWithContext, labels []string, n int, mode string) (map[string]map[string][]string, error) {
+	out := make(map[string]map[string][]string, len(labels))
+	for _, l := range labels {
+		res, err := sess.Run(ctx, "MATCH (x:"+quoteName(l)+") WITH x LIMIT $n UNWIND keys(x) AS k WITH k, x[k] AS v WHERE v IS NOT NULL RETURN k, collect(DISTINCT v)[..$examples]",
+			map[string]any{"n": n, "examples": ValueExamples})
+		if err != nil {
+			return nil, err
+		}
+		props := map[string][]string{}
+		for res.Next(ctx) {
+			rec := res.Record()
+			vals, _ := rec.Values[1].([]any)
+			for _, v := range vals {
+				props[fmt.Sprint(rec.Values[0])] = append(props[fmt.Sprint(rec.Values[0])], Anonymize(v, mode))
+			}
+		}
+		if err := res.Err(); err != nil {
+			return nil, err
+		}
+		out[l] = props
+	}
+	return out, nil
+}
+
+// hashKey keys ValuesHash; it is random per run so hashes can't be
+// precomputed or compared across runs.
+var hashKey = func() []byte {
+	k := make([]byte, 32)
+	if _, err := rand.Read(k); err != nil {
+		panic(err)
+	}
+	return k
+}()
+
+// Anonymize renders a property value for display under mode (ValuesHash or
+// ValuesMask). Strings are quoted and lists bracketed, so "true" and true
+// can be told apart.
+func Anonymize(v any, mode string) string {
+	switch x := v.(type) {
+	case string:
+		if mode == ValuesHash {
+			h := hmac.New(sha256.New, hashKey)
+			h.Write([]byte(x))
+			return "hmac:" + hex.EncodeToString(h.Sum(nil)[:6])
+		}
+		return strconv.Quote(mask(x))
+	case []any:
+		parts := make([]string, len(x))
+		for i, e := range x {
+			parts[i] = Anonymize(e, mode)
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	case bool, int64, float64:
+		return fmt.Sprint(x)
+	}
+	return fmt.Sprintf("<%T>", v)
+}
+
+// mask keeps the first and last letter or digit of each run of them and
+// replaces the rest with '*'; runs of up to two characters are masked
+// entirely after the first.
+func mask(s string) string {
+	r := []rune(s)
+	for i := 0; i < len(r); {
+		if !isWordRune(r[i]) {
+			i++
+			continue
+		}
+		j := i
+		for j < len(r) && isWordRune(r[j]) {
+			j++
+		}
+		last := j - 1
+		if j-i <= 2 {
+			last = j
+		}
+		for k := i + 1; k < last; k++ {
+			r[k] = '*'
+		}
+		i = j
+	}
+	return string(r)
+}
+
+func isWordRune(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }
+
+// PrintValues prints SampleValues output under the text --schema summary.
+func PrintValues(values map[string]map[string][]string, mode string) {
+	fmt.Printf("== Example values (%s) ==\n", mode)
+	labels := make([]string, 0, len(values))
+	for l := range values {
+		labels = append(labels, l)
+	}
+	sort.Strings(labels)
+	for _, l := range labels {
+		fmt.Println(l)
+		props := make([]string, 0, len(values[l]))
+		for p := range values[l] {
+			props = append(props, p)
+		}
+		sort.Strings(props)
+		for _, p := range props {
+			fmt.Printf("  %s: %s\n", p, strings.Join(values[l][p], ", "))
+		}
+	}
+}
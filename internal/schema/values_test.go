@@ -0,0 +1,45 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnonymize(t *testing.T) {
+	for _, c := range []struct {
+		v          any
+		mode, want string
+	}{
+		{"Windows Server 2008 R2", ValuesMask, `"W*****s S****r 2**8 R*"`},
+		{"JDOE@CORP.LOCAL", ValuesMask, `"J**E@C**P.L***L"`},
+		{"true", ValuesMask, `"t**e"`},
+		{true, ValuesMask, "true"},
+		{int64(1700000000), ValuesHash, "1700000000"},
+		{[]any{"a", int64(1)}, ValuesMask, `["a", 1]`},
+	} {
+		if got := Anonymize(c.v, c.mode); got != c.want {
+			t.Errorf("Anonymize(%v, %s) = %s, want %s", c.v, c.mode, got, c.want)
+		}
+	}
+}
+
+func TestAnonymizeHashIsKeyed(t *testing.T) {
+	a, b := Anonymize("JDOE@CORP.LOCAL", ValuesHash), Anonymize("JDOE@CORP.LOCAL", ValuesHash)
+	if a != b || !strings.HasPrefix(a, "hmac:") || len(a) != len("hmac:")+12 {
+		t.Fatalf("hash = %s and %s, want the same 12-digit hmac", a, b)
+	}
+	// The plain SHA-256 prefix of the value must not appear: a hash anyone
+	// can recompute would reveal the value to a guess.
+	if strings.Contains(a, "93c256670b11") {
+		t.Fatalf("hash %s is the unkeyed SHA-256", a)
+	}
+	if Anonymize("ADMIN@CORP.LOCAL", ValuesHash) == a {
+		t.Fatal("different values hash alike")
+	}
+}
+
+func TestQuoteName(t *testing.T) {
+	if got, want := quoteName("Odd`Label"), "`Odd``Label`"; got != want {
+		t.Errorf("quoteName = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,49 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// azureUploader writes a block blob with a single Put Blob request, authorized
+// by a SAS token. The account comes from AZURE_STORAGE_ACCOUNT and the token
+// from AZURE_STORAGE_SAS_TOKEN (with or without the leading '?').
+type azureUploader struct {
+	account, container, blob string
+	sas                      string
+}
+
+func newAzureUploader(dest string) (*azureUploader, error) {
+	rest := strings.TrimPrefix(dest, "az://")
+	container, blob, ok := strings.Cut(rest, "/")
+	if !ok || container == "" || blob == "" {
+		return nil, fmt.Errorf("invalid azure destination %q (expected az://container/blob)", dest)
+	}
+	u := &azureUploader{
+		account:   os.Getenv("AZURE_STORAGE_ACCOUNT"),
+		container: container,
+		blob:      blob,
+		sas:       strings.TrimPrefix(os.Getenv("AZURE_STORAGE_SAS_TOKEN"), "?"),
+	}
+	if u.account == "" || u.sas == "" {
+		return nil, fmt.Errorf("azure upload requires AZURE_STORAGE_ACCOUNT and AZURE_STORAGE_SAS_TOKEN")
+	}
+	return u, nil
+}
+
+func (u *azureUploader) upload(ctx context.Context, f *os.File, size int64) error {
+	p := (&url.URL{Path: "/" + u.container + "/" + u.blob}).EscapedPath()
+	target := fmt.Sprintf("https://%s.blob.core.windows.net%s?%s", u.account, p, u.sas)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, target, f)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("x-ms-blob-type", "BlockBlob")
+	req.Header.Set("x-ms-version", "2021-08-06")
+	return doUpload(req)
+}
@@ -0,0 +1,174 @@
+package sink
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// s3Uploader PUTs a single object using AWS Signature V4. Credentials come from
+// AWS_ACCESS_KEY_ID / AWS_SECRET_ACCESS_KEY (+ AWS_SESSION_TOKEN), the region from
+// AWS_REGION (default us-east-1). AWS_ENDPOINT_URL selects an S3-compatible
+// endpoint (MinIO, Ceph) using path-style addressing.
+type s3Uploader struct {
+	bucket, key          string
+	region, endpoint     string
+	accessKey, secretKey string
+	sessionToken         string
+}
+
+func newS3Uploader(dest string) (*s3Uploader, error) {
+	rest := strings.TrimPrefix(dest, "s3://")
+	bucket, key, ok := strings.Cut(rest, "/")
+	if !ok || bucket == "" || key == "" {
+		return nil, fmt.Errorf("invalid s3 destination %q (expected s3://bucket/key)", dest)
+	}
+	u := &s3Uploader{
+		bucket:       bucket,
+		key:          key,
+		region:       firstEnv("AWS_REGION", "AWS_DEFAULT_REGION"),
+		endpoint:     os.Getenv("AWS_ENDPOINT_URL"),
+		accessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+		secretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		sessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if u.region == "" {
+		u.region = "us-east-1"
+	}
+	if u.accessKey == "" || u.secretKey == "" {
+		return nil, fmt.Errorf("s3 upload requires AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	}
+	return u, nil
+}
+
+func (u *s3Uploader) upload(ctx context.Context, f *os.File, size int64) error {
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+	payloadHash := hex.EncodeToString(h.Sum(nil))
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	p := "/" + u.key
+	base := fmt.Sprintf("https://%s.s3.%s.amazonaws.com", u.bucket, u.region)
+	if u.endpoint != "" {
+		base = strings.TrimRight(u.endpoint, "/")
+		p = "/" + u.bucket + p
+	}
+	target, err := url.Parse(base)
+	if err != nil {
+		return err
+	}
+	target.Path += p
+	target.RawPath = s3Escape(target.Path)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, target.String(), f)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if u.sessionToken != "" {
+		req.Header.Set("x-amz-security-token", u.sessionToken)
+	}
+	u.sign(req, time.Now().UTC(), payloadHash)
+	return doUpload(req)
+}
+
+// sign adds the SigV4 Authorization header to req, signing host and every
+// header already set on it plus x-amz-date and x-amz-content-sha256.
+func (u *s3Uploader) sign(req *http.Request, now time.Time, payloadHash string) {
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	signed := []string{"host"}
+	for k := range req.Header {
+		signed = append(signed, strings.ToLower(k))
+	}
+	sort.Strings(signed)
+	var canonHeaders strings.Builder
+	for _, k := range signed {
+		v := req.Header.Get(k)
+		if k == "host" {
+			v = req.URL.Host
+		}
+		canonHeaders.WriteString(k + ":" + strings.TrimSpace(v) + "\n")
+	}
+	canonical := strings.Join([]string{
+		req.Method,
+		s3Escape(req.URL.Path),
+		req.URL.RawQuery,
+		canonHeaders.String(),
+		strings.Join(signed, ";"),
+		payloadHash,
+	}, "\n")
+	scope := day + "/" + u.region + "/s3/aws4_request"
+	sum := sha256.Sum256([]byte(canonical))
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(sum[:])
+
+	k := hmacSHA256([]byte("AWS4"+u.secretKey), day)
+	k = hmacSHA256(k, u.region)
+	k = hmacSHA256(k, "s3")
+	k = hmacSHA256(k, "aws4_request")
+	sig := hex.EncodeToString(hmacSHA256(k, toSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		u.accessKey, scope, strings.Join(signed, ";"), sig))
+}
+
+// s3Escape percent-encodes an object path for the SigV4 canonical URI: every
+// byte except the RFC 3986 unreserved characters and '/' is escaped, which is
+// stricter than net/url and matches what S3 computes on its side.
+func s3Escape(p string) string {
+	var b strings.Builder
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	m := hmac.New(sha256.New, key)
+	m.Write([]byte(data))
+	return m.Sum(nil)
+}
+
+func doUpload(req *http.Request) error {
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+func firstEnv(keys ...string) string {
+	for _, k := range keys {
+		if v := os.Getenv(k); v != "" {
+			return v
+		}
+	}
+	return ""
+}
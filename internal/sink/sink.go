@@ -0,0 +1,108 @@
+// Package sink resolves output destinations. Local paths are written directly;
+// s3://bucket/key and az://container/blob are staged in a temp file and
+// uploaded when the writer is closed, so every report writer works unchanged
+// in ephemeral containers without persistent disk.
+package sink
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// IsRemote reports whether dest names an object-store location.
+func IsRemote(dest string) bool {
+	return strings.HasPrefix(dest, "s3://") || strings.HasPrefix(dest, "az://")
+}
+
+// Join appends name to a destination directory/prefix.
+func Join(dir, name string) string {
+	if IsRemote(dir) {
+		scheme, rest, _ := strings.Cut(dir, "://")
+		return scheme + "://" + path.Join(rest, name)
+	}
+	return filepath.Join(dir, name)
+}
+
+// MkdirAll creates a local output directory; remote prefixes need no setup.
+func MkdirAll(dir string) error {
+	if IsRemote(dir) {
+		return nil
+	}
+	return os.MkdirAll(dir, 0o755)
+}
+
+// Create opens dest for writing. For remote destinations the upload happens in
+// Close, which returns any upload error; call Abort first to skip it.
+func Create(dest string) (io.WriteCloser, error) {
+	var up uploader
+	switch {
+	case strings.HasPrefix(dest, "s3://"):
+		u, err := newS3Uploader(dest)
+		if err != nil {
+			return nil, err
+		}
+		up = u
+	case strings.HasPrefix(dest, "az://"):
+		u, err := newAzureUploader(dest)
+		if err != nil {
+			return nil, err
+		}
+		up = u
+	default:
+		return os.Create(dest)
+	}
+	tmp, err := os.CreateTemp("", "gobloodyell-upload-*")
+	if err != nil {
+		return nil, err
+	}
+	return &remoteFile{File: tmp, up: up, dest: dest}, nil
+}
+
+type uploader interface {
+	upload(ctx context.Context, f *os.File, size int64) error
+}
+
+// Abort marks w, when Create returned it for a remote destination, so that its
+// Close discards the staged data instead of uploading a partial object. It is
+// a no-op for local files and any other writer.
+func Abort(w io.Writer) {
+	if a, ok := w.(interface{ Abort() }); ok {
+		a.Abort()
+	}
+}
+
+type remoteFile struct {
+	*os.File
+	up      uploader
+	dest    string
+	aborted bool
+}
+
+func (r *remoteFile) Abort() { r.aborted = true }
+
+func (r *remoteFile) Close() error {
+	defer os.Remove(r.File.Name())
+	defer r.File.Close()
+	if r.aborted {
+		return nil
+	}
+	fi, err := r.File.Stat()
+	if err != nil {
+		return err
+	}
+	if _, err := r.File.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+	if err := r.up.upload(ctx, r.File, fi.Size()); err != nil {
+		return fmt.Errorf("upload %s: %w", r.dest, err)
+	}
+	return nil
+}
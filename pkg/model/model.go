@@ -0,0 +1,106 @@
+// Package model defines the public, versioned data model of goBloodyEll's JSON
+// output. External tooling should parse exports (and embed the library) through
+// these types rather than the internal ones.
+//
+// Compatibility: SchemaVersion follows semantic versioning. Within a major
+// version fields are only added, never renamed, removed or retyped; consumers
+// should ignore unknown fields. A breaking change bumps the major version.
+package model
+
+import "time"
+
+// SchemaVersion is the version of the JSON document layout described here.
+const SchemaVersion = "1.9.0"
+
+// Document is the top-level JSON export.
+type Document struct {
+	SchemaVersion string   `json:"schemaVersion"`
+	Run           *Run     `json:"run,omitempty"` // since 1.3.0
+	Results       []Output `json:"results"`
+}
+
+// Run describes the invocation that produced a document. Per-query timings
+// are in each Output's DurationMS.
+type Run struct {
+	ID            string    `json:"id"`
+	ToolVersion   string    `json:"toolVersion"`
+	CommandLine   string    `json:"commandLine,omitempty"`
+	ServerVersion string    `json:"serverVersion,omitempty"`
+	ServerEdition string    `json:"serverEdition,omitempty"`
+	Database      string    `json:"database"`
+	Target        string    `json:"target"`
+	Nodes         int64     `json:"nodes"`
+	Relationships int64     `json:"relationships"`
+	Started       time.Time `json:"started"`
+	Ended         time.Time `json:"ended"`
+	// Interrupted is set when the run was cancelled (Ctrl-C/SIGTERM) and
+	// the results are partial (since 1.6.0).
+	Interrupted bool `json:"interrupted,omitempty"`
+	// LabelCounts and RelationshipCounts are the nodes per label and
+	// relationships per type (since 1.8.0).
+	LabelCounts        map[string]int64 `json:"labelCounts,omitempty"`
+	RelationshipCounts map[string]int64 `json:"relationshipCounts,omitempty"`
+}
+
+// Query describes a single Cypher check.
+type Query struct {
+	ID           string   `json:"id"`
+	Title        string   `json:"title"`
+	Category     string   `json:"category"` // AD | EntraID | INFO
+	Severity     string   `json:"severity"` // critical | high | medium | low | info
+	SheetName    string   `json:"sheetName"`
+	Headers      []string `json:"headers"`
+	ColumnKeys   []string `json:"columnKeys"`
+	Description  string   `json:"description"`
+	FindingTitle string   `json:"findingTitle,omitempty"`
+	Remediation  string   `json:"remediation,omitempty"` // since 1.1.0
+	Cypher       string   `json:"cypher"`
+	// Params are the values bound to the Cypher's $parameters (since 1.5.0).
+	Params map[string]any `json:"params,omitempty"`
+}
+
+// Result is the raw tabular result of a query.
+type Result struct {
+	Columns   []string `json:"columns"`
+	Rows      [][]any  `json:"rows"`
+	Truncated bool     `json:"truncated,omitempty"`
+}
+
+// Status values for Output.Status.
+const (
+	StatusOK      = "ok"
+	StatusEmpty   = "empty"
+	StatusSkipped = "skipped"
+	StatusError   = "error"
+	// StatusNotRun marks a query that never started because the run was
+	// stopped first (since 1.7.0).
+	StatusNotRun = "not_run"
+)
+
+// Output is one query together with its outcome.
+type Output struct {
+	Query       Query    `json:"query"`
+	Status      string   `json:"status"`
+	Result      Result   `json:"result"`
+	RowKeys     []string `json:"rowKeys,omitempty"`
+	Severity    string   `json:"severity"`
+	RowSeverity []string `json:"rowSeverity,omitempty"`
+	Error       string   `json:"error,omitempty"`
+	SkipReason  string   `json:"skipReason,omitempty"`
+	Note        string   `json:"note,omitempty"`
+	// Assurance is set on finding queries that ran and returned no rows, so
+	// passed checks are visible (since 1.2.0).
+	Assurance  string `json:"assurance,omitempty"`
+	DurationMS int64  `json:"durationMs"`
+	// ReturnedRows is the row count the server returned, before client-side
+	// filtering such as --dedupe or --min-rows (since 1.4.0).
+	ReturnedRows int `json:"returnedRows"`
+	// BelowThreshold is set on finding queries whose rows --min-rows dropped;
+	// they returned rows, so they carry no Assurance (since 1.9.0).
+	BelowThreshold bool `json:"belowThreshold,omitempty"`
+}
+
+// Duration returns DurationMS as a time.Duration.
+func (o Output) Duration() time.Duration {
+	return time.Duration(o.DurationMS) * time.Millisecond
+}
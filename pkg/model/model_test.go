@@ -0,0 +1,85 @@
+package model
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func sampleDocument() Document {
+	started := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	return Document{
+		SchemaVersion: SchemaVersion,
+		Run: &Run{
+			ID: "run-1", ToolVersion: "dev", Database: "neo4j", Target: "bolt://127.0.0.1:7687",
+			Nodes: 10, Relationships: 20, Started: started, Ended: started.Add(time.Minute),
+			LabelCounts: map[string]int64{"User": 4},
+		},
+		Results: []Output{
+			{
+				Query:   Query{ID: "ad-kerberoastable", Title: "Kerberoastable users", Category: "AD", Severity: "high", Headers: []string{"User"}, ColumnKeys: []string{"user"}, Params: map[string]any{"limit": 5.0}},
+				Status:  StatusOK,
+				Result:  Result{Columns: []string{"user"}, Rows: [][]any{{"SVC_SQL@CORP.LOCAL"}, {nil}}},
+				RowKeys: []string{"k1", "k2"}, Severity: "high", DurationMS: 42, ReturnedRows: 2,
+			},
+			{Query: Query{ID: "ad-unconstrained"}, Status: StatusSkipped, SkipReason: "needs APOC", Result: Result{Columns: []string{}, Rows: [][]any{}}},
+			{Query: Query{ID: "ad-dcsync"}, Status: StatusEmpty, Assurance: "No results", Result: Result{Columns: []string{}, Rows: [][]any{}}},
+		},
+	}
+}
+
+func TestDocumentRoundTrip(t *testing.T) {
+	want := sampleDocument()
+	b, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Document
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("round trip changed the document:\n got %+v\nwant %+v", got, want)
+	}
+}
+
+// TestDocumentFieldNames pins the wire names consumers rely on; renaming any
+// of them is a breaking change that needs a major SchemaVersion bump.
+func TestDocumentFieldNames(t *testing.T) {
+	b, err := json.Marshal(sampleDocument())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var top map[string]json.RawMessage
+	var results []map[string]json.RawMessage
+	if err := json.Unmarshal(b, &top); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(top["results"], &results); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := keys(top), []string{"results", "run", "schemaVersion"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("document keys = %v, want %v", got, want)
+	}
+	want := map[int][]string{
+		0: {"durationMs", "query", "result", "returnedRows", "rowKeys", "severity", "status"},
+		1: {"durationMs", "query", "result", "returnedRows", "severity", "skipReason", "status"},
+		2: {"assurance", "durationMs", "query", "result", "returnedRows", "severity", "status"},
+	}
+	for i, w := range want {
+		if got := keys(results[i]); !reflect.DeepEqual(got, w) {
+			t.Errorf("results[%d] keys = %v, want %v", i, got, w)
+		}
+	}
+}
+
+func keys(m map[string]json.RawMessage) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,242 @@
+//go:build integration
+
+// Package integration runs the goBloodyEll CLI end to end against a real Neo4j
+// loaded with testdata/synthetic.cypher and asserts on the generated artifacts.
+//
+// By default a disposable neo4j:5 container is started with the docker CLI.
+// Set NEO4J_TEST_URI (and NEO4J_TEST_PASS) to use an existing empty database
+// instead. Run with:
+//
+//	go test -tags integration ./test/integration/
+package integration
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/xuri/excelize/v2"
+
+	"github.com/bakw00ds/goBloodyEll/pkg/model"
+)
+
+const testPass = "integration-pass"
+
+var (
+	neo4jURI  string
+	neo4jPass string
+	binPath   string
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(run(m))
+}
+
+func run(m *testing.M) int {
+	neo4jURI = os.Getenv("NEO4J_TEST_URI")
+	neo4jPass = os.Getenv("NEO4J_TEST_PASS")
+	if neo4jURI == "" {
+		stop, uri, err := startContainer()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "skipping integration tests: %v\n", err)
+			return 0
+		}
+		defer stop()
+		neo4jURI, neo4jPass = uri, testPass
+	}
+
+	tmp, err := os.MkdirTemp("", "gobloodyell-e2e-*")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer os.RemoveAll(tmp)
+	binPath = filepath.Join(tmp, "goBloodyEll")
+	build := exec.Command("go", "build", "-o", binPath, "github.com/bakw00ds/goBloodyEll/cmd/goBloodyEll")
+	build.Stdout, build.Stderr = os.Stderr, os.Stderr
+	if err := build.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "build CLI: %v\n", err)
+		return 1
+	}
+	if err := loadDataset(); err != nil {
+		fmt.Fprintf(os.Stderr, "load dataset: %v\n", err)
+		return 1
+	}
+	return m.Run()
+}
+
+func startContainer() (func(), string, error) {
+	if _, err := exec.LookPath("docker"); err != nil {
+		return nil, "", fmt.Errorf("docker not found")
+	}
+	out, err := exec.Command("docker", "run", "-d", "--rm", "-P",
+		"-e", "NEO4J_AUTH=neo4j/"+testPass, "neo4j:5").Output()
+	if err != nil {
+		return nil, "", fmt.Errorf("docker run: %w", err)
+	}
+	id := strings.TrimSpace(string(out))
+	stop := func() { _ = exec.Command("docker", "rm", "-f", id).Run() }
+	port, err := exec.Command("docker", "port", id, "7687/tcp").Output()
+	if err != nil {
+		stop()
+		return nil, "", fmt.Errorf("docker port: %w", err)
+	}
+	hostPort := strings.TrimSpace(strings.Split(string(port), "\n")[0])
+	hostPort = strings.Replace(hostPort, "0.0.0.0", "127.0.0.1", 1)
+	return stop, "bolt://" + hostPort, nil
+}
+
+func loadDataset() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+	driver, err := neo4j.NewDriverWithContext(neo4jURI, neo4j.BasicAuth("neo4j", neo4jPass, ""))
+	if err != nil {
+		return err
+	}
+	defer driver.Close(ctx)
+	// The container needs a while before bolt accepts connections.
+	for {
+		if err = driver.VerifyConnectivity(ctx); err == nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("neo4j not ready: %w", err)
+		case <-time.After(2 * time.Second):
+		}
+	}
+	b, err := os.ReadFile("testdata/synthetic.cypher")
+	if err != nil {
+		return err
+	}
+	sess := driver.NewSession(ctx, neo4j.SessionConfig{})
+	defer sess.Close(ctx)
+	for _, stmt := range strings.Split(string(b), ";\n") {
+		lines := make([]string, 0)
+		for _, l := range strings.Split(stmt, "\n") {
+			if !strings.HasPrefix(strings.TrimSpace(l), "//") {
+				lines = append(lines, l)
+			}
+		}
+		stmt = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(strings.Join(lines, "\n")), ";"))
+		if stmt == "" {
+			continue
+		}
+		if _, err := sess.Run(ctx, stmt, nil); err != nil {
+			return fmt.Errorf("%s: %w", stmt, err)
+		}
+	}
+	return nil
+}
+
+func runCLI(t *testing.T, args ...string) string {
+	t.Helper()
+	base := []string{"--neo4j-uri", neo4jURI, "-p", neo4jPass, "--no-color"}
+	cmd := exec.Command(binPath, append(base, args...)...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("goBloodyEll %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return string(out)
+}
+
+func TestJSON(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "out.json")
+	runCLI(t, "--format", "json", "--out", p, "--info")
+	b, err := os.ReadFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc model.Document
+	if err := json.Unmarshal(b, &doc); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if doc.SchemaVersion != model.SchemaVersion {
+		t.Fatalf("schemaVersion %q", doc.SchemaVersion)
+	}
+	byID := map[string]model.Output{}
+	for _, o := range doc.Results {
+		byID[o.Query.ID] = o
+		if len(o.Result.Rows) != len(o.RowKeys) {
+			t.Fatalf("%s: %d rows but %d row keys", o.Query.ID, len(o.Result.Rows), len(o.RowKeys))
+		}
+	}
+	if got := byID["ad-asrep-roastable"]; got.Status != model.StatusOK || len(got.Result.Rows) != 1 {
+		t.Fatalf("asrep: %+v", got)
+	}
+	if got := byID["ad-dcsync-rights"]; len(got.Result.Rows) != 2 {
+		t.Fatalf("dcsync rows: %v", got.Result.Rows)
+	}
+}
+
+func TestCSV(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "out.csv")
+	runCLI(t, "--format", "csv", "--out", p)
+	f, err := os.Open(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	recs, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recs[0][0] != "query_id" || recs[0][4] != "row_key" {
+		t.Fatalf("header: %v", recs[0])
+	}
+	found := false
+	for _, r := range recs[1:] {
+		if r[0] == "ad-password-not-required" && strings.Contains(strings.ToUpper(strings.Join(r, ",")), "BOB@CORP.LOCAL") {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("BOB@CORP.LOCAL missing from password-not-required rows")
+	}
+}
+
+func TestText(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "out.txt")
+	runCLI(t, "-t", p)
+	b, err := os.ReadFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := strings.ToUpper(string(b))
+	for _, want := range []string{"DOMAIN ADMINS", "ADMIN@CORP.LOCAL", "GLOSSARY & METHODOLOGY"} {
+		if !strings.Contains(s, want) {
+			t.Fatalf("text report missing %q", want)
+		}
+	}
+}
+
+func TestXLSX(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "out.xlsx")
+	runCLI(t, "-x", p)
+	f, err := excelize.OpenFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sheets := f.GetSheetList()
+	want := []string{"Summary", "Glossary", "All Users", "All Computers", "Domain Admins", "Domain Controllers"}
+	for i, w := range want {
+		if i >= len(sheets) || sheets[i] != w {
+			t.Fatalf("sheet order: got %v, want prefix %v", sheets, want)
+		}
+	}
+	rows, err := f.GetRows("Domain Controllers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(strings.ToUpper(fmt.Sprint(rows)), "DC01.CORP.LOCAL") {
+		t.Fatalf("Domain Controllers sheet missing DC01: %v", rows)
+	}
+}
@@ -0,0 +1,24 @@
+// Synthetic BloodHound-style dataset for end-to-end tests.
+// Statements are separated by ';' at end of line.
+CREATE (:Domain {name: 'CORP.LOCAL', objectid: 'S-1-5-21-1000', domainsid: 'S-1-5-21-1000'});
+CREATE (:Group {name: 'DOMAIN ADMINS@CORP.LOCAL', objectid: 'S-1-5-21-1000-512', highvalue: true});
+CREATE (:Group {name: 'DOMAIN CONTROLLERS@CORP.LOCAL', objectid: 'S-1-5-21-1000-516', highvalue: true});
+CREATE (:Group {name: 'DOMAIN USERS@CORP.LOCAL', objectid: 'S-1-5-21-1000-513'});
+CREATE (:Group {name: 'VPN USERS@CORP.LOCAL', objectid: 'S-1-5-21-1000-1200'});
+CREATE (:User {name: 'ADMIN@CORP.LOCAL', samaccountname: 'admin', userprincipalname: 'admin@corp.local', objectid: 'S-1-5-21-1000-500', enabled: true, hasspn: false, admincount: true, pwdlastset: 1500000000.0, pwdneverexpires: true});
+CREATE (:User {name: 'SVC_SQL@CORP.LOCAL', samaccountname: 'svc_sql', objectid: 'S-1-5-21-1000-1101', enabled: true, hasspn: true, serviceprincipalnames: ['MSSQLSvc/sql01.corp.local:1433'], pwdlastset: 1400000000.0});
+CREATE (:User {name: 'ALICE@CORP.LOCAL', samaccountname: 'alice', objectid: 'S-1-5-21-1000-1102', enabled: true, dontreqpreauth: true, description: 'temp pass Winter2024', pwdlastset: 1700000000.0});
+CREATE (:User {name: 'BOB@CORP.LOCAL', samaccountname: 'bob', objectid: 'S-1-5-21-1000-1103', enabled: true, passwordnotreqd: true, pwdlastset: 1700000000.0});
+CREATE (:Computer {name: 'DC01.CORP.LOCAL', objectid: 'S-1-5-21-1000-1001', operatingsystem: 'Windows Server 2022', unconstraineddelegation: true, enabled: true});
+CREATE (:Computer {name: 'WS01.CORP.LOCAL', objectid: 'S-1-5-21-1000-1002', operatingsystem: 'Windows 10 Enterprise', enabled: true});
+CREATE (:Computer {name: 'APP01.CORP.LOCAL', objectid: 'S-1-5-21-1000-1003', operatingsystem: 'Windows Server 2008 R2', unconstraineddelegation: true, enabled: true, description: 'web application server'});
+CREATE (:GPO {name: 'DEFAULT DOMAIN POLICY@CORP.LOCAL', objectid: 'GPO-1'});
+MATCH (u:User {samaccountname: 'admin'}), (g:Group {objectid: 'S-1-5-21-1000-512'}) CREATE (u)-[:MemberOf]->(g);
+MATCH (c:Computer {name: 'DC01.CORP.LOCAL'}), (g:Group {objectid: 'S-1-5-21-1000-516'}) CREATE (c)-[:MemberOf]->(g);
+MATCH (u:User), (g:Group {objectid: 'S-1-5-21-1000-513'}) CREATE (u)-[:MemberOf]->(g);
+MATCH (u:User {samaccountname: 'bob'}), (g:Group {objectid: 'S-1-5-21-1000-1200'}) CREATE (u)-[:MemberOf]->(g);
+MATCH (g:Group {objectid: 'S-1-5-21-1000-513'}), (c:Computer {name: 'WS01.CORP.LOCAL'}) CREATE (g)-[:AdminTo]->(c);
+MATCH (c:Computer {name: 'WS01.CORP.LOCAL'}), (u:User {samaccountname: 'admin'}) CREATE (c)-[:HasSession]->(u);
+MATCH (u:User {samaccountname: 'alice'}), (t:User {samaccountname: 'bob'}) CREATE (u)-[:GenericAll]->(t);
+MATCH (u:User {samaccountname: 'alice'}), (g:GPO) CREATE (u)-[:GenericWrite]->(g);
+MATCH (u:User {samaccountname: 'svc_sql'}), (d:Domain) CREATE (u)-[:GetChanges]->(d), (u)-[:GetChangesAll]->(d);